@@ -0,0 +1,366 @@
+/*
+Package allure implements a Cucumber formatter that writes Allure 2 results:
+a result and a container for every scenario that ran, and a file for every
+attachment.
+*/
+package allure
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cucumber/messages-go/v13"
+	"github.com/cucumber/query-go"
+	gio "github.com/gogo/protobuf/io"
+)
+
+type Formatter struct {
+	// ResultsDir is the directory the results are written to, created if it
+	// does not exist
+	ResultsDir string
+	// LinkPatterns are the URLs of issues and tests in a test management
+	// system by link type, "issue" or "tms", with a %s for the id. Without
+	// a pattern, the id is the URL.
+	LinkPatterns map[string]string
+	// NewId makes the UUIDs of results, containers and attachments, random
+	// ones when it is nil
+	NewId func() string
+
+	query        *query.Query
+	testCaseRuns map[string]*testCaseRun
+}
+
+// testCaseRun is the result of a started test case, and the container of
+// its hooks
+type testCaseRun struct {
+	*query.TestCaseRun
+	result      *Result
+	container   *Container
+	beforeHooks map[string]bool
+	steps       map[string]*Step
+}
+
+// ProcessMessages writes the results of the scenarios as they finish
+func (self *Formatter) ProcessMessages(reader gio.ReadCloser) error {
+	if self.NewId == nil {
+		self.NewId = messages.UUID{}.NewId
+	}
+	err := os.MkdirAll(self.ResultsDir, 0755)
+	if err != nil {
+		return err
+	}
+	self.query = query.New()
+	self.testCaseRuns = make(map[string]*testCaseRun)
+
+	for {
+		envelope := &messages.Envelope{}
+		err := reader.ReadMsg(envelope)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		self.query.Update(envelope)
+		switch m := envelope.Message.(type) {
+		case *messages.Envelope_TestCaseStarted:
+			self.startTestCase(m.TestCaseStarted)
+
+		case *messages.Envelope_TestStepStarted:
+			self.startTestStep(m.TestStepStarted)
+
+		case *messages.Envelope_TestStepFinished:
+			self.finishTestStep(m.TestStepFinished)
+
+		case *messages.Envelope_Attachment:
+			err = self.writeAttachment(m.Attachment)
+
+		case *messages.Envelope_TestCaseFinished:
+			err = self.finishTestCase(m.TestCaseFinished)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (self *Formatter) startTestCase(started *messages.TestCaseStarted) {
+	run := self.query.TestCaseRun(started.Id)
+	if run == nil || run.Pickle == nil {
+		return
+	}
+	pickle := run.Pickle
+
+	result := &Result{
+		UUID:        self.NewId(),
+		Name:        pickle.Name,
+		FullName:    pickle.Name,
+		Status:      Unknown,
+		Stage:       "running",
+		Start:       millis(started.Timestamp),
+		Parameters:  make([]*Parameter, 0),
+		Steps:       make([]*Step, 0),
+		Attachments: make([]*Attachment, 0),
+	}
+	result.Labels, result.Links = self.labelsAndLinks(pickle.Tags)
+	if feature := self.query.Feature(pickle.Uri); feature != nil {
+		result.FullName = feature.Name + ": " + pickle.Name
+		result.Labels = addDefaultLabel(result.Labels, "feature", feature.Name)
+		result.Labels = addDefaultLabel(result.Labels, "suite", feature.Name)
+	}
+	result.Labels = addDefaultLabel(result.Labels, "framework", "cucumber")
+
+	if len(pickle.AstNodeIds) > 0 {
+		scenarioId := pickle.AstNodeIds[0]
+		lastId := pickle.AstNodeIds[len(pickle.AstNodeIds)-1]
+		// The test case is the scenario, its history is that of the example
+		// row. AST node ids change from run to run, lines do not.
+		result.TestCaseID = hash(pickle.Uri, self.query.Location(scenarioId))
+		result.HistoryID = hash(pickle.Uri, self.query.Location(lastId))
+		if scenario := self.query.Scenario(scenarioId); scenario != nil {
+			result.Description = strings.TrimSpace(scenario.Description)
+		}
+		if row := self.query.TableRow(lastId); row != nil {
+			result.Parameters = parameters(self.query.Examples(lastId).GetTableHeader(), row)
+		}
+	}
+
+	beforeHooks := make(map[string]bool)
+	for _, testStep := range run.TestCase.TestSteps {
+		if testStep.HookId == "" {
+			break
+		}
+		beforeHooks[testStep.Id] = true
+	}
+
+	self.testCaseRuns[started.Id] = &testCaseRun{
+		TestCaseRun: run,
+		result:      result,
+		container: &Container{
+			UUID:     self.NewId(),
+			Name:     pickle.Name,
+			Children: []string{result.UUID},
+			Befores:  make([]*Step, 0),
+			Afters:   make([]*Step, 0),
+			Start:    result.Start,
+		},
+		beforeHooks: beforeHooks,
+		steps:       make(map[string]*Step),
+	}
+}
+
+// parameters are the values of an example row by the names in the header
+func parameters(header *messages.GherkinDocument_Feature_TableRow, row *messages.GherkinDocument_Feature_TableRow) []*Parameter {
+	parameters := make([]*Parameter, 0, len(row.Cells))
+	for i, cell := range row.Cells {
+		if i < len(header.GetCells()) {
+			parameters = append(parameters, &Parameter{Name: header.Cells[i].Value, Value: cell.Value})
+		}
+	}
+	return parameters
+}
+
+func (self *Formatter) startTestStep(started *messages.TestStepStarted) {
+	run := self.testCaseRuns[started.TestCaseStartedId]
+	if run == nil {
+		return
+	}
+	step := &Step{
+		Status:      Unknown,
+		Stage:       "running",
+		Start:       millis(started.Timestamp),
+		Steps:       make([]*Step, 0),
+		Attachments: make([]*Attachment, 0),
+		Parameters:  make([]*Parameter, 0),
+	}
+	run.steps[started.TestStepId] = step
+
+	if run.beforeHooks[started.TestStepId] {
+		step.Name = "Before hook"
+		run.container.Befores = append(run.container.Befores, step)
+		return
+	}
+	pickleStepId := self.query.TestStep(started.TestStepId).GetPickleStepId()
+	if pickleStepId == "" {
+		step.Name = "After hook"
+		run.container.Afters = append(run.container.Afters, step)
+		return
+	}
+	if pickleStep := self.query.PickleStep(pickleStepId); pickleStep != nil {
+		step.Name = pickleStep.Text
+		if len(pickleStep.AstNodeIds) > 0 && self.query.Step(pickleStep.AstNodeIds[0]) != nil {
+			step.Name = self.query.Step(pickleStep.AstNodeIds[0]).Keyword + pickleStep.Text
+		}
+	}
+	run.result.Steps = append(run.result.Steps, step)
+}
+
+func (self *Formatter) finishTestStep(finished *messages.TestStepFinished) {
+	run := self.testCaseRuns[finished.TestCaseStartedId]
+	if run == nil || finished.TestStepResult == nil {
+		return
+	}
+	step := run.steps[finished.TestStepId]
+	if step == nil {
+		return
+	}
+	result := finished.TestStepResult
+	step.Status = allureStatus(result.Status)
+	step.Stage = "finished"
+	step.Stop = millis(finished.Timestamp)
+	if result.Message != "" {
+		step.StatusDetails = statusDetails(result.Message)
+	}
+}
+
+func (self *Formatter) writeAttachment(attachment *messages.Attachment) error {
+	run := self.testCaseRuns[attachment.TestCaseStartedId]
+	if run == nil {
+		return nil
+	}
+
+	body := []byte(attachment.Body)
+	if attachment.ContentEncoding == messages.Attachment_BASE64 {
+		var err error
+		body, err = base64.StdEncoding.DecodeString(attachment.Body)
+		if err != nil {
+			return err
+		}
+	}
+	source := self.NewId() + "-attachment" + extension(attachment)
+	err := os.WriteFile(filepath.Join(self.ResultsDir, source), body, 0644)
+	if err != nil {
+		return err
+	}
+
+	name := attachment.FileName
+	if name == "" {
+		name = attachment.MediaType
+	}
+	allureAttachment := &Attachment{Name: name, Source: source, Type: attachment.MediaType}
+	if step := run.steps[attachment.TestStepId]; step != nil {
+		step.Attachments = append(step.Attachments, allureAttachment)
+	} else {
+		run.result.Attachments = append(run.result.Attachments, allureAttachment)
+	}
+	return nil
+}
+
+func (self *Formatter) finishTestCase(finished *messages.TestCaseFinished) error {
+	run := self.testCaseRuns[finished.TestCaseStartedId]
+	if run == nil {
+		return nil
+	}
+	delete(self.testCaseRuns, finished.TestCaseStartedId)
+
+	run.result.Status = allureStatus(run.Status)
+	if run.Message != "" {
+		run.result.StatusDetails = statusDetails(run.Message)
+	}
+	run.result.Stage = "finished"
+	run.result.Stop = millis(finished.Timestamp)
+	run.container.Stop = run.result.Stop
+
+	err := self.writeJSON(run.result.UUID+"-result.json", run.result)
+	if err != nil {
+		return err
+	}
+	return self.writeJSON(run.container.UUID+"-container.json", run.container)
+}
+
+func (self *Formatter) writeJSON(name string, value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(self.ResultsDir, name), b, 0644)
+}
+
+// allureStatus is the Allure status of a Cucumber status. Failed steps failed,
+// and steps that could not run because of the step definitions are broken.
+func allureStatus(status messages.TestStepFinished_TestStepResult_Status) string {
+	switch status {
+	case messages.TestStepFinished_TestStepResult_PASSED:
+		return Passed
+	case messages.TestStepFinished_TestStepResult_FAILED:
+		return Failed
+	case messages.TestStepFinished_TestStepResult_UNDEFINED,
+		messages.TestStepFinished_TestStepResult_AMBIGUOUS:
+		return Broken
+	case messages.TestStepFinished_TestStepResult_SKIPPED,
+		messages.TestStepFinished_TestStepResult_PENDING:
+		return Skipped
+	default:
+		return Unknown
+	}
+}
+
+// statusDetails has the first line of a message, and the message as trace
+func statusDetails(message string) *StatusDetails {
+	first := message
+	if i := strings.IndexByte(message, '\n'); i >= 0 {
+		first = message[:i]
+	}
+	return &StatusDetails{Message: strings.TrimSpace(first), Trace: message}
+}
+
+var extensions = map[string]string{
+	"application/json": ".json",
+	"image/gif":        ".gif",
+	"image/jpeg":       ".jpg",
+	"image/png":        ".png",
+	"image/svg+xml":    ".svg",
+	"text/csv":         ".csv",
+	"text/html":        ".html",
+	"text/plain":       ".txt",
+	"text/xml":         ".xml",
+	"video/mp4":        ".mp4",
+}
+
+// extension is the extension of the file name of an attachment, or of its
+// media type
+func extension(attachment *messages.Attachment) string {
+	if ext := filepath.Ext(attachment.FileName); ext != "" {
+		return ext
+	}
+	mediaType, _, err := mime.ParseMediaType(attachment.MediaType)
+	if err != nil {
+		return ""
+	}
+	if ext, ok := extensions[mediaType]; ok {
+		return ext
+	}
+	if strings.HasPrefix(mediaType, "text/") {
+		return ".txt"
+	}
+	if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+func hash(uri string, location *messages.Location) string {
+	line := uint32(0)
+	if location != nil {
+		line = location.Line
+	}
+	sum := md5.Sum([]byte(fmt.Sprintf("%s:%d", uri, line)))
+	return hex.EncodeToString(sum[:])
+}
+
+func millis(timestamp *messages.Timestamp) int64 {
+	if timestamp == nil {
+		return 0
+	}
+	return messages.TimestampToGoTime(*timestamp).UnixNano() / 1000000
+}
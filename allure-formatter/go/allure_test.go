@@ -0,0 +1,187 @@
+package allure
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/cucumber/messages-go/v13"
+	"github.com/cucumber/query-go/querytest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritesAResultAndContainerForEveryScenario(t *testing.T) {
+	dir := t.TempDir()
+	formatter := &Formatter{
+		ResultsDir:   dir,
+		LinkPatterns: map[string]string{"tms": "https://tms.example.com/tests/%s"},
+		NewId:        (&messages.Incrementing{}).NewId,
+	}
+	err := formatter.ProcessMessages(querytest.NewReader(t, querytest.FailingRun()))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"0-result.json", "1-container.json",
+		"2-result.json", "3-container.json",
+		"4-result.json", "5-container.json", "6-attachment.png",
+	}, files(t, dir))
+
+	result := &Result{}
+	readJSON(t, filepath.Join(dir, "4-result.json"), result)
+	require.Equal(t, &Result{
+		UUID:          "4",
+		HistoryID:     "6956c0a4ed73b7c2b67f699632f4501c",
+		TestCaseID:    "ea338963983ac7b6ae640e657c7a7643",
+		FullName:      "Eating: Eating 6",
+		Name:          "Eating 6",
+		Description:   "Cucumbers are good for you",
+		Status:        Failed,
+		StatusDetails: &StatusDetails{Message: "expected 0", Trace: "expected 0\n\tat eating.go:12"},
+		Stage:         "finished",
+		Start:         1597093203000,
+		Stop:          1597093203004,
+		Labels: []*Label{
+			{Name: "severity", Value: "critical"},
+			{Name: "feature", Value: "Eating"},
+			{Name: "suite", Value: "Eating"},
+			{Name: "framework", Value: "cucumber"},
+		},
+		Links: []*Link{
+			{Name: "ABC-1", URL: "https://tms.example.com/tests/ABC-1", Type: "tms"},
+		},
+		Parameters: []*Parameter{{Name: "count", Value: "6"}},
+		Steps: []*Step{
+			{
+				Name:        "Given I am hungry",
+				Status:      Passed,
+				Stage:       "finished",
+				Start:       1597093203001,
+				Stop:        1597093203002,
+				Steps:       []*Step{},
+				Attachments: []*Attachment{},
+				Parameters:  []*Parameter{},
+			},
+			{
+				Name:          "When I eat 6",
+				Status:        Failed,
+				StatusDetails: &StatusDetails{Message: "expected 0", Trace: "expected 0\n\tat eating.go:12"},
+				Stage:         "finished",
+				Start:         1597093203002,
+				Stop:          1597093203003,
+				Steps:         []*Step{},
+				Attachments:   []*Attachment{{Name: "screenshot.png", Source: "6-attachment.png", Type: "image/png"}},
+				Parameters:    []*Parameter{},
+			},
+		},
+		Attachments: []*Attachment{},
+	}, result)
+
+	container := &Container{}
+	readJSON(t, filepath.Join(dir, "5-container.json"), container)
+	require.Equal(t, "Eating 6", container.Name)
+	require.Equal(t, []string{"4"}, container.Children)
+	require.Len(t, container.Befores, 1)
+	require.Equal(t, "Before hook", container.Befores[0].Name)
+	require.Equal(t, Passed, container.Befores[0].Status)
+	require.Len(t, container.Afters, 1)
+	require.Equal(t, "After hook", container.Afters[0].Name)
+	require.Equal(t, Failed, container.Afters[0].Status)
+	require.Equal(t, "cleanup failed", container.Afters[0].StatusDetails.Message)
+
+	attachment, err := os.ReadFile(filepath.Join(dir, "6-attachment.png"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x89, 'P', 'N', 'G'}, attachment)
+}
+
+func TestKeepsTheHistoryOfExamplesApart(t *testing.T) {
+	dir := t.TempDir()
+	formatter := &Formatter{ResultsDir: dir, NewId: (&messages.Incrementing{}).NewId}
+	err := formatter.ProcessMessages(querytest.NewReader(t, querytest.FailingRun()))
+	require.NoError(t, err)
+
+	passed := &Result{}
+	readJSON(t, filepath.Join(dir, "2-result.json"), passed)
+	failed := &Result{}
+	readJSON(t, filepath.Join(dir, "4-result.json"), failed)
+	require.Equal(t, Passed, passed.Status)
+	require.Nil(t, passed.StatusDetails)
+	require.Equal(t, "80314db5a8a361328fe2dc5df83ac224", passed.HistoryID)
+	require.Equal(t, failed.TestCaseID, passed.TestCaseID)
+	require.Equal(t, []*Parameter{{Name: "count", Value: "5"}}, passed.Parameters)
+}
+
+func TestConvertsStatuses(t *testing.T) {
+	for status, expected := range map[messages.TestStepFinished_TestStepResult_Status]string{
+		messages.TestStepFinished_TestStepResult_UNKNOWN:   Unknown,
+		messages.TestStepFinished_TestStepResult_PASSED:    Passed,
+		messages.TestStepFinished_TestStepResult_SKIPPED:   Skipped,
+		messages.TestStepFinished_TestStepResult_PENDING:   Skipped,
+		messages.TestStepFinished_TestStepResult_UNDEFINED: Broken,
+		messages.TestStepFinished_TestStepResult_AMBIGUOUS: Broken,
+		messages.TestStepFinished_TestStepResult_FAILED:    Failed,
+	} {
+		require.Equal(t, expected, allureStatus(status), status.String())
+	}
+}
+
+func TestConvertsTagsToLabelsAndLinks(t *testing.T) {
+	formatter := &Formatter{LinkPatterns: map[string]string{"tms": "https://tms.example.com/tests/%s"}}
+	labels, links := formatter.labelsAndLinks([]*messages.Pickle_PickleTag{
+		{Name: "@epic=Checkout"},
+		{Name: "@allure.label.layer=api"},
+		{Name: "@allure.label.component=cart"},
+		{Name: "@tms=TC-7"},
+		{Name: "@issue=JIRA-1"},
+		{Name: "@link=https://example.com/spec"},
+		{Name: "@wip"},
+		{Name: "@retries=3"},
+	})
+	require.Equal(t, []*Label{
+		{Name: "epic", Value: "Checkout"},
+		{Name: "layer", Value: "api"},
+		{Name: "component", Value: "cart"},
+		{Name: "tag", Value: "wip"},
+		{Name: "tag", Value: "retries=3"},
+	}, labels)
+	require.Equal(t, []*Link{
+		{Name: "TC-7", URL: "https://tms.example.com/tests/TC-7", Type: "tms"},
+		{Name: "JIRA-1", URL: "JIRA-1", Type: "issue"},
+		{Name: "https://example.com/spec", URL: "https://example.com/spec", Type: "link"},
+	}, links)
+}
+
+func TestNamesAttachmentFilesAfterTheirMediaType(t *testing.T) {
+	for _, example := range []struct {
+		fileName  string
+		mediaType string
+		expected  string
+	}{
+		{"report.html", "text/html", ".html"},
+		{"", "text/plain", ".txt"},
+		{"", "text/x.cucumber.log+plain", ".txt"},
+		{"", "application/json; charset=utf-8", ".json"},
+		{"", "image/jpeg", ".jpg"},
+		{"", "nonsense", ""},
+	} {
+		require.Equal(t, example.expected, extension(&messages.Attachment{FileName: example.fileName, MediaType: example.mediaType}), example.mediaType)
+	}
+}
+
+func files(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readJSON(t *testing.T, path string, value interface{}) {
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(b, value))
+}
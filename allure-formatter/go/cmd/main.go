@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+
+	allureFormatter "github.com/cucumber/allure-formatter-go"
+	fio "github.com/cucumber/messages-go/v13/io"
+	gio "github.com/gogo/protobuf/io"
+)
+
+var formatFlag = flag.String("format", "protobuf", "output format")
+var resultsDirFlag = flag.String("results-dir", "allure-results", "directory to write the results to")
+var issuePatternFlag = flag.String("issue-pattern", "", "URL of issues tagged @issue=<id>, with a %s for the id")
+var tmsPatternFlag = flag.String("tms-pattern", "", "URL of tests tagged @tms=<id>, with a %s for the id")
+
+func main() {
+	flag.Parse()
+
+	linkPatterns := make(map[string]string)
+	if *issuePatternFlag != "" {
+		linkPatterns["issue"] = *issuePatternFlag
+	}
+	if *tmsPatternFlag != "" {
+		linkPatterns["tms"] = *tmsPatternFlag
+	}
+	af := &allureFormatter.Formatter{
+		ResultsDir:   *resultsDirFlag,
+		LinkPatterns: linkPatterns,
+	}
+	err := af.ProcessMessages(newReader(os.Stdin))
+	if err != nil {
+		log.Fatal("ERROR: ", err)
+	}
+}
+
+func newReader(in io.Reader) gio.ReadCloser {
+	var reader gio.ReadCloser
+	switch *formatFlag {
+	case "protobuf":
+		reader = gio.NewDelimitedReader(in, math.MaxInt32)
+	case "ndjson":
+		reader = fio.NewNdjsonReader(in)
+	default:
+		_, err := fmt.Fprintf(os.Stderr, "Unsupported format: %s\n", *formatFlag)
+		if err != nil {
+			log.Fatal("ERROR: ", err)
+		}
+	}
+	return reader
+}
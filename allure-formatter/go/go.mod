@@ -0,0 +1,21 @@
+module github.com/cucumber/allure-formatter-go
+
+require (
+	github.com/cucumber/messages-go/v13 v13.1.0
+	github.com/cucumber/query-go v0.0.0
+	github.com/gogo/protobuf v1.3.1
+	github.com/stretchr/testify v1.6.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/gofrs/uuid v3.3.0+incompatible // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+)
+
+replace github.com/cucumber/messages-go/v13 => ../../messages/go
+
+replace github.com/cucumber/query-go => ../../query/go
+
+go 1.23
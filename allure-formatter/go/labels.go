@@ -0,0 +1,73 @@
+package allure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cucumber/messages-go/v13"
+)
+
+// labelNames are the labels that can be set with a tag like
+// @severity=critical
+var labelNames = map[string]bool{
+	"epic":        true,
+	"feature":     true,
+	"story":       true,
+	"suite":       true,
+	"parentSuite": true,
+	"subSuite":    true,
+	"severity":    true,
+	"owner":       true,
+	"lead":        true,
+	"layer":       true,
+}
+
+// labelsAndLinks converts tags to labels and links. A tag like
+// @severity=critical sets one of the labelNames, and
+// @allure.label.<name>=<value> sets any label. @issue=<id> and @tms=<id>
+// link to an issue and a test in a test management system, using the
+// LinkPatterns, and @link=<url> links to a URL. Other tags are tag labels.
+func (self *Formatter) labelsAndLinks(tags []*messages.Pickle_PickleTag) ([]*Label, []*Link) {
+	labels := make([]*Label, 0)
+	links := make([]*Link, 0)
+	for _, tag := range tags {
+		name := strings.TrimPrefix(tag.Name, "@")
+		key, value := name, ""
+		if i := strings.IndexByte(name, '='); i > 0 {
+			key, value = name[:i], name[i+1:]
+		}
+		switch {
+		case value == "":
+			labels = append(labels, &Label{Name: "tag", Value: name})
+		case labelNames[key]:
+			labels = append(labels, &Label{Name: key, Value: value})
+		case strings.HasPrefix(key, "allure.label."):
+			labels = append(labels, &Label{Name: strings.TrimPrefix(key, "allure.label."), Value: value})
+		case key == "issue" || key == "tms":
+			links = append(links, &Link{Name: value, URL: self.linkURL(key, value), Type: key})
+		case key == "link":
+			links = append(links, &Link{Name: value, URL: value, Type: "link"})
+		default:
+			labels = append(labels, &Label{Name: "tag", Value: name})
+		}
+	}
+	return labels, links
+}
+
+func (self *Formatter) linkURL(linkType string, value string) string {
+	pattern, ok := self.LinkPatterns[linkType]
+	if !ok {
+		return value
+	}
+	return fmt.Sprintf(pattern, value)
+}
+
+// addDefaultLabel adds a label unless a tag set it
+func addDefaultLabel(labels []*Label, name string, value string) []*Label {
+	for _, label := range labels {
+		if label.Name == name {
+			return labels
+		}
+	}
+	return append(labels, &Label{Name: name, Value: value})
+}
@@ -0,0 +1,84 @@
+package allure
+
+// Result is an Allure test result, written to <uuid>-result.json
+type Result struct {
+	UUID          string         `json:"uuid"`
+	HistoryID     string         `json:"historyId"`
+	TestCaseID    string         `json:"testCaseId"`
+	FullName      string         `json:"fullName"`
+	Name          string         `json:"name"`
+	Description   string         `json:"description,omitempty"`
+	Status        string         `json:"status"`
+	StatusDetails *StatusDetails `json:"statusDetails,omitempty"`
+	Stage         string         `json:"stage"`
+	Start         int64          `json:"start"`
+	Stop          int64          `json:"stop"`
+	Labels        []*Label       `json:"labels"`
+	Links         []*Link        `json:"links"`
+	Parameters    []*Parameter   `json:"parameters"`
+	Steps         []*Step        `json:"steps"`
+	Attachments   []*Attachment  `json:"attachments"`
+}
+
+// Container groups the fixtures of results, written to
+// <uuid>-container.json. The fixtures of a scenario are its hooks.
+type Container struct {
+	UUID     string   `json:"uuid"`
+	Name     string   `json:"name"`
+	Children []string `json:"children"`
+	Befores  []*Step  `json:"befores"`
+	Afters   []*Step  `json:"afters"`
+	Start    int64    `json:"start"`
+	Stop     int64    `json:"stop"`
+}
+
+// Step is a step of a Result, or a fixture of a Container
+type Step struct {
+	Name          string         `json:"name"`
+	Status        string         `json:"status"`
+	StatusDetails *StatusDetails `json:"statusDetails,omitempty"`
+	Stage         string         `json:"stage"`
+	Start         int64          `json:"start"`
+	Stop          int64          `json:"stop"`
+	Steps         []*Step        `json:"steps"`
+	Attachments   []*Attachment  `json:"attachments"`
+	Parameters    []*Parameter   `json:"parameters"`
+}
+
+// StatusDetails explains why a result or step did not pass
+type StatusDetails struct {
+	Message string `json:"message,omitempty"`
+	Trace   string `json:"trace,omitempty"`
+}
+
+type Label struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type Link struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	Type string `json:"type"`
+}
+
+type Parameter struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Attachment refers to a file written next to the results
+type Attachment struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Type   string `json:"type"`
+}
+
+// Statuses of results and steps
+const (
+	Passed  = "passed"
+	Failed  = "failed"
+	Broken  = "broken"
+	Skipped = "skipped"
+	Unknown = "unknown"
+)
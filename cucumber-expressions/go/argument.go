@@ -1,10 +1,23 @@
 package cucumberexpressions
 
-import "fmt"
+import (
+	"fmt"
+	"reflect"
+	"unicode/utf8"
+)
 
 type Argument struct {
 	group         *Group
 	parameterType *ParameterType
+	// defaultGroup is the group of the default of a parameter in an
+	// optional that isn't in the text
+	defaultGroup *Group
+	// resolved are the values of the group with their templates resolved,
+	// or nil without template functions
+	resolved []*string
+	// start and end are the offsets in runes of the group in the text
+	start int
+	end   int
 }
 
 func BuildArguments(treeRegexp *TreeRegexp, text string, parameterTypes []*ParameterType) []*Argument {
@@ -19,14 +32,22 @@ func BuildArguments(treeRegexp *TreeRegexp, text string, parameterTypes []*Param
 	arguments := make([]*Argument, len(parameterTypes))
 	for i, parameterType := range parameterTypes {
 		arguments[i] = NewArgument(argGroups[i], parameterType)
+		if argGroups[i].Value() != nil {
+			arguments[i].start = utf8.RuneCountInString(text[:argGroups[i].Start()])
+			arguments[i].end = arguments[i].start + utf8.RuneCountInString(*argGroups[i].Value())
+		}
 	}
 	return arguments
 }
 
+// NewArgument returns an argument matched by a group. Its offsets in runes
+// are the ones in bytes of the group, as the text isn't known.
 func NewArgument(group *Group, parameterType *ParameterType) *Argument {
 	return &Argument{
 		group:         group,
 		parameterType: parameterType,
+		start:         group.Start(),
+		end:           group.End(),
 	}
 }
 
@@ -34,14 +55,122 @@ func (a *Argument) Group() *Group {
 	return a.group
 }
 
+// Start returns the offset in runes of the start of the argument in the
+// text, or -1 when it didn't match, such as a parameter in an optional
+func (a *Argument) Start() int {
+	return a.start
+}
+
+// End returns the offset in runes of the end of the argument in the text,
+// or -1 when it didn't match
+func (a *Argument) End() int {
+	return a.end
+}
+
+// ByteStart returns the offset in bytes of the start of the argument in
+// the text, or -1 when it didn't match
+func (a *Argument) ByteStart() int {
+	return a.group.Start()
+}
+
+// ByteEnd returns the offset in bytes of the end of the argument in the
+// text, or -1 when it didn't match
+func (a *Argument) ByteEnd() int {
+	return a.group.End()
+}
+
+// Name returns the name of the capture group the argument was matched by,
+// or an empty string for unnamed groups.
+func (a *Argument) Name() string {
+	return a.group.Name()
+}
+
 func (a *Argument) GetValue() interface{} {
-	values := a.group.Values()
+	values := a.values()
 	if values == nil {
 		return nil
 	}
 	return a.parameterType.Transform(values)
 }
 
+// values returns the values of the group of the argument, or of its
+// default when it didn't match
+func (a *Argument) values() []*string {
+	if a.group.Value() == nil && a.defaultGroup != nil {
+		return a.defaultGroup.Values()
+	}
+	if a.resolved != nil {
+		return a.resolved
+	}
+	return a.group.Values()
+}
+
+// transformedValue returns the value of an argument, or the error a
+// transform panicked with
+func transformedValue(argument *Argument) (value interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			transformErr, ok := r.(error)
+			if !ok {
+				panic(r)
+			}
+			err = transformErr
+		}
+	}()
+	return argument.GetValue(), nil
+}
+
+// anonymousValue converts the text of an argument of the anonymous
+// parameter type to valueType, like a type hint to Match would
+func anonymousValue(argument *Argument, valueType reflect.Type) (reflect.Value, error) {
+	values := argument.values()
+	if values == nil || values[0] == nil {
+		return reflect.Zero(valueType), nil
+	}
+	transformed, err := BuiltInParameterTransformer{}.Transform(*values[0], valueType)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	// Kinds transform to their built-in type, such as int for a named int type
+	return reflect.ValueOf(transformed).Convert(valueType), nil
+}
+
+// ArgumentByName returns the argument with a name, such as the argument of
+// {count:int} or of a named group (?P<count>\d+), or nil when there is
+// none.
+func ArgumentByName(arguments []*Argument, name string) *Argument {
+	for _, argument := range arguments {
+		if argument.Name() == name {
+			return argument
+		}
+	}
+	return nil
+}
+
+// argumentNames names arguments after their capture group or parameter
+// type, with 2, 3 and so on appended when a name is used more than once
+func argumentNames(arguments []*Argument) []string {
+	names := make([]string, len(arguments))
+	for i, argument := range arguments {
+		names[i] = argument.Name()
+		if names[i] == "" {
+			names[i] = argument.ParameterType().Name()
+		}
+	}
+	return numberNames(names)
+}
+
+// numberNames appends 2, 3 and so on to names that are used more than
+// once, like the parameter names of snippets and of RegularExpression
+func numberNames(names []string) []string {
+	result := make([]string, len(names))
+	usageByName := map[string]int{}
+	for i, name := range names {
+		result[i] = getParameterName(name, usageByName)
+	}
+	return result
+}
+
 func (a *Argument) ParameterType() *ParameterType {
 	return a.parameterType
 }
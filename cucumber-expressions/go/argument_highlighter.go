@@ -0,0 +1,121 @@
+package cucumberexpressions
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// ArgumentHighlighter renders step text with the matched arguments wrapped
+// in markup, so formatters don't have to slice the text by Group offsets
+// themselves.
+type ArgumentHighlighter struct {
+	before string
+	after  string
+	escape func(string) string
+}
+
+// NewAnsiArgumentHighlighter highlights arguments in bold for terminal output.
+func NewAnsiArgumentHighlighter() *ArgumentHighlighter {
+	return &ArgumentHighlighter{
+		before: "\x1b[1m",
+		after:  "\x1b[22m",
+		escape: func(s string) string { return s },
+	}
+}
+
+// NewHtmlArgumentHighlighter wraps arguments in a span with the given class.
+// The rest of the text is HTML-escaped.
+func NewHtmlArgumentHighlighter(className string) *ArgumentHighlighter {
+	return &ArgumentHighlighter{
+		before: `<span class="` + html.EscapeString(className) + `">`,
+		after:  "</span>",
+		escape: html.EscapeString,
+	}
+}
+
+// Highlight returns text with the markup of the highlighter around every
+// argument that matched. Arguments that didn't match, such as ones in
+// optionals, and arguments nested in others are left out.
+func (h *ArgumentHighlighter) Highlight(text string, arguments []*Argument) string {
+	var result strings.Builder
+	pos := 0
+	for _, argument := range arguments {
+		group := argument.Group()
+		if group == nil || group.Value() == nil || group.Start() < pos {
+			continue
+		}
+		result.WriteString(h.escape(text[pos:group.Start()]))
+		result.WriteString(h.before)
+		result.WriteString(h.escape(text[group.Start():group.End()]))
+		result.WriteString(h.after)
+		pos = group.End()
+	}
+	result.WriteString(h.escape(text[pos:]))
+	return result.String()
+}
+
+// ArgumentMarkers returns a line that, when printed under text in a
+// terminal, places marker under every column occupied by an argument.
+// Wide (e.g. CJK) characters occupy two columns, combining marks none.
+func ArgumentMarkers(text string, arguments []*Argument, marker rune) string {
+	var result strings.Builder
+	pos := 0
+	for _, argument := range arguments {
+		group := argument.Group()
+		if group == nil || group.Value() == nil || group.Start() < pos {
+			continue
+		}
+		result.WriteString(strings.Repeat(" ", DisplayWidth(text[pos:group.Start()])))
+		width := DisplayWidth(text[group.Start():group.End()])
+		if width == 0 {
+			width = 1
+		}
+		result.WriteString(strings.Repeat(string(marker), width))
+		pos = group.End()
+	}
+	return result.String()
+}
+
+// DisplayWidth returns the number of terminal columns needed to print s.
+func DisplayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+func runeWidth(r rune) int {
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	for _, wideRange := range wideRuneRanges {
+		if r >= wideRange[0] && r <= wideRange[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// East Asian Wide and Fullwidth blocks, plus the emoji and symbol blocks
+// terminals render as two columns. The ranges are sorted.
+var wideRuneRanges = [][2]rune{
+	{0x1100, 0x115F},
+	{0x2600, 0x27BF},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x1F300, 0x1F64F},
+	{0x1F680, 0x1F6FF},
+	{0x1F900, 0x1F9FF},
+	{0x1FA70, 0x1FAFF},
+	{0x20000, 0x3FFFD},
+}
@@ -0,0 +1,60 @@
+package cucumberexpressions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgumentHighlighter(t *testing.T) {
+	match := func(t *testing.T, expr string, text string) []*Argument {
+		expression, err := NewCucumberExpression(expr, NewParameterTypeRegistry())
+		require.NoError(t, err)
+		args, err := expression.Match(text)
+		require.NoError(t, err)
+		return args
+	}
+
+	t.Run("highlights arguments with ANSI escapes", func(t *testing.T) {
+		text := "I have 42 cukes in my belly"
+		args := match(t, "I have {int} cukes in my {word}", text)
+		require.Equal(
+			t,
+			"I have \x1b[1m42\x1b[22m cukes in my \x1b[1mbelly\x1b[22m",
+			NewAnsiArgumentHighlighter().Highlight(text, args),
+		)
+	})
+
+	t.Run("highlights arguments with HTML spans and escapes the text", func(t *testing.T) {
+		text := `Tom & Jerry say "hello"`
+		args := match(t, "Tom & Jerry say {string}", text)
+		require.Equal(
+			t,
+			`Tom &amp; Jerry say <span class="param">&#34;hello&#34;</span>`,
+			NewHtmlArgumentHighlighter("param").Highlight(text, args),
+		)
+	})
+
+	t.Run("places markers under arguments", func(t *testing.T) {
+		text := "I have 42 cukes"
+		args := match(t, "I have {int} cukes", text)
+		require.Equal(t, "       ^^", ArgumentMarkers(text, args, '^'))
+	})
+
+	t.Run("places markers using display width of wide characters", func(t *testing.T) {
+		text := "我有 42 黄瓜"
+		args := match(t, "我有 {int} {word}", text)
+		require.Equal(t, "     ^^ ^^^^", ArgumentMarkers(text, args, '^'))
+	})
+
+	t.Run("places markers after emoji and symbols", func(t *testing.T) {
+		text := "🚀 ☀ launch 3 rockets"
+		args := match(t, "🚀 ☀ launch {int} rockets", text)
+		require.Equal(t, "             ^", ArgumentMarkers(text, args, '^'))
+		require.Equal(t, 4, DisplayWidth("🚀🪴"))
+	})
+
+	t.Run("ignores combining marks when computing display width", func(t *testing.T) {
+		require.Equal(t, 4, DisplayWidth("café"))
+	})
+}
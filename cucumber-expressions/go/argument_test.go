@@ -18,4 +18,15 @@ func TestArgument(t *testing.T) {
 
 		require.Equal(t, argument.ParameterType().name, "string")
 	})
+
+	t.Run("exposes the offsets of arguments in the text", func(t *testing.T) {
+		expression, err := NewCucumberExpressionWithDefaults("{word} has {int} cukes( in {word})", NewParameterTypeRegistry(), "total")
+		require.NoError(t, err)
+		args, err := expression.Match("Ærøskøbing has 42 cukes")
+		require.NoError(t, err)
+
+		require.Equal(t, []int{0, 10, 0, 13}, []int{args[0].Start(), args[0].End(), args[0].ByteStart(), args[0].ByteEnd()})
+		require.Equal(t, []int{15, 17, 18, 20}, []int{args[1].Start(), args[1].End(), args[1].ByteStart(), args[1].ByteEnd()})
+		require.Equal(t, []int{-1, -1, -1, -1}, []int{args[2].Start(), args[2].End(), args[2].ByteStart(), args[2].ByteEnd()})
+	})
 }
@@ -0,0 +1,264 @@
+package cucumberexpressions
+
+import (
+	"encoding/json"
+	"iter"
+	"strings"
+	"unicode"
+)
+
+const escapeCharacter rune = '\\'
+const alternationCharacter rune = '/'
+const beginParameterCharacter rune = '{'
+const endParameterCharacter rune = '}'
+const beginOptionalCharacter rune = '('
+const endOptionalCharacter rune = ')'
+
+// NodeType is the type of a node of a parsed Cucumber Expression
+type NodeType string
+
+const (
+	TextNode        NodeType = "TEXT_NODE"
+	OptionalNode    NodeType = "OPTIONAL_NODE"
+	AlternationNode NodeType = "ALTERNATION_NODE"
+	AlternativeNode NodeType = "ALTERNATIVE_NODE"
+	ParameterNode   NodeType = "PARAMETER_NODE"
+	ExpressionNode  NodeType = "EXPRESSION_NODE"
+)
+
+// Node is a node of the tree Parse builds from a Cucumber Expression.
+// Start and End are the offsets in runes of the part of the expression
+// the node was parsed from, and ByteStart and ByteEnd the offsets in bytes.
+// Text nodes have a Token, the unescaped text, and the other nodes have
+// child Nodes.
+type Node struct {
+	NodeType  NodeType
+	Start     int
+	End       int
+	ByteStart int
+	ByteEnd   int
+	Token     string
+	Nodes     []Node
+}
+
+// Text returns the unescaped text of the node and its children
+func (n Node) Text() string {
+	builder := strings.Builder{}
+	builder.WriteString(n.Token)
+	for _, child := range n.Nodes {
+		builder.WriteString(child.Text())
+	}
+	return builder.String()
+}
+
+// Source returns the expression text of the node. Text is escaped where
+// it would otherwise not parse as text, so the source of a node parses to
+// the same tree, apart from offsets and how text is split into nodes.
+func (n Node) Source() string {
+	builder := strings.Builder{}
+	n.writeSource(&builder)
+	return builder.String()
+}
+
+func (n Node) writeSource(builder *strings.Builder) {
+	switch n.NodeType {
+	case TextNode:
+		builder.WriteString(escapeText(n.Token))
+	case OptionalNode:
+		builder.WriteRune(beginOptionalCharacter)
+		n.writeChildrenSource(builder)
+		builder.WriteRune(endOptionalCharacter)
+	case ParameterNode:
+		// Parameter names are not escaped
+		builder.WriteRune(beginParameterCharacter)
+		builder.WriteString(n.Text())
+		builder.WriteRune(endParameterCharacter)
+	case AlternationNode:
+		for i, alternative := range n.Nodes {
+			if i > 0 {
+				builder.WriteRune(alternationCharacter)
+			}
+			alternative.writeSource(builder)
+		}
+	default:
+		n.writeChildrenSource(builder)
+	}
+}
+
+func (n Node) writeChildrenSource(builder *strings.Builder) {
+	for _, child := range n.Nodes {
+		child.writeSource(builder)
+	}
+}
+
+// escapeText escapes the characters of text that would start an optional,
+// a parameter or an alternative
+func escapeText(text string) string {
+	builder := strings.Builder{}
+	for _, r := range text {
+		if canEscape(r) {
+			builder.WriteRune(escapeCharacter)
+			builder.WriteRune(escapeCharacter)
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// jsonNode is the JSON format of nodes of the other Cucumber Expressions
+// implementations: text nodes have a token, the others have nodes. The
+// offsets in bytes are only there when they differ from the ones in runes,
+// after text that isn't ASCII. The keys are in the order of the fields.
+type jsonNode struct {
+	NodeType  NodeType `json:"type"`
+	Start     int      `json:"start"`
+	End       int      `json:"end"`
+	ByteStart *int     `json:"byteStart,omitempty"`
+	ByteEnd   *int     `json:"byteEnd,omitempty"`
+	Token     *string  `json:"token,omitempty"`
+	Nodes     *[]Node  `json:"nodes,omitempty"`
+}
+
+// MarshalJSON returns the JSON of a node, with the keys type, start, end,
+// byteStart, byteEnd and token or nodes in this order. Values are never
+// null: text nodes have a token and the other nodes have nodes, which is
+// [] when they have none, and byteStart and byteEnd are left out when they
+// are the same as start and end.
+func (n Node) MarshalJSON() ([]byte, error) {
+	result := jsonNode{NodeType: n.NodeType, Start: n.Start, End: n.End}
+	if n.ByteStart != n.Start {
+		result.ByteStart = &n.ByteStart
+	}
+	if n.ByteEnd != n.End {
+		result.ByteEnd = &n.ByteEnd
+	}
+	if n.NodeType == TextNode {
+		result.Token = &n.Token
+	} else {
+		nodes := n.Nodes
+		if nodes == nil {
+			nodes = []Node{}
+		}
+		result.Nodes = &nodes
+	}
+	return json.Marshal(result)
+}
+
+func (n *Node) UnmarshalJSON(data []byte) error {
+	var result jsonNode
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	*n = Node{NodeType: result.NodeType, Start: result.Start, End: result.End, ByteStart: result.Start, ByteEnd: result.End}
+	if result.ByteStart != nil {
+		n.ByteStart = *result.ByteStart
+	}
+	if result.ByteEnd != nil {
+		n.ByteEnd = *result.ByteEnd
+	}
+	if result.Token != nil {
+		n.Token = *result.Token
+	}
+	if result.Nodes != nil {
+		n.Nodes = *result.Nodes
+	}
+	return nil
+}
+
+// Walk calls visit for node and then, in order, for its descendants. When
+// visit returns false, the descendants of that node are skipped.
+func Walk(node Node, visit func(Node) bool) {
+	if !visit(node) {
+		return
+	}
+	for _, child := range node.Nodes {
+		Walk(child, visit)
+	}
+}
+
+// AllNodes yields node and then, in order, its descendants, until the
+// caller stops ranging over them.
+func AllNodes(node Node) iter.Seq[Node] {
+	return func(yield func(Node) bool) {
+		yieldNodes(node, yield)
+	}
+}
+
+// yieldNodes yields node and its descendants, and returns false when the
+// caller stopped
+func yieldNodes(node Node, yield func(Node) bool) bool {
+	if !yield(node) {
+		return false
+	}
+	for _, child := range node.Nodes {
+		if !yieldNodes(child, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// TokenType is the type of a token of a Cucumber Expression
+type TokenType string
+
+const (
+	StartOfLineToken    TokenType = "START_OF_LINE"
+	EndOfLineToken      TokenType = "END_OF_LINE"
+	WhiteSpaceToken     TokenType = "WHITE_SPACE"
+	BeginOptionalToken  TokenType = "BEGIN_OPTIONAL"
+	EndOptionalToken    TokenType = "END_OPTIONAL"
+	BeginParameterToken TokenType = "BEGIN_PARAMETER"
+	EndParameterToken   TokenType = "END_PARAMETER"
+	AlternationToken    TokenType = "ALTERNATION"
+	TextToken           TokenType = "TEXT"
+)
+
+// Token is a token of the list Tokenize splits a Cucumber Expression into.
+// Start and End are the offsets in runes of the part of the expression the
+// token was read from, including escape characters, and ByteStart and
+// ByteEnd the offsets in bytes. Text is the unescaped text.
+type Token struct {
+	Text      string    `json:"text"`
+	TokenType TokenType `json:"type"`
+	Start     int       `json:"start"`
+	End       int       `json:"end"`
+	ByteStart int       `json:"byteStart"`
+	ByteEnd   int       `json:"byteEnd"`
+}
+
+func isWhiteSpace(r rune) bool {
+	return unicode.Is(unicode.White_Space, r)
+}
+
+// canEscape tells if a double escape character escapes r
+func canEscape(r rune) bool {
+	switch r {
+	case beginOptionalCharacter, beginParameterCharacter, alternationCharacter:
+		return true
+	}
+	return false
+}
+
+// isSpecial tells if r has a meaning in expressions besides text
+func isSpecial(r rune) bool {
+	return r == escapeCharacter || typeOf(r) != TextToken
+}
+
+func typeOf(r rune) TokenType {
+	if isWhiteSpace(r) {
+		return WhiteSpaceToken
+	}
+	switch r {
+	case alternationCharacter:
+		return AlternationToken
+	case beginParameterCharacter:
+		return BeginParameterToken
+	case endParameterCharacter:
+		return EndParameterToken
+	case beginOptionalCharacter:
+		return BeginOptionalToken
+	case endOptionalCharacter:
+		return EndOptionalToken
+	}
+	return TextToken
+}
@@ -0,0 +1,209 @@
+package cucumberexpressions
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAst(t *testing.T) {
+	t.Run("walks nodes depth first", func(t *testing.T) {
+		ast, err := Parse("a {int} (b(c)) d/e")
+		require.NoError(t, err)
+
+		var visited []string
+		Walk(ast, func(node Node) bool {
+			visited = append(visited, string(node.NodeType)+":"+node.Token)
+			return true
+		})
+		require.Equal(t, []string{
+			"EXPRESSION_NODE:",
+			"TEXT_NODE:a",
+			"TEXT_NODE: ",
+			"PARAMETER_NODE:",
+			"TEXT_NODE:int",
+			"TEXT_NODE: ",
+			"OPTIONAL_NODE:",
+			"TEXT_NODE:b",
+			"OPTIONAL_NODE:",
+			"TEXT_NODE:c",
+			"TEXT_NODE: ",
+			"ALTERNATION_NODE:",
+			"ALTERNATIVE_NODE:",
+			"TEXT_NODE:d",
+			"ALTERNATIVE_NODE:",
+			"TEXT_NODE:e",
+		}, visited)
+	})
+
+	t.Run("skips the children of nodes when visit returns false", func(t *testing.T) {
+		ast, err := Parse("{int} (a {b}) {string}")
+		require.NoError(t, err)
+
+		var parameters []string
+		Walk(ast, func(node Node) bool {
+			if node.NodeType == ParameterNode {
+				parameters = append(parameters, node.Text())
+			}
+			return node.NodeType != OptionalNode
+		})
+		require.Equal(t, []string{"int", "string"}, parameters)
+	})
+
+	t.Run("yields nodes depth first", func(t *testing.T) {
+		ast, err := Parse("{int} (a {b}) {string}")
+		require.NoError(t, err)
+
+		var walked []Node
+		Walk(ast, func(node Node) bool {
+			walked = append(walked, node)
+			return true
+		})
+		var yielded []Node
+		for node := range AllNodes(ast) {
+			yielded = append(yielded, node)
+		}
+		require.Equal(t, walked, yielded)
+
+		var first string
+		for node := range AllNodes(ast) {
+			if node.NodeType == ParameterNode {
+				first = node.Text()
+				break
+			}
+		}
+		require.Equal(t, "int", first)
+	})
+
+	t.Run("marshals nodes like the other implementations", func(t *testing.T) {
+		ast, err := Parse("a/b {int} {}")
+		require.NoError(t, err)
+
+		data, err := json.Marshal(ast)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"type": "EXPRESSION_NODE", "start": 0, "end": 12, "nodes": [
+				{"type": "ALTERNATION_NODE", "start": 0, "end": 3, "nodes": [
+					{"type": "ALTERNATIVE_NODE", "start": 0, "end": 1, "nodes": [
+						{"type": "TEXT_NODE", "start": 0, "end": 1, "token": "a"}
+					]},
+					{"type": "ALTERNATIVE_NODE", "start": 2, "end": 3, "nodes": [
+						{"type": "TEXT_NODE", "start": 2, "end": 3, "token": "b"}
+					]}
+				]},
+				{"type": "TEXT_NODE", "start": 3, "end": 4, "token": " "},
+				{"type": "PARAMETER_NODE", "start": 4, "end": 9, "nodes": [
+					{"type": "TEXT_NODE", "start": 5, "end": 8, "token": "int"}
+				]},
+				{"type": "TEXT_NODE", "start": 9, "end": 10, "token": " "},
+				{"type": "PARAMETER_NODE", "start": 10, "end": 12, "nodes": []}
+			]
+		}`, string(data))
+	})
+
+	t.Run("marshals byte offsets that differ from the rune offsets", func(t *testing.T) {
+		ast, err := Parse("ñ")
+		require.NoError(t, err)
+
+		data, err := json.Marshal(ast)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"type": "EXPRESSION_NODE", "start": 0, "end": 1, "byteEnd": 2, "nodes": [
+				{"type": "TEXT_NODE", "start": 0, "end": 1, "byteEnd": 2, "token": "ñ"}
+			]
+		}`, string(data))
+		var unmarshalled Node
+		require.NoError(t, json.Unmarshal(data, &unmarshalled))
+		require.Equal(t, ast, unmarshalled)
+	})
+
+	t.Run("marshals keys in a fixed order and no nulls", func(t *testing.T) {
+		data, err := json.Marshal(Node{NodeType: ExpressionNode, Start: 0, End: 1, ByteStart: 0, ByteEnd: 2})
+		require.NoError(t, err)
+		require.Equal(t, `{"type":"EXPRESSION_NODE","start":0,"end":1,"byteEnd":2,"nodes":[]}`, string(data))
+
+		data, err = json.Marshal(Node{NodeType: TextNode, Start: 1, End: 2, ByteStart: 2, ByteEnd: 3})
+		require.NoError(t, err)
+		require.Equal(t, `{"type":"TEXT_NODE","start":1,"end":2,"byteStart":2,"byteEnd":3,"token":""}`, string(data))
+	})
+
+	t.Run("unmarshals marshalled nodes", func(t *testing.T) {
+		ast, err := Parse("I have {int} cuke(s) in my belly/stomach")
+		require.NoError(t, err)
+
+		data, err := json.Marshal(ast)
+		require.NoError(t, err)
+		var unmarshalled Node
+		require.NoError(t, json.Unmarshal(data, &unmarshalled))
+		require.Equal(t, ast, unmarshalled)
+	})
+	for _, expression := range []string{
+		"",
+		"three blind mice",
+		"I have {int} cuke(s) in my belly/stomach",
+		"cuke((s))",
+		"a) b}",
+		`\\(\\{a}\\/ b\c`,
+		"(a/b) c/d(e)",
+		"{count:int} {}",
+		"a \\\n b",
+	} {
+		expression := expression
+		t.Run("prints the source of "+expression, func(t *testing.T) {
+			ast, err := Parse(expression)
+			require.NoError(t, err)
+			require.Equal(t, expression, ast.Source())
+		})
+	}
+
+	t.Run("escapes text that would not parse as text", func(t *testing.T) {
+		ast := Node{ExpressionNode, 0, 0, 0, 0, "", []Node{
+			{TextNode, 0, 0, 0, 0, "a (b) {c} d/e", nil},
+			{OptionalNode, 0, 0, 0, 0, "", []Node{
+				{TextNode, 0, 0, 0, 0, "f", nil},
+			}},
+		}}
+		source := ast.Source()
+		require.Equal(t, `a \\(b) \\{c} d\\/e(f)`, source)
+
+		reparsed, err := Parse(source)
+		require.NoError(t, err)
+		require.Equal(t, ast.Text(), reparsed.Text())
+		require.Equal(t, withoutText(ast), withoutText(reparsed))
+	})
+
+	t.Run("prints renamed parameter types", func(t *testing.T) {
+		ast, err := Parse("I have {int} cuke(s)")
+		require.NoError(t, err)
+		ast.Nodes[4].Nodes[0].Token = "integer"
+		require.Equal(t, "I have {integer} cuke(s)", ast.Source())
+	})
+	for _, expression := range []string{
+		"{int",
+		"a/b/ c",
+		"(a/b/)",
+		`\\({int})`,
+	} {
+		expression := expression
+		t.Run("reparses the source of "+expression, func(t *testing.T) {
+			ast, err := Parse(expression)
+			require.NoError(t, err)
+			reparsed, err := Parse(ast.Source())
+			require.NoError(t, err)
+			require.Equal(t, ast.Text(), reparsed.Text())
+			require.Equal(t, withoutText(ast), withoutText(reparsed))
+		})
+	}
+}
+
+// withoutText returns the node without offsets and text nodes, as the
+// source of text may be split into other text nodes
+func withoutText(node Node) Node {
+	result := Node{NodeType: node.NodeType}
+	for _, child := range node.Nodes {
+		if child.NodeType != TextNode {
+			result.Nodes = append(result.Nodes, withoutText(child))
+		}
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package cucumberexpressions
+
+import (
+	"reflect"
+	"regexp"
+)
+
+// DefaultTrueWords are the words {bool} transforms to true
+var DefaultTrueWords = []string{"true", "yes", "enabled"}
+
+// DefaultFalseWords are the words {bool} transforms to false
+var DefaultFalseWords = []string{"false", "no", "disabled"}
+
+// AddBoolWords makes {bool} also match and transform synonyms of true and
+// false, such as "on" and "off". Expressions compiled before keep matching
+// the words {bool} had then.
+func (p *ParameterTypeRegistry) AddBoolWords(trueWords []string, falseWords []string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	// The words are copied, as clones share them
+	words := make(map[string]bool, len(p.boolWords)+len(trueWords)+len(falseWords))
+	for word, value := range p.boolWords {
+		words[word] = value
+	}
+	for value, added := range map[bool][]string{true: trueWords, false: falseWords} {
+		for _, word := range added {
+			if existing, ok := words[word]; ok && existing != value {
+				return newMessageError(AmbiguousBoolWordMessage, word, existing)
+			}
+			words[word] = value
+		}
+	}
+	// {bool} isn't used by regular expressions, so only its name is
+	// redefined
+	p.parameterTypeByName["bool"] = newBoolParameterType(words)
+	p.boolWords = words
+	return nil
+}
+
+// newBoolParameterType returns {bool}, which matches the words and
+// transforms them to their value
+func newBoolParameterType(words map[string]bool) *ParameterType {
+	parameterType, err := NewParameterType(
+		"bool",
+		[]*regexp.Regexp{wordsRegexp(words)},
+		"bool",
+		func(args ...*string) interface{} {
+			return words[*args[0]]
+		},
+		false,
+		false,
+		false,
+	)
+	if err != nil {
+		panic(err)
+	}
+	parameterType.valueType = reflect.TypeOf(false)
+	return parameterType
+}
@@ -0,0 +1,50 @@
+package cucumberexpressions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoolWords(t *testing.T) {
+	match := func(t *testing.T, registry *ParameterTypeRegistry, text string) interface{} {
+		expression, err := NewCucumberExpression("the flag is {bool}", registry)
+		require.NoError(t, err)
+		args, err := expression.Match(text)
+		require.NoError(t, err)
+		if args == nil {
+			return nil
+		}
+		return args[0].GetValue()
+	}
+
+	t.Run("matches added words", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		require.NoError(t, registry.AddBoolWords([]string{"on", "ja"}, []string{"off", "nein"}))
+		require.Equal(t, true, match(t, registry, "the flag is on"))
+		require.Equal(t, false, match(t, registry, "the flag is nein"))
+		require.Equal(t, true, match(t, registry, "the flag is true"))
+	})
+
+	t.Run("keeps the words of expressions compiled before, clones and imported registries", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		expression, err := NewCucumberExpression("the flag is {bool}", registry)
+		require.NoError(t, err)
+		require.NoError(t, registry.AddBoolWords([]string{"on"}, nil))
+		args, err := expression.Match("the flag is on")
+		require.NoError(t, err)
+		require.Nil(t, args)
+
+		require.Equal(t, true, match(t, registry.Clone(), "the flag is on"))
+		require.NoError(t, registry.UnmarshalJSON([]byte(`{"parameterTypes": []}`)))
+		require.Equal(t, true, match(t, registry, "the flag is on"))
+	})
+
+	t.Run("does not add words that are already the other value", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		err := registry.AddBoolWords(nil, []string{"off", "yes"})
+		require.EqualError(t, err, `The word "yes" of {bool} is already true`)
+		require.Equal(t, AmbiguousBoolWordCode, ErrorCodeOf(err))
+		require.Nil(t, match(t, registry, "the flag is off"))
+	})
+}
@@ -0,0 +1,73 @@
+package cucumberexpressions
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// WHITE_SPACE_CLASS matches the characters of the Unicode White_Space
+// property in a character class, like the tokenizer, for {word} and folded
+// whitespace. \s alone only matches ASCII whitespace, so {word} would match
+// no-break and ideographic spaces.
+var WHITE_SPACE_CLASS = `\s\x0B\x85\pZ`
+
+// SetBoundaries makes the characters of boundaries end alternatives, such
+// as "red/green" in "red/green-blue", and the text {word} matches, besides
+// whitespace, which always does. Boundaries are used for hyphens or for
+// the punctuation of scripts without spaces between words, such as "、"
+// and "。". An empty string makes only whitespace a boundary again.
+// Expressions compiled before keep the boundaries they had then.
+func (p *ParameterTypeRegistry) SetBoundaries(boundaries string) error {
+	for _, r := range boundaries {
+		if isSpecial(r) {
+			return newMessageError(InvalidBoundaryMessage, string(r))
+		}
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.setBoundaries(boundaries)
+	return nil
+}
+
+// Boundaries returns the characters besides whitespace that end
+// alternatives and the text {word} matches
+func (p *ParameterTypeRegistry) Boundaries() string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.boundaries
+}
+
+// setBoundaries sets the boundaries and redefines {word}, which must be
+// locked
+func (p *ParameterTypeRegistry) setBoundaries(boundaries string) {
+	p.boundaries = boundaries
+	word := p.parameterTypeByName["word"]
+	regexps := WORD_REGEXPS
+	if boundaries != "" {
+		regexps = []*regexp.Regexp{regexp.MustCompile(fmt.Sprintf(`[^%s]+`, boundaryClass(boundaries)))}
+	}
+	// {word} is used by regular expressions for the capture groups of its
+	// default regexp, so only its name is redefined
+	p.parameterTypeByName["word"] = &ParameterType{
+		name:                           word.name,
+		regexps:                        regexps,
+		type1:                          word.type1,
+		transform:                      word.transform,
+		useForSnippets:                 word.useForSnippets,
+		preferForRegexpMatch:           word.preferForRegexpMatch,
+		useRegexpMatchAsStrongTypeHint: word.useRegexpMatchAsStrongTypeHint,
+		valueType:                      word.valueType,
+	}
+}
+
+// boundaryClass returns the contents of a character class matching
+// whitespace and the boundaries
+func boundaryClass(boundaries string) string {
+	var class strings.Builder
+	class.WriteString(WHITE_SPACE_CLASS)
+	for _, r := range boundaries {
+		class.WriteString(fmt.Sprintf(`\x{%X}`, r))
+	}
+	return class.String()
+}
@@ -0,0 +1,89 @@
+package cucumberexpressions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoundaries(t *testing.T) {
+	matches := func(t *testing.T, registry *ParameterTypeRegistry, expr string, text string) bool {
+		expression, err := NewCucumberExpression(expr, registry)
+		require.NoError(t, err)
+		args, err := expression.Match(text)
+		require.NoError(t, err)
+		return args != nil
+	}
+
+	t.Run("ends alternatives at whitespace in every script", func(t *testing.T) {
+		for _, example := range []struct {
+			script string
+			expr   string
+			text   string
+		}{
+			{"Latin", "I have a cat/dog", "I have a dog"},
+			{"Cyrillic", "у меня есть кошка/собака", "у меня есть собака"},
+			{"Greek", "έχω μια γάτα/σκύλο", "έχω μια σκύλο"},
+			{"Arabic", "لدي قط/كلب", "لدي كلب"},
+			{"Hebrew", "יש לי חתול/כלב", "יש לי כלב"},
+			{"Devanagari", "मेरे पास बिल्ली/कुत्ता है", "मेरे पास कुत्ता है"},
+			{"no-break space", "100\u00a0euro/dollar", "100\u00a0dollar"},
+			{"narrow no-break space", "100\u202feuro/dollar", "100\u202fdollar"},
+			{"ideographic space", "猫\u3000犬/鳥", "猫\u3000鳥"},
+		} {
+			t.Run(example.script, func(t *testing.T) {
+				require.True(t, matches(t, NewParameterTypeRegistry(), example.expr, example.text))
+			})
+		}
+	})
+
+	t.Run("ends alternatives at boundaries", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		require.False(t, matches(t, registry, "a red/green-blue car", "a red-blue car"))
+		require.False(t, matches(t, registry, "猫、犬/鳥が好き", "猫、鳥が好き"))
+
+		require.NoError(t, registry.SetBoundaries("-、が"))
+		require.Equal(t, "-、が", registry.Boundaries())
+		require.True(t, matches(t, registry, "a red/green-blue car", "a red-blue car"))
+		require.True(t, matches(t, registry, "猫、犬/鳥が好き", "猫、鳥が好き"))
+		require.True(t, matches(t, registry, "猫、犬/鳥が好き", "猫、犬が好き"))
+	})
+
+	t.Run("ends words at boundaries", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		require.True(t, matches(t, registry, "{word}", "red-blue"))
+
+		require.NoError(t, registry.SetBoundaries("-"))
+		require.False(t, matches(t, registry, "{word}", "red-blue"))
+		require.True(t, matches(t, registry, "{word}-{word}", "red-blue"))
+		require.False(t, matches(t, registry, "{word}", "red\u00a0blue"))
+
+		require.NoError(t, registry.SetBoundaries(""))
+		require.True(t, matches(t, registry, "{word}", "red-blue"))
+	})
+
+	t.Run("keeps the boundaries of expressions compiled before, clones and imported registries", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		expression, err := NewCucumberExpression("a red/green-blue car", registry)
+		require.NoError(t, err)
+		require.NoError(t, registry.SetBoundaries("-"))
+		args, err := expression.Match("a red-blue car")
+		require.NoError(t, err)
+		require.Nil(t, args)
+
+		require.True(t, matches(t, registry.Clone(), "a red/green-blue car", "a red-blue car"))
+		require.NoError(t, registry.UnmarshalJSON([]byte(`{"parameterTypes": []}`)))
+		require.True(t, matches(t, registry, "a red/green-blue car", "a red-blue car"))
+	})
+
+	t.Run("does not allow whitespace and special characters as boundaries", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		for _, boundaries := range []string{" ", "\u3000", "/", "(", "{", "\\"} {
+			err := registry.SetBoundaries(boundaries)
+			require.Error(t, err)
+			require.Equal(t, InvalidBoundaryCode, ErrorCodeOf(err))
+		}
+		require.EqualError(t, registry.SetBoundaries("-/"), `The character "/" is whitespace or special, so it can't be a boundary`)
+		require.Equal(t, "", registry.Boundaries())
+	})
+}
@@ -0,0 +1,215 @@
+/*
+This is a console application for trying out Cucumber Expressions.
+
+	cucumber-expressions serve [-addr localhost:8080]
+
+starts a web server with a page where an expression and a text can be
+entered to see the generated regular expression, the syntax tree of Cucumber
+Expressions and the matched arguments. The same information is available as
+JSON by posting to /match. Offsets are in runes, in the syntax tree and in
+the arguments.
+
+	cucumber-expressions extract "{} level={word} took {int}ms" < app.log
+
+prints the fields of the lines of standard input that match the expression
+as NDJSON, one object per line with the line number and the fields.
+*/
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	cucumberexpressions "github.com/cucumber/cucumber-expressions-go/v10"
+)
+
+// Set during build with -ldflags
+var version string = "(unknown version)"
+
+type matchRequest struct {
+	Expression string `json:"expression"`
+	Text       string `json:"text"`
+}
+
+type matchResponse struct {
+	Regexp    string                    `json:"regexp,omitempty"`
+	AST       *cucumberexpressions.Node `json:"ast,omitempty"`
+	Matched   bool                      `json:"matched"`
+	Arguments []matchArgument           `json:"arguments"`
+	Mismatch  string                    `json:"mismatch,omitempty"`
+	Error     string                    `json:"error,omitempty"`
+}
+
+type matchArgument struct {
+	ParameterType string      `json:"parameterType"`
+	Text          *string     `json:"text"`
+	Start         int         `json:"start"`
+	End           int         `json:"end"`
+	Value         interface{} `json:"value"`
+}
+
+type extractRecord struct {
+	Line   int                    `json:"line"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+func main() {
+	if len(os.Args) >= 3 && os.Args[1] == "extract" {
+		extract(os.Args[2])
+		return
+	}
+	if len(os.Args) < 2 || os.Args[1] != "serve" {
+		fmt.Fprintf(os.Stderr, "cucumber-expressions %s\n\nUsage: %s serve [-addr host:port]\n       %s extract EXPRESSION < LOG\n", version, os.Args[0], os.Args[0])
+		os.Exit(2)
+	}
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	flags.Parse(os.Args[2:])
+
+	http.HandleFunc("/", handleIndex)
+	http.HandleFunc("/match", handleMatch)
+	log.Printf("Serving on http://%s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
+
+func extract(expression string) {
+	extractor, err := cucumberexpressions.NewLogExtractor(expression, cucumberexpressions.NewParameterTypeRegistry())
+	if err != nil {
+		log.Fatal(err)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	err = extractor.Extract(os.Stdin, func(record cucumberexpressions.LogRecord) error {
+		return encoder.Encode(extractRecord{Line: record.Line, Fields: record.Fields})
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, indexHtml)
+}
+
+func handleMatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST a JSON object with expression and text", http.StatusMethodNotAllowed)
+		return
+	}
+	var request matchRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(match(request))
+}
+
+func match(request matchRequest) (response matchResponse) {
+	response.Arguments = []matchArgument{}
+	defer func() {
+		// Transforms of the built-in parameter types panic on bad input
+		if r := recover(); r != nil {
+			response.Error = fmt.Sprint(r)
+		}
+	}()
+
+	expression, err := newExpression(request.Expression)
+	if err != nil {
+		response.Error = err.Error()
+		return response
+	}
+	response.Regexp = expression.Regexp().String()
+	if _, ok := expression.(*cucumberexpressions.CucumberExpression); ok {
+		ast, err := cucumberexpressions.Parse(request.Expression)
+		if err != nil {
+			response.Error = err.Error()
+			return response
+		}
+		response.AST = &ast
+	}
+
+	args, err := expression.Match(request.Text)
+	if err != nil {
+		response.Error = err.Error()
+		return response
+	}
+	response.Matched = args != nil
+	if !response.Matched {
+		mismatch, err := cucumberexpressions.ExplainMismatch(expression, request.Text)
+		if err == nil && mismatch != nil {
+			response.Mismatch = mismatch.String()
+		}
+	}
+	for _, arg := range args {
+		response.Arguments = append(response.Arguments, matchArgument{
+			ParameterType: arg.ParameterType().Name(),
+			Text:          arg.Group().Value(),
+			Start:         arg.Start(),
+			End:           arg.End(),
+			Value:         arg.GetValue(),
+		})
+	}
+	return response
+}
+
+// newExpression treats expressions anchored with ^ or $, or surrounded by
+// slashes, as regular expressions like Cucumber does.
+func newExpression(source string) (cucumberexpressions.Expression, error) {
+	parameterTypeRegistry := cucumberexpressions.NewParameterTypeRegistry()
+	if strings.HasPrefix(source, "/") && strings.HasSuffix(source, "/") && len(source) > 1 {
+		source = source[1 : len(source)-1]
+	} else if !strings.HasPrefix(source, "^") && !strings.HasSuffix(source, "$") {
+		return cucumberexpressions.NewCucumberExpression(source, parameterTypeRegistry)
+	}
+	r, err := regexp.Compile(source)
+	if err != nil {
+		return nil, err
+	}
+	return cucumberexpressions.NewRegularExpression(r, parameterTypeRegistry), nil
+}
+
+const indexHtml = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>Cucumber Expressions</title>
+  <style>
+    body { font-family: sans-serif; max-width: 50em; margin: 2em auto; }
+    input { width: 100%; font-family: monospace; font-size: 1.1em; margin-bottom: 1em; }
+    pre { background: #f4f4f4; padding: 1em; }
+  </style>
+</head>
+<body>
+  <h1>Cucumber Expressions</h1>
+  <label>Expression <input id="expression" value="I have {int} cuke(s)"></label>
+  <label>Text <input id="text" value="I have 42 cukes"></label>
+  <pre id="result"></pre>
+  <script>
+    const expression = document.getElementById('expression')
+    const text = document.getElementById('text')
+    const result = document.getElementById('result')
+    async function update() {
+      const response = await fetch('match', {
+        method: 'POST',
+        body: JSON.stringify({ expression: expression.value, text: text.value })
+      })
+      result.textContent = JSON.stringify(await response.json(), null, 2)
+    }
+    expression.addEventListener('input', update)
+    text.addEventListener('input', update)
+    update()
+  </script>
+</body>
+</html>
+`
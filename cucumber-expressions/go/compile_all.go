@@ -0,0 +1,104 @@
+package cucumberexpressions
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+type CompileError struct {
+	Index      int
+	Expression string
+	Err        error
+}
+
+func (e *CompileError) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e *CompileError) localize(messages Messages) string {
+	return messages.format(CompileErrorMessage, e.Index, e.Expression, e.Err)
+}
+
+func (e *CompileError) Unwrap() error {
+	return e.Err
+}
+
+// CompileErrors holds every expression that failed to compile, in input order.
+type CompileErrors []*CompileError
+
+func (e CompileErrors) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e CompileErrors) localize(messages Messages) string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.localize(messages)
+	}
+	return messages.format(CompileErrorsMessage, len(e), strings.Join(lines, "\n"))
+}
+
+func (e CompileErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// CompileAll compiles the cucumber expressions using up to parallelism
+// goroutines (GOMAXPROCS when parallelism is not positive). The result has
+// one Expression per input, nil where compilation failed. All failures are
+// reported together as CompileErrors.
+func CompileAll(expressions []string, parameterTypeRegistry *ParameterTypeRegistry, parallelism int) ([]Expression, error) {
+	return CompileAllContext(context.Background(), expressions, parameterTypeRegistry, parallelism)
+}
+
+// CompileAllContext compiles the cucumber expressions like CompileAll, and
+// stops with the error of ctx when it is done, such as when a language
+// server request is cancelled.
+func CompileAllContext(ctx context.Context, expressions []string, parameterTypeRegistry *ParameterTypeRegistry, parallelism int) ([]Expression, error) {
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+	result := make([]Expression, len(expressions))
+	errs := make([]error, len(expressions))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < parallelism; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result[i], errs[i] = NewCucumberExpression(expressions[i], parameterTypeRegistry)
+			}
+		}()
+	}
+feed:
+	for i := range expressions {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var compileErrors CompileErrors
+	for i, err := range errs {
+		if err != nil {
+			compileErrors = append(compileErrors, &CompileError{Index: i, Expression: expressions[i], Err: err})
+		}
+	}
+	if compileErrors != nil {
+		return result, compileErrors
+	}
+	return result, nil
+}
@@ -0,0 +1,52 @@
+package cucumberexpressions
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileAll(t *testing.T) {
+	t.Run("compiles all expressions in order", func(t *testing.T) {
+		var sources []string
+		for i := 0; i < 100; i++ {
+			sources = append(sources, fmt.Sprintf("step %d with {int}", i))
+		}
+		expressions, err := CompileAll(sources, NewParameterTypeRegistry(), 4)
+		require.NoError(t, err)
+		require.Len(t, expressions, 100)
+		for i, expression := range expressions {
+			require.Equal(t, sources[i], expression.Source())
+		}
+	})
+
+	t.Run("reports all errors, not just the first", func(t *testing.T) {
+		sources := []string{"{int} cukes", "{unknown} cukes", "some {int}", "({int})"}
+		expressions, err := CompileAll(sources, NewParameterTypeRegistry(), 0)
+
+		compileErrors, ok := err.(CompileErrors)
+		require.True(t, ok)
+		require.Len(t, compileErrors, 2)
+		require.Equal(t, 1, compileErrors[0].Index)
+		require.Equal(t, "{unknown} cukes", compileErrors[0].Expression)
+		require.EqualError(t, compileErrors[0].Err, "Undefined parameter type {unknown}")
+		require.Equal(t, 3, compileErrors[1].Index)
+		require.EqualError(t, err, `2 of the expressions could not be compiled:
+expression 1 ({unknown} cukes): Undefined parameter type {unknown}
+expression 3 (({int})): Parameter types cannot be optional: ({int})`)
+
+		require.NotNil(t, expressions[0])
+		require.Nil(t, expressions[1])
+		require.NotNil(t, expressions[2])
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		expressions, err := CompileAllContext(ctx, []string{"{int} cukes", "{unknown} cukes"}, NewParameterTypeRegistry(), 1)
+		require.Equal(t, context.Canceled, err)
+		require.Nil(t, expressions)
+	})
+}
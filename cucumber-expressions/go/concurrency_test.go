@@ -0,0 +1,190 @@
+package cucumberexpressions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The concurrency tests use the shared state of the package from many
+// goroutines at once. They are meant to be run with the race detector,
+// which is how the Makefile runs them:
+//
+//	go test -race -run Concurrency github.com/cucumber/cucumber-expressions-go/v10
+func TestConcurrency(t *testing.T) {
+	goroutines, iterations := 16, 200
+	if testing.Short() {
+		goroutines, iterations = 4, 20
+	}
+	stress := func(t *testing.T, work func(goroutine int, iteration int)) {
+		var wg sync.WaitGroup
+		for goroutine := 0; goroutine < goroutines; goroutine++ {
+			wg.Add(1)
+			go func(goroutine int) {
+				defer wg.Done()
+				for iteration := 0; iteration < iterations; iteration++ {
+					work(goroutine, iteration)
+				}
+			}(goroutine)
+		}
+		wg.Wait()
+	}
+
+	t.Run("matches expressions from many goroutines", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		cucumberExpression, err := NewCucumberExpression("I have {int} cukes in my {word} after {} days", registry)
+		require.NoError(t, err)
+		regularExpression := NewRegularExpression(regexp.MustCompile(`^I have (\d+) cukes in my (\w+) after (.*) days$`), registry)
+
+		stress(t, func(goroutine int, iteration int) {
+			text := fmt.Sprintf("I have %d cukes in my belly after %d days", iteration, goroutine)
+			for _, expression := range []Expression{cucumberExpression, regularExpression} {
+				args, err := expression.Match(text, reflect.TypeOf(0), reflect.TypeOf(""), reflect.TypeOf(0))
+				if err != nil || len(args) != 3 {
+					t.Errorf("%s did not match %s: %v", expression.Source(), text, err)
+					return
+				}
+				if args[2].GetValue() != goroutine {
+					t.Errorf("%s matched %v days in %s", expression.Source(), args[2].GetValue(), text)
+				}
+			}
+		})
+	})
+
+	t.Run("defines parameter types while compiling and matching", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		stress(t, func(goroutine int, iteration int) {
+			name := fmt.Sprintf("color%d_%d", goroutine, iteration)
+			parameterType, err := NewParameterType(name, []*regexp.Regexp{regexp.MustCompile(`red|blue`)}, "color", nil, false, false, false)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if err := registry.DefineParameterType(parameterType); err != nil {
+				t.Error(err)
+				return
+			}
+			expression, err := NewCucumberExpression("a {"+name+"} ball for {int}", registry)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if args, err := expression.Match("a red ball for 3"); err != nil || args[0].GetValue() != "red" {
+				t.Errorf("%s did not match: %v", expression.Source(), err)
+			}
+			if iteration%50 == 0 {
+				_, _ = registry.LookupByRegexp(`red|blue`, `^a (red|blue) ball$`, "a red ball")
+				_ = registry.Clone()
+			}
+		})
+		require.Len(t, registry.ParameterTypes(), 13+goroutines*iterations)
+	})
+
+	t.Run("imports JSON while defining parameter types", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		data, err := json.Marshal(registry)
+		require.NoError(t, err)
+		stress(t, func(goroutine int, iteration int) {
+			if goroutine == 0 {
+				if err := json.Unmarshal(data, registry); err != nil {
+					t.Error(err)
+				}
+				return
+			}
+			name := fmt.Sprintf("size%d_%d", goroutine, iteration)
+			parameterType, err := NewParameterType(name, []*regexp.Regexp{regexp.MustCompile(`small|large`)}, "size", nil, false, false, false)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if err := registry.DefineParameterType(parameterType); err != nil {
+				t.Error(err)
+			}
+		})
+	})
+
+	t.Run("reloads an expression set while matching", func(t *testing.T) {
+		set, err := NewExpressionSet([]string{"deploy {word}"}, NewParameterTypeRegistry())
+		require.NoError(t, err)
+		stress(t, func(goroutine int, iteration int) {
+			if goroutine == 0 {
+				sources := []string{"deploy {word}", fmt.Sprintf("release %d", iteration)}
+				if err := set.Reload(sources, NewParameterTypeRegistry()); err != nil {
+					t.Error(err)
+				}
+				return
+			}
+			if expression, _, err := set.Match("deploy api"); err != nil || expression == nil {
+				t.Errorf("deploy api did not match: %v", err)
+			}
+		})
+	})
+
+	t.Run("adds routes while dispatching", func(t *testing.T) {
+		router := NewRouter(NewParameterTypeRegistry(), MostSpecificRoute)
+		require.NoError(t, router.Add("status {}", func(string) {}))
+		stress(t, func(goroutine int, iteration int) {
+			if iteration%10 == 0 {
+				if err := router.Add(fmt.Sprintf("status %d-%d", goroutine, iteration), func() {}); err != nil {
+					t.Error(err)
+				}
+				router.Use(func(next RouteHandler) RouteHandler { return next })
+			}
+			if err := router.Dispatch(context.Background(), "status api"); err != nil {
+				t.Error(err)
+			}
+		})
+	})
+
+	t.Run("collects match statistics from many goroutines", func(t *testing.T) {
+		statistics := NewMatchStatistics(10)
+		expression, err := NewCucumberExpression("I have {int} cukes", NewParameterTypeRegistry())
+		require.NoError(t, err)
+		instrumented := statistics.Instrument(expression)
+		stress(t, func(goroutine int, iteration int) {
+			if _, err := instrumented.Match(fmt.Sprintf("I have %d cukes", iteration)); err != nil {
+				t.Error(err)
+			}
+			_ = statistics.Expressions()
+		})
+		require.Equal(t, goroutines*iterations, statistics.Expressions()[0].Attempts)
+	})
+
+	t.Run("adds messages while localizing errors", func(t *testing.T) {
+		_, undefined := NewCucumberExpression("{unknown}", NewParameterTypeRegistry())
+		require.Error(t, undefined)
+		stress(t, func(goroutine int, iteration int) {
+			language := fmt.Sprintf("x%d", goroutine)
+			AddMessages(language, Messages{UndefinedParameterTypeMessage: "? {%s}"})
+			if message := LocalizeError(undefined, language); message != "? {unknown}" {
+				t.Errorf("localized %q", message)
+			}
+		})
+	})
+
+	t.Run("compiles expressions with pooled buffers", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		registry.SetPooledCompilation(true)
+		sources := []string{"I have {int} cucumber(s) in my belly/stomach", "three (blind) mice/rats", "{int} cucumber/gherkin {float}"}
+		expected := make([]string, len(sources))
+		for i, source := range sources {
+			expression, err := NewCucumberExpression(source, NewParameterTypeRegistry())
+			require.NoError(t, err)
+			expected[i] = expression.Regexp().String()
+		}
+
+		stress(t, func(goroutine int, iteration int) {
+			i := (goroutine + iteration) % len(sources)
+			expression, err := NewCucumberExpression(sources[i], registry)
+			if err != nil || expression.Regexp().String() != expected[i] {
+				t.Errorf("compiled %s to %v: %v", sources[i], expression, err)
+			}
+		})
+	})
+}
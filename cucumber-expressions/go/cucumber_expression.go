@@ -7,42 +7,109 @@ import (
 	"strings"
 )
 
-var ESCAPE_REGEXP = regexp.MustCompile(`([\\^[$.|?*+])`)
-var PARAMETER_REGEXP = regexp.MustCompile(`(\\\\\\\\)?{([^}]*)}`)
-var OPTIONAL_REGEXP = regexp.MustCompile(`(\\\\\\\\)?\([^)]+\)`)
-var ALTERNATIVE_NON_WHITESPACE_TEXT_REGEXP = regexp.MustCompile(`([^\s^/]+)((/[^\s^/]+)+)`)
-var DOUBLE_ESCAPE = `\\\\`
+// These regexps were used to compile Cucumber Expressions before they were
+// compiled from the tree Parse returns.
+var (
+	// Deprecated: unused.
+	ESCAPE_REGEXP = regexp.MustCompile(`([\\^[$.|?*+])`)
+
+	// Deprecated: unused.
+	PARAMETER_REGEXP = regexp.MustCompile(`(\\\\\\\\)?{([^}]*)}`)
+
+	// Deprecated: unused.
+	OPTIONAL_REGEXP = regexp.MustCompile(`(\\\\\\\\)?\([^)]+\)`)
+
+	// Deprecated: unused.
+	ALTERNATIVE_NON_WHITESPACE_TEXT_REGEXP = regexp.MustCompile(`([^\s^/]+)((/[^\s^/]+)+)`)
+
+	// Deprecated: unused.
+	DOUBLE_ESCAPE = `\\\\`
+)
+
+var PARAMETER_NAME_REGEXP = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
 
 type CucumberExpression struct {
-	source                string
-	parameterTypes        []*ParameterType
+	source         string
+	parameterTypes []*ParameterType
+	// parameterNames are the names of the parameters, such as count in
+	// {count:int}, or "" for parameters without a name
+	parameterNames        []string
 	treeRegexp            *TreeRegexp
 	parameterTypeRegistry *ParameterTypeRegistry
+	// defaults are the texts of the parameters in optionals, or nil when
+	// parameters can't be optional
+	defaults []string
+	// optionalParameters are the indexes of the parameters in optionals
+	optionalParameters map[int]bool
+	// defaultGroups are the groups of the defaults of the parameters in
+	// optionals, by index of the parameter
+	defaultGroups map[int]*Group
 }
 
 func NewCucumberExpression(expression string, parameterTypeRegistry *ParameterTypeRegistry) (Expression, error) {
-	result := &CucumberExpression{source: expression, parameterTypeRegistry: parameterTypeRegistry}
+	return newCucumberExpression(expression, parameterTypeRegistry, nil)
+}
 
-	expression = result.processEscapes(expression)
+// NewCucumberExpressionWithDefaults returns an expression whose optionals
+// may have parameters, such as "I wait( {int} seconds)". The defaults are
+// the texts of these parameters, in order, for when their optional isn't
+// in the text, and are transformed like the text would be:
+//
+//	NewCucumberExpressionWithDefaults("I wait( {int} seconds)", registry, "1")
+//
+// matches "I wait" with the argument 1.
+func NewCucumberExpressionWithDefaults(expression string, parameterTypeRegistry *ParameterTypeRegistry, defaults ...string) (Expression, error) {
+	return newCucumberExpression(expression, parameterTypeRegistry, append([]string{}, defaults...))
+}
 
-	expression, err := result.processOptional(expression)
-	if err != nil {
-		return nil, err
+func newCucumberExpression(expression string, parameterTypeRegistry *ParameterTypeRegistry, defaults []string) (Expression, error) {
+	result := &CucumberExpression{
+		source:                expression,
+		parameterTypeRegistry: parameterTypeRegistry,
+		defaults:              defaults,
+		optionalParameters:    map[int]bool{},
+		defaultGroups:         map[int]*Group{},
 	}
 
-	expression, err = result.processAlteration(expression)
+	parameterTypeRegistry.mutex.RLock()
+	boundaries := parameterTypeRegistry.boundaries
+	fold := parameterTypeRegistry.foldWhiteSpace
+	pooled := parameterTypeRegistry.pooledCompilation
+	parameterTypeRegistry.mutex.RUnlock()
+
+	buffers := &parseBuffers{}
+	if pooled {
+		// The tree is dropped once the expression is compiled
+		buffers = parseBuffersPool.Get().(*parseBuffers)
+		defer func() {
+			buffers.reset()
+			parseBuffersPool.Put(buffers)
+		}()
+	}
+	ast, err := parseWith(buffers, expression, boundaries)
 	if err != nil {
 		return nil, err
 	}
+	if defaults == nil {
+		for node := range AllNodes(ast) {
+			if node.NodeType != OptionalNode {
+				continue
+			}
+			if parameter, ok := firstParameter(node); ok {
+				return nil, createParameterInOptional(expression, parameter)
+			}
+		}
+	}
 
-	expression, err = result.processParameters(expression, parameterTypeRegistry)
+	pattern, err := result.rewriteToRegex(ast, fold, false)
 	if err != nil {
 		return nil, err
 	}
+	if result.defaults != nil && len(result.defaults) != len(result.optionalParameters) {
+		return nil, newMessageError(OptionalParameterDefaultsMessage, result.source, len(result.optionalParameters), len(result.defaults))
+	}
 
-	expression = "^" + expression + "$"
-
-	result.treeRegexp = NewTreeRegexp(regexp.MustCompile(expression))
+	result.treeRegexp = NewTreeRegexp(regexp.MustCompile("^" + pattern + "$"))
 	return result, nil
 }
 
@@ -59,7 +126,16 @@ func (c *CucumberExpression) Match(text string, typeHints ...reflect.Type) ([]*A
 			parameterTypes[i] = parameterType
 		}
 	}
-	return BuildArguments(c.treeRegexp, text, parameterTypes), nil
+	arguments := BuildArguments(c.treeRegexp, text, parameterTypes)
+	for i, argument := range arguments {
+		if argument.group.Value() == nil {
+			argument.defaultGroup = c.defaultGroups[i]
+		}
+	}
+	if err := resolveTemplates(c.parameterTypeRegistry, text, arguments); err != nil {
+		return nil, err
+	}
+	return arguments, nil
 }
 
 func hintOrDefault(i int, typeHints ...reflect.Type) reflect.Type {
@@ -78,70 +154,128 @@ func (c *CucumberExpression) Source() string {
 	return c.source
 }
 
-func (c *CucumberExpression) processEscapes(expression string) string {
-	return ESCAPE_REGEXP.ReplaceAllString(expression, `\$1`)
-}
-
-func (c *CucumberExpression) processOptional(expression string) (string, error) {
-	var err error
-	result := OPTIONAL_REGEXP.ReplaceAllStringFunc(expression, func(match string) string {
-		if strings.HasPrefix(match, DOUBLE_ESCAPE) {
-			return fmt.Sprintf(`\(%s\)`, match[5:len(match)-1])
+// rewriteToRegex returns the regexp of a node of the expression. The
+// whitespace of text matches any whitespace when fold is true, and
+// inOptional tells if the node is in an optional.
+func (c *CucumberExpression) rewriteToRegex(node Node, fold bool, inOptional bool) (string, error) {
+	switch node.NodeType {
+	case TextNode:
+		text := regexp.QuoteMeta(node.Token)
+		if fold {
+			text = FOLDED_WHITE_SPACE_REGEXP.ReplaceAllLiteralString(text, FOLDED_WHITE_SPACE)
 		}
-		if PARAMETER_REGEXP.MatchString(match) {
-			err = NewCucumberExpressionError(fmt.Sprintf("Parameter types cannot be optional: %s", c.source))
-			return match
+		return text, nil
+	case OptionalNode:
+		pattern, err := c.rewriteNodesToRegex(node.Nodes, "", fold, true)
+		if err != nil {
+			return "", err
 		}
-		return fmt.Sprintf("(?:%s)?", match[1:len(match)-1])
-	})
-	return result, err
-}
-
-func (c *CucumberExpression) processAlteration(expression string) (string, error) {
-	var err error
-	result := ALTERNATIVE_NON_WHITESPACE_TEXT_REGEXP.ReplaceAllStringFunc(expression, func(match string) string {
-		// replace \/ with /
-		// replace / with |
-		replacement := strings.Replace(match, "/", "|", -1)
-		replacement = strings.Replace(replacement, `\\\\|`, "/", -1)
-
-		if strings.Contains(replacement, "|") {
-			parts := strings.Split(replacement, ":")
-			for _, part := range parts {
-				if PARAMETER_REGEXP.MatchString(part) {
-					err = NewCucumberExpressionError(fmt.Sprintf("Parameter types cannot be alternative: %s", c.source))
-					return match
-				}
-			}
-			return fmt.Sprintf("(?:%s)", replacement)
+		return "(?:" + pattern + ")?", nil
+	case AlternationNode:
+		pattern, err := c.rewriteNodesToRegex(node.Nodes, "|", fold, inOptional)
+		if err != nil {
+			return "", err
 		}
+		return "(?:" + pattern + ")", nil
+	case ParameterNode:
+		return c.rewriteParameterToRegex(node, inOptional)
+	}
+	return c.rewriteNodesToRegex(node.Nodes, "", fold, inOptional)
+}
 
-		return replacement
-	})
-	return result, err
+func (c *CucumberExpression) rewriteNodesToRegex(nodes []Node, separator string, fold bool, inOptional bool) (string, error) {
+	patterns := make([]string, len(nodes))
+	for i, node := range nodes {
+		pattern, err := c.rewriteToRegex(node, fold, inOptional)
+		if err != nil {
+			return "", err
+		}
+		patterns[i] = pattern
+	}
+	return strings.Join(patterns, separator), nil
 }
 
-func (c *CucumberExpression) processParameters(expression string, parameterTypeRegistry *ParameterTypeRegistry) (string, error) {
-	var err error
-	result := PARAMETER_REGEXP.ReplaceAllStringFunc(expression, func(match string) string {
-		if strings.HasPrefix(match, DOUBLE_ESCAPE) {
-			return fmt.Sprintf(`\{%s\}`, match[5:len(match)-1])
+// rewriteParameterToRegex looks up the parameter type of a parameter, such
+// as {int} or {count:int}, and returns its capture group
+func (c *CucumberExpression) rewriteParameterToRegex(node Node, inOptional bool) (string, error) {
+	name, typeName := "", node.Text()
+	if i := strings.Index(typeName, ":"); i >= 0 {
+		if !c.parameterTypeRegistry.SyntaxEnabled(NamedParameterSyntax) {
+			return "", &SyntaxNotEnabledError{NamedParameterSyntax, "{" + typeName + "}", c.source, node.Start, node.End}
+		}
+		name, typeName = typeName[:i], typeName[i+1:]
+		if err := c.checkParameterName(name, node); err != nil {
+			return "", err
 		}
+	}
+	if err := CheckParameterTypeName(typeName); err != nil {
+		return "", err
+	}
+	parameterType := c.parameterTypeRegistry.LookupByTypeName(typeName)
+	if parameterType == nil {
+		return "", &UndefinedParameterTypeError{
+			TypeName:    typeName,
+			Suggestions: c.parameterTypeRegistry.closestParameterTypeNames(typeName),
+			Expression:  c.source,
+			Start:       node.Start,
+			End:         node.End,
+		}
+	}
+	if inOptional {
+		c.optionalParameters[len(c.parameterTypes)] = true
+		if err := c.addDefault(parameterType); err != nil {
+			return "", err
+		}
+	}
+	c.parameterTypes = append(c.parameterTypes, parameterType)
+	c.parameterNames = append(c.parameterNames, name)
+	if name != "" {
+		// The group is named, so the argument is too
+		return "(?P<" + name + ">" + buildCaptureRegexp(parameterType.regexps)[1:], nil
+	}
+	return buildCaptureRegexp(parameterType.regexps), nil
+}
 
-		typeName := match[1 : len(match)-1]
-		err = CheckParameterTypeName(typeName)
-		if err != nil {
-			return ""
+// firstParameter returns the first parameter among the descendants of a
+// node, and tells if there is one
+func firstParameter(node Node) (Node, bool) {
+	for descendant := range AllNodes(node) {
+		if descendant.NodeType == ParameterNode {
+			return descendant, true
 		}
-		parameterType := parameterTypeRegistry.LookupByTypeName(typeName)
-		if parameterType == nil {
-			err = NewUndefinedParameterTypeError(typeName)
-			return match
+	}
+	return Node{}, false
+}
+
+// checkParameterName checks that the name of a parameter, such as count in
+// {count:int}, can name a capture group and isn't the name of another one
+func (c *CucumberExpression) checkParameterName(name string, parameter Node) error {
+	if !PARAMETER_NAME_REGEXP.MatchString(name) {
+		return &InvalidParameterNameError{name, c.source, parameter.Start, parameter.End}
+	}
+	for _, other := range c.parameterNames {
+		if other == name {
+			return &DuplicateParameterNameError{name, c.source, parameter.Start, parameter.End}
 		}
-		c.parameterTypes = append(c.parameterTypes, parameterType)
-		return buildCaptureRegexp(parameterType.regexps)
-	})
-	return result, err
+	}
+	return nil
+}
+
+// addDefault matches the next default with the regexps of the parameter
+// type of the next parameter, which is in an optional
+func (c *CucumberExpression) addDefault(parameterType *ParameterType) error {
+	index := len(c.defaultGroups)
+	if index >= len(c.defaults) {
+		// The number of defaults is reported once all parameters are known
+		return nil
+	}
+	defaultRegexp := NewTreeRegexp(regexp.MustCompile("^" + buildCaptureRegexp(parameterType.regexps) + "$"))
+	group := defaultRegexp.Match(c.defaults[index])
+	if group == nil {
+		return newMessageError(DefaultMismatchMessage, c.defaults[index], parameterType.Name())
+	}
+	c.defaultGroups[len(c.parameterTypes)] = group.Children()[0]
+	return nil
 }
 
 func buildCaptureRegexp(regexps []*regexp.Regexp) string {
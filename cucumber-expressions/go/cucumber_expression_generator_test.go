@@ -83,6 +83,29 @@ func TestCucumberExpressionGeneratory(t *testing.T) {
 		)
 	})
 
+	t.Run("generates expression for uuids", func(t *testing.T) {
+		assertExpression(
+			t,
+			"the resource {uuid} exists",
+			[]string{"uuid"},
+			"the resource 123e4567-e89b-12d3-a456-426614174000 exists",
+		)
+	})
+
+	t.Run("does not generate expressions for durations", func(t *testing.T) {
+		for text, expected := range map[string][]string{
+			"I wait 3 seconds":    {"I wait {int} seconds", "I wait {float} seconds"},
+			"I have 5 days left":  {"I have {int} days left", "I have {float} days left"},
+			"it costs 5m dollars": {"it costs 5m dollars"},
+		} {
+			var sources []string
+			for _, generatedExpression := range NewCucumberExpressionGenerator(NewParameterTypeRegistry()).GenerateExpressions(text) {
+				sources = append(sources, generatedExpression.Source())
+			}
+			require.Equal(t, expected, sources)
+		}
+	})
+
 	t.Run("generates expression with % sign", func(t *testing.T) {
 		assertExpression(t, "I am {int}%% foobar", []string{"int"}, "I am 20%% foobar")
 	})
@@ -159,7 +182,86 @@ func TestCucumberExpressionGeneratory(t *testing.T) {
 		)
 	})
 
-	// TODO: prefers widest match
+	t.Run("prefers widest match when there is overlap at the same position", func(t *testing.T) {
+		parameterTypeRegistry := NewParameterTypeRegistry()
+		parameterType1, err := NewParameterType(
+			"type1",
+			[]*regexp.Regexp{regexp.MustCompile("b")},
+			"type1",
+			nil,
+			true,
+			false,
+			false,
+		)
+		require.NoError(t, err)
+		require.NoError(t, parameterTypeRegistry.DefineParameterType(parameterType1))
+		parameterType2, err := NewParameterType(
+			"type2",
+			[]*regexp.Regexp{regexp.MustCompile("b c")},
+			"type2",
+			nil,
+			true,
+			false,
+			false,
+		)
+		require.NoError(t, err)
+		require.NoError(t, parameterTypeRegistry.DefineParameterType(parameterType2))
+
+		assertExpressionWithParameterTypeRegistry(
+			t,
+			parameterTypeRegistry,
+			"a {type2} d",
+			[]string{"type2"},
+			"a b c d",
+		)
+	})
+
+	t.Run("does not suggest parameter types that are not for snippets", func(t *testing.T) {
+		parameterTypeRegistry := NewParameterTypeRegistry()
+		color, err := NewParameterType(
+			"color",
+			[]*regexp.Regexp{regexp.MustCompile("red|blue")},
+			"Color",
+			nil,
+			false,
+			false,
+			false,
+		)
+		require.NoError(t, err)
+		require.NoError(t, parameterTypeRegistry.DefineParameterType(color))
+
+		assertExpressionWithParameterTypeRegistry(
+			t,
+			parameterTypeRegistry,
+			"I have a red ball",
+			[]string{},
+			"I have a red ball",
+		)
+	})
+
+	t.Run("lists expressions with preferential parameter types first", func(t *testing.T) {
+		parameterTypeRegistry := NewParameterTypeRegistry()
+		for _, name := range []string{"alpha", "beta", "gamma"} {
+			parameterType, err := NewParameterType(
+				name,
+				[]*regexp.Regexp{regexp.MustCompile("x")},
+				name,
+				nil,
+				true,
+				name == "gamma",
+				false,
+			)
+			require.NoError(t, err)
+			require.NoError(t, parameterTypeRegistry.DefineParameterType(parameterType))
+		}
+		generator := NewCucumberExpressionGenerator(parameterTypeRegistry)
+		generatedExpressions := generator.GenerateExpressions("I have x")
+		sources := make([]string, len(generatedExpressions))
+		for i, generatedExpression := range generatedExpressions {
+			sources[i] = generatedExpression.Source()
+		}
+		require.Equal(t, []string{"I have {gamma}", "I have {alpha}", "I have {beta}"}, sources)
+	})
 
 	t.Run("generates all combinations of expressions when several parameter types match", func(t *testing.T) {
 		parameterTypeRegistry := NewParameterTypeRegistry()
@@ -0,0 +1,378 @@
+package cucumberexpressions
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Parse parses a Cucumber Expression into a tree of nodes, with an
+// ExpressionNode at its root, the way NewCucumberExpression reads it. It
+// only checks the syntax of the expression: parameter types are not looked
+// up, and parameters in optionals are only allowed with defaults.
+func Parse(expression string) (Node, error) {
+	return parse(expression, "")
+}
+
+// parse parses an expression whose alternatives also end at the
+// characters of boundaries
+func parse(expression string, boundaries string) (Node, error) {
+	return parseWith(&parseBuffers{}, expression, boundaries)
+}
+
+// parseWith parses an expression like parse, with the tokens and nodes in
+// buffers
+func parseWith(buffers *parseBuffers, expression string, boundaries string) (Node, error) {
+	tokens, err := buffers.tokenize(expression, boundaries)
+	if err != nil {
+		return Node{}, err
+	}
+	consumed, ast, err := parseExpression(buffers, buffers.runesOf(expression), tokens, 0)
+	if err != nil {
+		return Node{}, err
+	}
+	if consumed != len(tokens) {
+		// If configured correctly this will never happen
+		return Node{}, createCouldNotParse(expression, tokens[consumed])
+	}
+	return ast, nil
+}
+
+// Diagnostic is a problem with an expression that ParseWithRecovery
+// recovered from. Start and End are the offsets in runes of the problem,
+// and ByteStart and ByteEnd the offsets in bytes.
+type Diagnostic struct {
+	Start     int
+	End       int
+	ByteStart int
+	ByteEnd   int
+	Message   string
+	Code      ErrorCode
+	Severity  Severity
+	Err       error
+}
+
+// ParseWithRecovery parses a Cucumber Expression like Parse, but doesn't
+// stop at the first problem. The tokens the parser can not make sense of,
+// such as an unmatched '(', are read as text. It returns the best-effort
+// tree together with a diagnostic per problem, in the order they appear in
+// the expression.
+func ParseWithRecovery(expression string) (Node, []Diagnostic) {
+	tokens, err := Tokenize(expression)
+	var diagnostics []Diagnostic
+	if err != nil {
+		// The escape characters are text in the tokens
+		diagnostics = append(diagnostics, diagnosticOf(expression, err))
+	}
+
+	buffers := &parseBuffers{}
+	runes := []rune(expression)
+	for {
+		_, ast, err := parseExpression(buffers, runes, tokens, 0)
+		if err == nil {
+			sort.SliceStable(diagnostics, func(i, j int) bool {
+				return diagnostics[i].Start < diagnostics[j].Start
+			})
+			return ast, diagnostics
+		}
+		diagnostic := diagnosticOf(expression, err)
+		diagnostics = append(diagnostics, diagnostic)
+		if !readAsText(tokens, diagnostic.Start) {
+			// If configured correctly this will never happen
+			return Node{ExpressionNode, 0, len(runes), 0, len(expression), "", []Node{}}, diagnostics
+		}
+	}
+}
+
+func diagnosticOf(expression string, err error) Diagnostic {
+	var problem expressionProblem
+	if !errors.As(err, &problem) {
+		return Diagnostic{Message: err.Error(), Code: ErrorCodeOf(err), Severity: SeverityError, Err: err}
+	}
+	start, end := problem.span()
+	message, _ := problem.describe(englishMessages)
+	return Diagnostic{start, end, byteOffset(expression, start), byteOffset(expression, end), message, ErrorCodeOf(err), SeverityError, err}
+}
+
+// byteOffset converts an offset in runes of an expression to one in bytes
+func byteOffset(expression string, runeOffset int) int {
+	runes := 0
+	for i := range expression {
+		if runes == runeOffset {
+			return i
+		}
+		runes++
+	}
+	return len(expression)
+}
+
+// readAsText turns the token at start into text, and tells if there was a
+// token to turn
+func readAsText(tokens []Token, start int) bool {
+	for i, t := range tokens {
+		if t.Start == start && t.TokenType != TextToken && t.TokenType != StartOfLineToken && t.TokenType != EndOfLineToken {
+			tokens[i].TokenType = TextToken
+			return true
+		}
+	}
+	return false
+}
+
+// A parser tries to parse a node from the tokens at current. It returns
+// the number of tokens it consumed, which is 0 when the tokens are not for
+// this parser.
+type parser func(buffers *parseBuffers, expression []rune, tokens []Token, current int) (int, Node, error)
+
+/*
+ * text := .
+ */
+func parseText(buffers *parseBuffers, expression []rune, tokens []Token, current int) (int, Node, error) {
+	t := tokens[current]
+	switch t.TokenType {
+	case WhiteSpaceToken, TextToken, BeginParameterToken, EndParameterToken, EndOptionalToken, AlternationToken:
+		return 1, Node{TextNode, t.Start, t.End, t.ByteStart, t.ByteEnd, t.Text, nil}, nil
+	}
+	return 0, Node{}, nil
+}
+
+/*
+ * parameter := '{' + name + '}'
+ * name := [^}]*
+ */
+func parseParameter(buffers *parseBuffers, expression []rune, tokens []Token, current int) (int, Node, error) {
+	if !lookingAt(tokens, current, BeginParameterToken) {
+		return 0, Node{}, nil
+	}
+	end := current + 1
+	for !lookingAtAny(tokens, end, EndParameterToken, EndOfLineToken) {
+		end++
+	}
+	if !lookingAt(tokens, end, EndParameterToken) {
+		// A '{' without a '}' is text
+		return 0, Node{}, nil
+	}
+
+	name := buffers.nodes(0)
+	if end > current+1 {
+		first := tokens[current+1]
+		last := tokens[end-1]
+		for _, t := range tokens[current+1 : end] {
+			if t.TokenType != TextToken && t.TokenType != WhiteSpaceToken {
+				return 0, Node{}, createInvalidParameterTypeName(string(expression), t)
+			}
+		}
+		// The name is not unescaped, like the names of parameter types
+		name = buffers.nodes(1)
+		name[0] = Node{TextNode, first.Start, last.End, first.ByteStart, last.ByteEnd, string(expression[first.Start:last.End]), nil}
+	}
+	start := tokens[current]
+	stop := tokens[end]
+	return end + 1 - current, Node{ParameterNode, start.Start, stop.End, start.ByteStart, stop.ByteEnd, "", name}, nil
+}
+
+/*
+ * optional := '(' + option* + ')'
+ * option := alternation | optional | parameter | text
+ */
+func parseOptional(buffers *parseBuffers, expression []rune, tokens []Token, current int) (int, Node, error) {
+	return parseBetween(OptionalNode, BeginOptionalToken, EndOptionalToken, parseAlternation, parseOptional, parseParameter, parseText)(buffers, expression, tokens, current)
+}
+
+func parseAlternativeSeparator(buffers *parseBuffers, expression []rune, tokens []Token, current int) (int, Node, error) {
+	if !lookingAt(tokens, current, AlternationToken) {
+		return 0, Node{}, nil
+	}
+	t := tokens[current]
+	return 1, Node{AlternativeNode, t.Start, t.End, t.ByteStart, t.ByteEnd, t.Text, nil}, nil
+}
+
+/*
+ * alternation := (?<=left-boundary) + alternative+ + ( '/' + alternative+ )+ + (?=right-boundary)
+ * left-boundary := whitespace | '(' | ^
+ * right-boundary := whitespace | ')' | $
+ * alternative: = optional | parameter | text
+ *
+ * Parameters are not allowed in alternatives.
+ */
+func parseAlternation(buffers *parseBuffers, expression []rune, tokens []Token, current int) (int, Node, error) {
+	previous := current - 1
+	if !lookingAtAny(tokens, previous, StartOfLineToken, WhiteSpaceToken, BeginOptionalToken) {
+		return 0, Node{}, nil
+	}
+
+	consumed, subAst, err := parseTokensUntil(buffers, expression, []parser{parseAlternativeSeparator, parseOptional, parseParameter, parseText}, tokens, current, WhiteSpaceToken, EndOfLineToken, EndOptionalToken)
+	if err != nil {
+		return 0, Node{}, err
+	}
+	if !containsNodeType(subAst, AlternativeNode) {
+		return 0, Node{}, nil
+	}
+
+	// Does not consume right hand boundary token
+	start := tokens[current]
+	end := tokens[current+consumed]
+	node := Node{AlternationNode, start.Start, end.Start, start.ByteStart, end.ByteStart, "", nil}
+	node.Nodes = splitAlternatives(buffers, node, subAst)
+	for _, alternative := range node.Nodes {
+		if len(alternative.Nodes) == 0 {
+			// Without text on both sides, a '/' is text
+			return 0, Node{}, nil
+		}
+	}
+	for _, alternative := range node.Nodes {
+		for descendant := range AllNodes(alternative) {
+			if descendant.NodeType == ParameterNode {
+				return 0, Node{}, createParameterInAlternative(string(expression), descendant)
+			}
+		}
+	}
+	return consumed, node, nil
+}
+
+/*
+ * cucumber-expression :=  ( alternation | optional | parameter | text )*
+ */
+func parseExpression(buffers *parseBuffers, expression []rune, tokens []Token, current int) (int, Node, error) {
+	return parseBetween(ExpressionNode, StartOfLineToken, EndOfLineToken, parseAlternation, parseOptional, parseParameter, parseText)(buffers, expression, tokens, current)
+}
+
+func parseBetween(nodeType NodeType, beginToken TokenType, endToken TokenType, parsers ...parser) parser {
+	return func(buffers *parseBuffers, expression []rune, tokens []Token, current int) (int, Node, error) {
+		if !lookingAt(tokens, current, beginToken) {
+			return 0, Node{}, nil
+		}
+
+		subCurrent := current + 1
+		consumed, subAst, err := parseTokensUntil(buffers, expression, parsers, tokens, subCurrent, endToken, EndOfLineToken)
+		if err != nil {
+			return 0, Node{}, err
+		}
+		subCurrent += consumed
+
+		// endToken not found
+		if !lookingAt(tokens, subCurrent, endToken) {
+			return 0, Node{}, createMissingEndToken(string(expression), beginToken, endToken, tokens[current])
+		}
+
+		// consumes endToken
+		start := tokens[current]
+		end := tokens[subCurrent]
+		return subCurrent + 1 - current, Node{nodeType, start.Start, end.End, start.ByteStart, end.ByteEnd, "", subAst}, nil
+	}
+}
+
+func parseToken(buffers *parseBuffers, expression []rune, parsers []parser, tokens []Token, startAt int) (int, Node, error) {
+	for _, p := range parsers {
+		consumed, ast, err := p(buffers, expression, tokens, startAt)
+		if err != nil {
+			return 0, Node{}, err
+		}
+		if consumed != 0 {
+			return consumed, ast, nil
+		}
+	}
+	// If configured correctly this will never happen
+	return 0, Node{}, &CucumberExpressionError{s: fmt.Sprintf("No eligible parsers for %v", tokens), code: CouldNotParseCode}
+}
+
+// parseTokensUntil parses nodes until one of endTokens. The nodes are
+// pushed on the stack of buffers, above the ones of the parsers that called
+// it, and moved to a slice of their own once they are all parsed.
+func parseTokensUntil(buffers *parseBuffers, expression []rune, parsers []parser, tokens []Token, startAt int, endTokens ...TokenType) (int, []Node, error) {
+	current := startAt
+	bottom := len(buffers.stack)
+	defer func() { buffers.stack = buffers.stack[:bottom] }()
+	for current < len(tokens) {
+		if lookingAtAny(tokens, current, endTokens...) {
+			break
+		}
+		consumed, node, err := parseToken(buffers, expression, parsers, tokens, current)
+		if err != nil {
+			return 0, nil, err
+		}
+		current += consumed
+		buffers.stack = append(buffers.stack, node)
+	}
+	ast := buffers.nodes(len(buffers.stack) - bottom)
+	copy(ast, buffers.stack[bottom:])
+	return current - startAt, ast, nil
+}
+
+func lookingAtAny(tokens []Token, at int, tokenTypes ...TokenType) bool {
+	for _, tokenType := range tokenTypes {
+		if lookingAt(tokens, at, tokenType) {
+			return true
+		}
+	}
+	return false
+}
+
+func lookingAt(tokens []Token, at int, tokenType TokenType) bool {
+	if at < 0 {
+		return tokenType == StartOfLineToken
+	}
+	if at >= len(tokens) {
+		return tokenType == EndOfLineToken
+	}
+	return tokens[at].TokenType == tokenType
+}
+
+func containsNodeType(nodes []Node, nodeType NodeType) bool {
+	for _, node := range nodes {
+		if node.NodeType == nodeType {
+			return true
+		}
+	}
+	return false
+}
+
+// splitAlternatives groups the nodes of an alternation between its
+// separators into alternative nodes. The nodes of the alternatives are the
+// runs of nodes of the alternation between the separators.
+func splitAlternatives(buffers *parseBuffers, parent Node, alternation []Node) []Node {
+	separators := 0
+	for _, node := range alternation {
+		if node.NodeType == AlternativeNode {
+			separators++
+		}
+	}
+
+	nodes := buffers.nodes(separators + 1)
+	start := 0
+	i := 0
+	for j, node := range alternation {
+		if node.NodeType != AlternativeNode {
+			continue
+		}
+		nodes[i] = Node{AlternativeNode, parent.Start, node.Start, parent.ByteStart, node.ByteStart, "", alternation[start:j:j]}
+		if i > 0 {
+			nodes[i].Start = alternation[start-1].End
+			nodes[i].ByteStart = alternation[start-1].ByteEnd
+		}
+		start = j + 1
+		i++
+	}
+	last := Node{AlternativeNode, parent.Start, parent.End, parent.ByteStart, parent.ByteEnd, "", alternation[start:len(alternation):len(alternation)]}
+	if i > 0 {
+		last.Start = alternation[start-1].End
+		last.ByteStart = alternation[start-1].ByteEnd
+	}
+	nodes[i] = last
+	return nodes
+}
+
+func symbolOf(tokenType TokenType) string {
+	switch tokenType {
+	case BeginOptionalToken:
+		return string(beginOptionalCharacter)
+	case EndOptionalToken:
+		return string(endOptionalCharacter)
+	case BeginParameterToken:
+		return string(beginParameterCharacter)
+	case EndParameterToken:
+		return string(endParameterCharacter)
+	case AlternationToken:
+		return string(alternationCharacter)
+	}
+	return ""
+}
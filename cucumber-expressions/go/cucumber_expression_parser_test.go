@@ -0,0 +1,302 @@
+package cucumberexpressions
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCucumberExpressionParser(t *testing.T) {
+	t.Run("parses an empty expression", func(t *testing.T) {
+		ast, err := Parse("")
+		require.NoError(t, err)
+		require.Equal(t, Node{ExpressionNode, 0, 0, 0, 0, "", []Node{}}, ast)
+	})
+
+	t.Run("parses text and whitespace", func(t *testing.T) {
+		ast, err := Parse("three blind mice")
+		require.NoError(t, err)
+		require.Equal(t, Node{ExpressionNode, 0, 16, 0, 16, "", []Node{
+			{TextNode, 0, 5, 0, 5, "three", nil},
+			{TextNode, 5, 6, 5, 6, " ", nil},
+			{TextNode, 6, 11, 6, 11, "blind", nil},
+			{TextNode, 11, 12, 11, 12, " ", nil},
+			{TextNode, 12, 16, 12, 16, "mice", nil},
+		}}, ast)
+	})
+
+	t.Run("parses a parameter", func(t *testing.T) {
+		ast, err := Parse("I have {int} cukes")
+		require.NoError(t, err)
+		require.Equal(t, Node{ParameterNode, 7, 12, 7, 12, "", []Node{
+			{TextNode, 8, 11, 8, 11, "int", nil},
+		}}, ast.Nodes[4])
+	})
+
+	t.Run("parses an optional with a nested optional", func(t *testing.T) {
+		ast, err := Parse("cuke((s))")
+		require.NoError(t, err)
+		require.Equal(t, Node{ExpressionNode, 0, 9, 0, 9, "", []Node{
+			{TextNode, 0, 4, 0, 4, "cuke", nil},
+			{OptionalNode, 4, 9, 4, 9, "", []Node{
+				{OptionalNode, 5, 8, 5, 8, "", []Node{
+					{TextNode, 6, 7, 6, 7, "s", nil},
+				}},
+			}},
+		}}, ast)
+	})
+
+	t.Run("parses an alternation bounded by whitespace", func(t *testing.T) {
+		ast, err := Parse("three mice/rats(s) run")
+		require.NoError(t, err)
+		require.Equal(t, Node{AlternationNode, 6, 18, 6, 18, "", []Node{
+			{AlternativeNode, 6, 10, 6, 10, "", []Node{
+				{TextNode, 6, 10, 6, 10, "mice", nil},
+			}},
+			{AlternativeNode, 11, 18, 11, 18, "", []Node{
+				{TextNode, 11, 15, 11, 15, "rats", nil},
+				{OptionalNode, 15, 18, 15, 18, "", []Node{
+					{TextNode, 16, 17, 16, 17, "s", nil},
+				}},
+			}},
+		}}, ast.Nodes[2])
+	})
+
+	t.Run("parses a slash without text on both sides as text", func(t *testing.T) {
+		ast, err := Parse("/ a/ /b")
+		require.NoError(t, err)
+		require.Equal(t, []NodeType{TextNode, TextNode, TextNode, TextNode, TextNode, TextNode, TextNode}, nodeTypes(ast.Nodes))
+		require.Equal(t, "/ a/ /b", ast.Text())
+	})
+
+	t.Run("parses an alternation in an optional", func(t *testing.T) {
+		ast, err := Parse("(a/b)")
+		require.NoError(t, err)
+		require.Equal(t, Node{ExpressionNode, 0, 5, 0, 5, "", []Node{
+			{OptionalNode, 0, 5, 0, 5, "", []Node{
+				{AlternationNode, 1, 4, 1, 4, "", []Node{
+					{AlternativeNode, 1, 2, 1, 2, "", []Node{
+						{TextNode, 1, 2, 1, 2, "a", nil},
+					}},
+					{AlternativeNode, 3, 4, 3, 4, "", []Node{
+						{TextNode, 3, 4, 3, 4, "b", nil},
+					}},
+				}},
+			}},
+		}}, ast)
+	})
+
+	t.Run("parses a parameter in an optional", func(t *testing.T) {
+		ast, err := Parse("I wait( {int} seconds)")
+		require.NoError(t, err)
+		require.Equal(t, []NodeType{TextNode, TextNode, TextNode, OptionalNode}, nodeTypes(ast.Nodes))
+		require.Equal(t, []NodeType{TextNode, ParameterNode, TextNode, TextNode}, nodeTypes(ast.Nodes[3].Nodes))
+	})
+
+	t.Run("parses the name of a parameter as a single text", func(t *testing.T) {
+		ast, err := Parse("{my count:int} {}")
+		require.NoError(t, err)
+		require.Equal(t, []Node{{TextNode, 1, 13, 1, 13, "my count:int", nil}}, ast.Nodes[0].Nodes)
+		require.Equal(t, Node{ParameterNode, 15, 17, 15, 17, "", []Node{}}, ast.Nodes[2])
+	})
+
+	t.Run("parses a '{' without a '}' as text", func(t *testing.T) {
+		ast, err := Parse("{int")
+		require.NoError(t, err)
+		require.Equal(t, []NodeType{TextNode, TextNode}, nodeTypes(ast.Nodes))
+		require.Equal(t, "{int", ast.Text())
+	})
+
+	t.Run("parses an alternation starting with an optional", func(t *testing.T) {
+		ast, err := Parse("(a)b/c")
+		require.NoError(t, err)
+		require.Equal(t, []NodeType{AlternationNode}, nodeTypes(ast.Nodes))
+		require.Equal(t, []NodeType{OptionalNode, TextNode}, nodeTypes(ast.Nodes[0].Nodes[0].Nodes))
+	})
+
+	t.Run("does not parse an alternation without a slash", func(t *testing.T) {
+		ast, err := Parse("a (b) c")
+		require.NoError(t, err)
+		require.Equal(t, []NodeType{TextNode, TextNode, OptionalNode, TextNode, TextNode}, nodeTypes(ast.Nodes))
+	})
+
+	t.Run("unescapes escaped characters", func(t *testing.T) {
+		ast, err := Parse(`\\(\\{a}\\/ b\c`)
+		require.NoError(t, err)
+		require.Equal(t, []Node{
+			{TextNode, 0, 7, 0, 7, "({a", nil},
+			{TextNode, 7, 8, 7, 8, "}", nil},
+			{TextNode, 8, 11, 8, 11, "/", nil},
+			{TextNode, 11, 12, 11, 12, " ", nil},
+			{TextNode, 12, 15, 12, 15, `b\c`, nil},
+		}, ast.Nodes)
+		require.Equal(t, `({a}/ b\c`, ast.Text())
+	})
+
+	t.Run("parses an escaped newline as whitespace", func(t *testing.T) {
+		ast, err := Parse("a \\\n b")
+		require.NoError(t, err)
+		require.Equal(t, []Node{
+			{TextNode, 0, 1, 0, 1, "a", nil},
+			{TextNode, 1, 5, 1, 5, " \\\n ", nil},
+			{TextNode, 5, 6, 5, 6, "b", nil},
+		}, ast.Nodes)
+	})
+
+	t.Run("measures offsets in runes and bytes", func(t *testing.T) {
+		ast, err := Parse("ñ {int}")
+		require.NoError(t, err)
+		require.Equal(t, 2, ast.Nodes[2].Start)
+		require.Equal(t, 3, ast.Nodes[2].ByteStart)
+		require.Equal(t, 7, ast.End)
+		require.Equal(t, 8, ast.ByteEnd)
+	})
+
+	t.Run("measures the offsets of alternatives in bytes", func(t *testing.T) {
+		ast, err := Parse("größe/大きさ 🥒")
+		require.NoError(t, err)
+		alternation := ast.Nodes[0]
+		require.Equal(t, []int{0, 6, 0, 8}, []int{alternation.Nodes[0].Start, alternation.Nodes[1].Start, alternation.Nodes[0].ByteStart, alternation.Nodes[1].ByteStart})
+		require.Equal(t, []int{9, 17}, []int{alternation.End, alternation.ByteEnd})
+		require.Equal(t, "🥒", "größe/大きさ 🥒"[ast.Nodes[2].ByteStart:ast.Nodes[2].ByteEnd])
+	})
+
+	t.Run("parses an unmatched closing brace as text", func(t *testing.T) {
+		ast, err := Parse("a) b}")
+		require.NoError(t, err)
+		require.Equal(t, "a) b}", ast.Text())
+	})
+
+	for _, example := range []struct {
+		expression string
+		message    string
+	}{
+		{"three (blind mice", `This Cucumber Expression has a problem at column 7:
+
+three (blind mice
+      ^
+The '(' does not have a matching ')'.
+If you did not intend to use optional text you can use '\\(' to escape the '('`},
+		{"{(int)}", `This Cucumber Expression has a problem at column 2:
+
+{(int)}
+ ^
+Parameter names may not contain '{', '(', ')' or '/'.
+Did you mean to use a regular expression?`},
+		{`\\(a) (b c`, `This Cucumber Expression has a problem at column 7:
+
+\\(a) (b c
+      ^
+The '(' does not have a matching ')'.
+If you did not intend to use optional text you can use '\\(' to escape the '('`},
+		{"x/{int}", "Parameter types cannot be alternative: x/{int}"},
+		{"a/b{int}", "Parameter types cannot be alternative: a/b{int}"},
+		{"{int}/x", "Parameter types cannot be alternative: {int}/x"},
+	} {
+		example := example
+		t.Run("does not parse "+example.expression, func(t *testing.T) {
+			_, err := Parse(example.expression)
+			require.EqualError(t, err, example.message)
+		})
+	}
+
+	t.Run("points at every rune of a span", func(t *testing.T) {
+		require.Equal(t, "  ^", pointAtSpan(2, 3))
+		require.Equal(t, "  ^-^", pointAtSpan(2, 5))
+	})
+
+	t.Run("returns errors with the position of the problem", func(t *testing.T) {
+		_, err := Parse("three (blind mice")
+		var missingEndTokenError *MissingEndTokenError
+		require.True(t, errors.As(err, &missingEndTokenError))
+		require.Equal(t, "three (blind mice", missingEndTokenError.Expression)
+		require.Equal(t, 6, missingEndTokenError.Start)
+		require.Equal(t, 7, missingEndTokenError.End)
+		require.Equal(t, "(", missingEndTokenError.BeginSymbol)
+		require.Equal(t, ")", missingEndTokenError.EndSymbol)
+
+		_, err = Parse("{a(b}")
+		var nameError *InvalidParameterTypeNameError
+		require.True(t, errors.As(err, &nameError))
+		require.Equal(t, 2, nameError.Start)
+
+		_, err = Parse("a/b({int}) c")
+		var parameterInAlternativeError *ParameterInAlternativeError
+		require.True(t, errors.As(err, &parameterInAlternativeError))
+		require.Equal(t, []int{4, 9}, []int{parameterInAlternativeError.Start, parameterInAlternativeError.End})
+	})
+}
+
+func TestParseWithRecovery(t *testing.T) {
+	t.Run("has no diagnostics for a valid expression", func(t *testing.T) {
+		recovered, diagnostics := ParseWithRecovery("three (blind) {int} mice")
+		require.Empty(t, diagnostics)
+		parsed, err := Parse("three (blind) {int} mice")
+		require.NoError(t, err)
+		require.Equal(t, parsed, recovered)
+	})
+
+	t.Run("reads an unmatched token as text", func(t *testing.T) {
+		ast, diagnostics := ParseWithRecovery("three (blind mice")
+		require.Equal(t, []Diagnostic{
+			{6, 7, 6, 7, "The '(' does not have a matching ')'", MissingEndTokenCode, SeverityError, diagnostics[0].Err},
+		}, diagnostics)
+		var missingEndTokenError *MissingEndTokenError
+		require.True(t, errors.As(diagnostics[0].Err, &missingEndTokenError))
+		require.Equal(t, "three (blind mice", ast.Text())
+		require.Equal(t, []NodeType{TextNode, TextNode, TextNode, TextNode, TextNode, TextNode}, nodeTypes(ast.Nodes))
+	})
+
+	t.Run("keeps the parts of the expression that parse", func(t *testing.T) {
+		ast, diagnostics := ParseWithRecovery("{int} (a/b) (c {x")
+		require.Len(t, diagnostics, 1)
+		require.Equal(t, []NodeType{ParameterNode, TextNode, OptionalNode, TextNode, TextNode, TextNode, TextNode, TextNode, TextNode}, nodeTypes(ast.Nodes))
+	})
+
+	t.Run("reports every problem in order", func(t *testing.T) {
+		_, diagnostics := ParseWithRecovery("{c(d} x/{int} (e")
+		var starts []int
+		var messages []string
+		var codes []ErrorCode
+		for _, diagnostic := range diagnostics {
+			starts = append(starts, diagnostic.Start)
+			messages = append(messages, diagnostic.Message)
+			codes = append(codes, diagnostic.Code)
+		}
+		require.Equal(t, []int{2, 8, 14}, starts)
+		require.Equal(t, []ErrorCode{InvalidParameterTypeNameCode, ParameterInAlternativeCode, MissingEndTokenCode}, codes)
+		require.Equal(t, []string{
+			"Parameter names may not contain '{', '(', ')' or '/'",
+			"Parameter types cannot be alternative: {c(d} x/{int} (e",
+			"The '(' does not have a matching ')'",
+		}, messages)
+	})
+
+	t.Run("measures diagnostics in runes and bytes", func(t *testing.T) {
+		_, diagnostics := ParseWithRecovery("drei (Mäuse")
+		require.Equal(t, []int{5, 6, 5, 6}, []int{diagnostics[0].Start, diagnostics[0].End, diagnostics[0].ByteStart, diagnostics[0].ByteEnd})
+		_, diagnostics = ParseWithRecovery("Mäuse (drei")
+		require.Equal(t, []int{6, 7, 7, 8}, []int{diagnostics[0].Start, diagnostics[0].End, diagnostics[0].ByteStart, diagnostics[0].ByteEnd})
+	})
+
+	t.Run("reads a single escape character as text", func(t *testing.T) {
+		ast, diagnostics := ParseWithRecovery(`mice\`)
+		require.Empty(t, diagnostics)
+		require.Equal(t, []Node{{TextNode, 0, 5, 0, 5, `mice\`, nil}}, ast.Nodes)
+	})
+
+	t.Run("reads an escaped end of line as text", func(t *testing.T) {
+		ast, diagnostics := ParseWithRecovery(`(mice \\`)
+		require.Equal(t, []ErrorCode{MissingEndTokenCode, EscapedEndOfLineCode}, []ErrorCode{diagnostics[0].Code, diagnostics[1].Code})
+		require.Equal(t, []int{0, 6}, []int{diagnostics[0].Start, diagnostics[1].Start})
+		require.Equal(t, Node{TextNode, 6, 8, 6, 8, `\\`, nil}, ast.Nodes[3])
+	})
+}
+
+func nodeTypes(nodes []Node) []NodeType {
+	types := make([]NodeType, len(nodes))
+	for i, node := range nodes {
+		types[i] = node.NodeType
+	}
+	return types
+}
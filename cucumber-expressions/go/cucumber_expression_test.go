@@ -1,11 +1,14 @@
 package cucumberexpressions
 
 import (
+	"errors"
 	"fmt"
 	"github.com/stretchr/testify/require"
+	"math/big"
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
 )
 
 func TestCucumberExpression(t *testing.T) {
@@ -212,6 +215,51 @@ func TestCucumberExpression(t *testing.T) {
 		)
 	})
 
+	t.Run("matches biginteger", func(t *testing.T) {
+		values := MatchCucumberExpression(t, "{biginteger}", "-123456789012345678901234567890")
+		require.Equal(t, "-123456789012345678901234567890", values[0].(*big.Int).String())
+		require.Nil(t, MatchCucumberExpression(t, "{biginteger}", "1.5"))
+	})
+
+	t.Run("matches bigdecimal", func(t *testing.T) {
+		values := MatchCucumberExpression(t, "{bigdecimal}", "12345678901234567890.10000000000000000001")
+		require.Equal(t, "12345678901234567890.10000000000000000001", values[0].(*big.Float).Text('f', -1))
+		values = MatchCucumberExpression(t, "{bigdecimal}", "-.1")
+		require.Equal(t, "-0.1", values[0].(*big.Float).Text('f', -1))
+	})
+
+	t.Run("matches uuid", func(t *testing.T) {
+		require.Equal(t, []interface{}{"123e4567-e89b-12d3-a456-426614174000"}, MatchCucumberExpression(t, "{uuid}", "123E4567-E89B-12D3-A456-426614174000"))
+		require.Nil(t, MatchCucumberExpression(t, "{uuid}", "123e4567-e89b-12d3-a456"))
+	})
+
+	t.Run("matches duration", func(t *testing.T) {
+		require.Equal(t, []interface{}{250 * time.Millisecond}, MatchCucumberExpression(t, "I wait {duration}", "I wait 250ms"))
+		require.Equal(t, []interface{}{2*time.Hour + 45*time.Minute}, MatchCucumberExpression(t, "I wait {duration}", "I wait 2h45m"))
+		require.Equal(t, []interface{}{3 * time.Second}, MatchCucumberExpression(t, "I wait {duration}", "I wait 3 seconds"))
+		require.Equal(t, []interface{}{time.Second}, MatchCucumberExpression(t, "I wait {duration}", "I wait 1 second"))
+		require.Equal(t, []interface{}{90 * time.Minute}, MatchCucumberExpression(t, "I wait {duration}", "I wait 1.5 hours"))
+		require.Nil(t, MatchCucumberExpression(t, "I wait {duration}", "I wait 3 fortnights"))
+	})
+
+	t.Run("reports durations that overflow", func(t *testing.T) {
+		expression, err := NewCucumberExpression("I wait {duration}", NewParameterTypeRegistry())
+		require.NoError(t, err)
+		for _, text := range []string{"I wait 300000 days", "I wait 9999999999999999999 hours", "I wait 300000.5 days", "I wait 3000000h"} {
+			args, err := expression.Match(text)
+			require.NoError(t, err)
+			require.NotNil(t, args, text)
+			_, err = ArgumentValue[time.Duration](args[0])
+			require.Error(t, err, text)
+		}
+	})
+
+	t.Run("matches bool", func(t *testing.T) {
+		require.Equal(t, []interface{}{true}, MatchCucumberExpression(t, "the flag is {bool}", "the flag is enabled"))
+		require.Equal(t, []interface{}{false}, MatchCucumberExpression(t, "the flag is {bool}", "the flag is no"))
+		require.Nil(t, MatchCucumberExpression(t, "the flag is {bool}", "the flag is not"))
+	})
+
 	t.Run("matches anonymous", func(t *testing.T) {
 		require.Equal(
 			t,
@@ -237,6 +285,37 @@ func TestCucumberExpression(t *testing.T) {
 		_, err := NewCucumberExpression("({int})", parameterTypeRegistry)
 		require.Error(t, err)
 		require.Equal(t, "Parameter types cannot be optional: ({int})", err.Error())
+		var parameterInOptionalError *ParameterInOptionalError
+		require.True(t, errors.As(err, &parameterInOptionalError))
+		require.Equal(t, &ParameterInOptionalError{"({int})", 1, 6}, parameterInOptionalError)
+	})
+
+	t.Run("matches optional parameter types with defaults", func(t *testing.T) {
+		parameterTypeRegistry := NewParameterTypeRegistry()
+		expression, err := NewCucumberExpressionWithDefaults("I wait( {int} seconds) for {word}( in {word})", parameterTypeRegistry, "1", "total")
+		require.NoError(t, err)
+
+		args, err := expression.Match("I wait for cukes")
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{1, "cukes", "total"}, []interface{}{args[0].GetValue(), args[1].GetValue(), args[2].GetValue()})
+
+		args, err = expression.Match("I wait 3 seconds for cukes in basket")
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{3, "cukes", "basket"}, []interface{}{args[0].GetValue(), args[1].GetValue(), args[2].GetValue()})
+	})
+
+	t.Run("does not allow optional parameter types without a default", func(t *testing.T) {
+		parameterTypeRegistry := NewParameterTypeRegistry()
+		_, err := NewCucumberExpressionWithDefaults("I wait( {int} seconds)( {int} times)", parameterTypeRegistry, "1")
+		require.EqualError(t, err, "The expression I wait( {int} seconds)( {int} times) has 2 parameters in optionals, but 1 defaults")
+		require.Equal(t, OptionalParameterDefaultsCode, ErrorCodeOf(err))
+	})
+
+	t.Run("does not allow defaults that don't match their parameter type", func(t *testing.T) {
+		parameterTypeRegistry := NewParameterTypeRegistry()
+		_, err := NewCucumberExpressionWithDefaults("I wait( {int} seconds)", parameterTypeRegistry, "one")
+		require.EqualError(t, err, `The default "one" does not match {int}`)
+		require.Equal(t, DefaultMismatchCode, ErrorCodeOf(err))
 	})
 
 	t.Run("allows escaped optional parameters", func(t *testing.T) {
@@ -252,6 +331,8 @@ func TestCucumberExpression(t *testing.T) {
 		_, err := NewCucumberExpression("x/{int}", parameterTypeRegistry)
 		require.Error(t, err)
 		require.Equal(t, "Parameter types cannot be alternative: x/{int}", err.Error())
+		var parameterInAlternativeError *ParameterInAlternativeError
+		require.True(t, errors.As(err, &parameterInAlternativeError))
 	})
 
 	t.Run("does not allow parameter type/text alternation", func(t *testing.T) {
@@ -263,8 +344,140 @@ func TestCucumberExpression(t *testing.T) {
 
 	t.Run("returns UndefinedParameterTypeExpression for unknown parameter", func(t *testing.T) {
 		parameterTypeRegistry := NewParameterTypeRegistry()
-		_, err := NewCucumberExpression("{unknown}", parameterTypeRegistry)
+		_, err := NewCucumberExpression("I have {unknown}", parameterTypeRegistry)
 		require.Error(t, err)
+		var undefinedParameterTypeError *UndefinedParameterTypeError
+		require.True(t, errors.As(err, &undefinedParameterTypeError))
+		require.Equal(t, "unknown", undefinedParameterTypeError.TypeName)
+		require.Empty(t, undefinedParameterTypeError.Suggestions)
+		require.Equal(t, "I have {unknown}", undefinedParameterTypeError.Expression)
+		require.Equal(t, 7, undefinedParameterTypeError.Start)
+		require.Equal(t, 16, undefinedParameterTypeError.End)
+	})
+
+	t.Run("suggests the closest parameter types for an unknown parameter", func(t *testing.T) {
+		parameterTypeRegistry := NewParameterTypeRegistry()
+		_, err := NewCucumberExpression("I have {in} cukes", parameterTypeRegistry)
+		require.EqualError(t, err, "Undefined parameter type {in}. Did you mean {int}?")
+
+		_, err = NewCucumberExpression("I say {strng}", parameterTypeRegistry)
+		require.EqualError(t, err, "Undefined parameter type {strng}. Did you mean {string}?")
+
+		_, err = NewCucumberExpression("{flat}", parameterTypeRegistry)
+		var undefinedParameterTypeError *UndefinedParameterTypeError
+		require.True(t, errors.As(err, &undefinedParameterTypeError))
+		require.Equal(t, []string{"float"}, undefinedParameterTypeError.Suggestions)
+
+		_, err = NewCucumberExpression("{x}", parameterTypeRegistry)
+		require.EqualError(t, err, "Undefined parameter type {x}")
+	})
+
+	t.Run("matches named parameters", func(t *testing.T) {
+		parameterTypeRegistry := NewParameterTypeRegistry()
+		require.NoError(t, parameterTypeRegistry.SetSyntaxEnabled(NamedParameterSyntax, true))
+		expression, err := NewCucumberExpression("{from:int} of {count:int} {word} in {box:}", parameterTypeRegistry)
+		require.NoError(t, err)
+		args, err := expression.Match("3 of 12 cukes in basket")
+		require.NoError(t, err)
+		require.Equal(t, []string{"from", "count", "", "box"}, []string{args[0].Name(), args[1].Name(), args[2].Name(), args[3].Name()})
+		require.Equal(t, 12, ArgumentByName(args, "count").GetValue())
+		require.Equal(t, "basket", ArgumentByName(args, "box").GetValue())
+		require.Nil(t, ArgumentByName(args, "word"))
+	})
+
+	t.Run("does not allow invalid or duplicate parameter names", func(t *testing.T) {
+		parameterTypeRegistry := NewParameterTypeRegistry()
+		require.NoError(t, parameterTypeRegistry.SetSyntaxEnabled(NamedParameterSyntax, true))
+		_, err := NewCucumberExpression("{my count:int} cukes", parameterTypeRegistry)
+		require.EqualError(t, err, `The parameter name "my count" in {my count:int} cukes must be a letter or '_' followed by letters, digits or '_'`)
+		require.Equal(t, InvalidParameterNameCode, ErrorCodeOf(err))
+		var invalidParameterNameError *InvalidParameterNameError
+		require.True(t, errors.As(err, &invalidParameterNameError))
+		require.Equal(t, &InvalidParameterNameError{"my count", "{my count:int} cukes", 0, 14}, invalidParameterNameError)
+
+		_, err = NewCucumberExpression("{count:int:int} cukes", parameterTypeRegistry)
+		require.EqualError(t, err, "illegal character ':' in parameter name {int:int}")
+
+		_, err = NewCucumberExpression("{count:int} of {count:int} cukes", parameterTypeRegistry)
+		require.EqualError(t, err, `The parameter name "count" is used twice in {count:int} of {count:int} cukes`)
+		require.Equal(t, DuplicateParameterNameCode, ErrorCodeOf(err))
+		var duplicateParameterNameError *DuplicateParameterNameError
+		require.True(t, errors.As(err, &duplicateParameterNameError))
+		require.Equal(t, &DuplicateParameterNameError{"count", "{count:int} of {count:int} cukes", 15, 26}, duplicateParameterNameError)
+	})
+
+	t.Run("requires enabling named parameters", func(t *testing.T) {
+		parameterTypeRegistry := NewParameterTypeRegistry()
+		_, err := NewCucumberExpression("{int} of {count:int} cukes", parameterTypeRegistry)
+		require.EqualError(t, err, `This Cucumber Expression has a problem at column 10:
+
+{int} of {count:int} cukes
+         ^---------^
+{count:int} requires enabling the named-parameter syntax.
+It can be enabled with ParameterTypeRegistry.SetSyntaxEnabled("named-parameter", true)`)
+		require.Equal(t, SyntaxNotEnabledCode, ErrorCodeOf(err))
+		var syntaxNotEnabledError *SyntaxNotEnabledError
+		require.True(t, errors.As(err, &syntaxNotEnabledError))
+		require.Equal(t, NamedParameterSyntax, syntaxNotEnabledError.Feature)
+		require.Equal(t, 9, syntaxNotEnabledError.Start)
+		require.Equal(t, 20, syntaxNotEnabledError.End)
+
+		require.False(t, parameterTypeRegistry.SyntaxEnabled(NamedParameterSyntax))
+		require.True(t, parameterTypeRegistry.SyntaxEnabled(OptionalTextSyntax))
+		err = parameterTypeRegistry.SetSyntaxEnabled(OptionalTextSyntax, false)
+		require.EqualError(t, err, "The optional-text syntax can't be enabled or disabled")
+		require.Equal(t, SyntaxNotSwitchableCode, ErrorCodeOf(err))
+
+		clone := parameterTypeRegistry.Clone()
+		require.NoError(t, clone.SetSyntaxEnabled(NamedParameterSyntax, true))
+		require.False(t, parameterTypeRegistry.SyntaxEnabled(NamedParameterSyntax))
+		_, err = NewCucumberExpression("{int} of {count:int} cukes", clone)
+		require.NoError(t, err)
+	})
+
+	t.Run("reads an escape character before an optional as text", func(t *testing.T) {
+		ast, err := Parse(`I have \(x\)`)
+		require.NoError(t, err)
+		require.Equal(t, []NodeType{TextNode, TextNode, TextNode, TextNode, TextNode, OptionalNode}, nodeTypes(ast.Nodes))
+		require.Equal(t, []interface{}{}, MatchCucumberExpression(t, `I have \(x\)`, `I have \x\`))
+		require.Equal(t, []interface{}{}, MatchCucumberExpression(t, `I have \(x\)`, `I have \`))
+		require.Nil(t, MatchCucumberExpression(t, `I have \(x\)`, `I have (x)`))
+	})
+
+	t.Run("matches an alternation in an optional", func(t *testing.T) {
+		ast, err := Parse("(a/b)")
+		require.NoError(t, err)
+		require.Equal(t, []NodeType{AlternationNode}, nodeTypes(ast.Nodes[0].Nodes))
+		for _, text := range []string{"", "a", "b"} {
+			require.Equal(t, []interface{}{}, MatchCucumberExpression(t, "(a/b)", text), text)
+		}
+		require.Nil(t, MatchCucumberExpression(t, "(a/b)", "a/b"))
+	})
+
+	t.Run("does not allow parameters after an alternation in the same word", func(t *testing.T) {
+		for _, expr := range []string{"a/b{int}", "{int}/x"} {
+			_, parseErr := Parse(expr)
+			_, err := NewCucumberExpression(expr, NewParameterTypeRegistry())
+			require.EqualError(t, err, "Parameter types cannot be alternative: "+expr)
+			require.Equal(t, parseErr, err)
+		}
+	})
+
+	t.Run("parses parameters in optionals that need defaults", func(t *testing.T) {
+		_, err := Parse("I wait( {int} seconds)")
+		require.NoError(t, err)
+		_, err = NewCucumberExpression("I wait( {int} seconds)", NewParameterTypeRegistry())
+		var parameterInOptionalError *ParameterInOptionalError
+		require.True(t, errors.As(err, &parameterInOptionalError))
+		_, err = NewCucumberExpressionWithDefaults("I wait( {int} seconds)", NewParameterTypeRegistry(), "1")
+		require.NoError(t, err)
+	})
+
+	t.Run("returns the errors of Parse", func(t *testing.T) {
+		_, err := NewCucumberExpression("three (blind mice", NewParameterTypeRegistry())
+		var missingEndTokenError *MissingEndTokenError
+		require.True(t, errors.As(err, &missingEndTokenError))
+		require.Contains(t, err.Error(), "three (blind mice\n      ^\n")
 	})
 
 	t.Run("exposes source", func(t *testing.T) {
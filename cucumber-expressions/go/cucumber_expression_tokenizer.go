@@ -0,0 +1,119 @@
+package cucumberexpressions
+
+import (
+	"iter"
+	"strings"
+)
+
+// Tokenize splits an expression into tokens, between a start of line and
+// an end of line token, without parsing it. Consecutive text and whitespace
+// are a single token, and escaped characters are text. It returns an
+// EscapedEndOfLineError when the expression ends with a double escape
+// character, together with the tokens, where they are text.
+func Tokenize(expression string) ([]Token, error) {
+	return tokenize(expression, "")
+}
+
+// Tokens yields the tokens of an expression like Tokenize, as they are
+// found. It doesn't report problems, a double escape character at the end
+// is yielded as text.
+func Tokens(expression string) iter.Seq[Token] {
+	return func(yield func(Token) bool) {
+		scanTokens(expression, "", yield)
+	}
+}
+
+// tokenize tokenizes an expression with the characters of boundaries as
+// whitespace, so they end alternations like whitespace does
+func tokenize(expression string, boundaries string) ([]Token, error) {
+	var tokens []Token
+	err := scanTokens(expression, boundaries, func(token Token) bool {
+		tokens = append(tokens, token)
+		return true
+	})
+	return tokens, err
+}
+
+// scanTokens passes the tokens of an expression to emit, until it returns
+// false. A double escape character escapes the '(', '{' or '/' after it, as
+// the expression would otherwise read it as the start of an optional, a
+// parameter or an alternative. Other escape characters are text, except
+// before a newline, which they escape as whitespace. A double escape
+// character at the end escapes nothing, so it is an error, which is
+// returned once the tokens are emitted.
+func scanTokens(expression string, boundaries string, emit func(Token) bool) error {
+	runes := []rune(expression)
+	if !emit(Token{"", StartOfLineToken, 0, 0, 0, 0}) {
+		return nil
+	}
+
+	var buffer []rune
+	previousTokenType := StartOfLineToken
+	escaped := 0
+	bufferStartIndex := 0
+	bufferStartByte := 0
+
+	convertBufferToToken := func(tokenType TokenType) Token {
+		escapeTokens := 0
+		if tokenType == TextToken {
+			escapeTokens = escaped
+			escaped = 0
+		}
+		consumedIndex := bufferStartIndex + len(buffer) + escapeTokens
+		consumedByte := bufferStartByte + len(string(buffer)) + escapeTokens
+		t := Token{string(buffer), tokenType, bufferStartIndex, consumedIndex, bufferStartByte, consumedByte}
+		buffer = nil
+		bufferStartIndex = consumedIndex
+		bufferStartByte = consumedByte
+		return t
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		currentTokenType := typeOf(r)
+		switch {
+		case isEscape(runes, i):
+			escaped += 2
+			i += 2
+			r = runes[i]
+			currentTokenType = TextToken
+		case r == escapeCharacter && i+1 < len(runes) && runes[i+1] == '\n',
+			strings.ContainsRune(boundaries, r):
+			currentTokenType = WhiteSpaceToken
+		}
+
+		if shouldCreateNewToken(previousTokenType, currentTokenType) && !emit(convertBufferToToken(previousTokenType)) {
+			return nil
+		}
+		previousTokenType = currentTokenType
+		buffer = append(buffer, r)
+	}
+
+	if len(buffer) > 0 && !emit(convertBufferToToken(previousTokenType)) {
+		return nil
+	}
+
+	if !emit(Token{"", EndOfLineToken, len(runes), len(runes), len(expression), len(expression)}) {
+		return nil
+	}
+	if n := len(runes); n >= 2 && runes[n-2] == escapeCharacter && runes[n-1] == escapeCharacter {
+		return &EscapedEndOfLineError{expression, n - 2, n}
+	}
+	return nil
+}
+
+// isEscape tells if the runes at i are a double escape character followed
+// by a character it escapes
+func isEscape(runes []rune, i int) bool {
+	return i+2 < len(runes) && runes[i] == escapeCharacter && runes[i+1] == escapeCharacter && canEscape(runes[i+2])
+}
+
+func shouldCreateNewToken(previousTokenType TokenType, currentTokenType TokenType) bool {
+	if previousTokenType == StartOfLineToken {
+		return false
+	}
+	if currentTokenType != previousTokenType {
+		return true
+	}
+	return currentTokenType != WhiteSpaceToken && currentTokenType != TextToken
+}
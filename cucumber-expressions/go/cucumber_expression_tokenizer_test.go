@@ -0,0 +1,144 @@
+package cucumberexpressions
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCucumberExpressionTokenizer(t *testing.T) {
+	t.Run("tokenizes an empty expression", func(t *testing.T) {
+		tokens, err := Tokenize("")
+		require.NoError(t, err)
+		require.Equal(t, []Token{
+			{"", StartOfLineToken, 0, 0, 0, 0},
+			{"", EndOfLineToken, 0, 0, 0, 0},
+		}, tokens)
+	})
+
+	t.Run("joins consecutive text and whitespace", func(t *testing.T) {
+		tokens, err := Tokenize("three  mice")
+		require.NoError(t, err)
+		require.Equal(t, []Token{
+			{"", StartOfLineToken, 0, 0, 0, 0},
+			{"three", TextToken, 0, 5, 0, 5},
+			{"  ", WhiteSpaceToken, 5, 7, 5, 7},
+			{"mice", TextToken, 7, 11, 7, 11},
+			{"", EndOfLineToken, 11, 11, 11, 11},
+		}, tokens)
+	})
+
+	t.Run("does not join consecutive symbols", func(t *testing.T) {
+		tokens, err := Tokenize("(({}))//")
+		require.NoError(t, err)
+		require.Equal(t, []TokenType{
+			StartOfLineToken, BeginOptionalToken, BeginOptionalToken, BeginParameterToken, EndParameterToken,
+			EndOptionalToken, EndOptionalToken, AlternationToken, AlternationToken, EndOfLineToken,
+		}, tokenTypes(tokens))
+	})
+
+	t.Run("counts escape characters in the offsets of text", func(t *testing.T) {
+		tokens, err := Tokenize(`\\(a) b`)
+		require.NoError(t, err)
+		require.Equal(t, []Token{
+			{"", StartOfLineToken, 0, 0, 0, 0},
+			{"(a", TextToken, 0, 4, 0, 4},
+			{")", EndOptionalToken, 4, 5, 4, 5},
+			{" ", WhiteSpaceToken, 5, 6, 5, 6},
+			{"b", TextToken, 6, 7, 6, 7},
+			{"", EndOfLineToken, 7, 7, 7, 7},
+		}, tokens)
+	})
+
+	t.Run("tokenizes other escape characters as text", func(t *testing.T) {
+		tokens, err := Tokenize(`a\b \\c\`)
+		require.NoError(t, err)
+		require.Equal(t, []Token{
+			{"", StartOfLineToken, 0, 0, 0, 0},
+			{`a\b`, TextToken, 0, 3, 0, 3},
+			{" ", WhiteSpaceToken, 3, 4, 3, 4},
+			{`\\c\`, TextToken, 4, 8, 4, 8},
+			{"", EndOfLineToken, 8, 8, 8, 8},
+		}, tokens)
+	})
+
+	t.Run("does not tokenize an escaped end of line", func(t *testing.T) {
+		tokens, err := Tokenize(`a \\`)
+		require.EqualError(t, err, `This Cucumber Expression has a problem at column 3:
+
+a \\
+  ^^
+The end of line can not be escaped.
+'\\' only escapes a '(', '{' or '/' after it`)
+		require.Equal(t, EscapedEndOfLineCode, ErrorCodeOf(err))
+		var escapedEndOfLineError *EscapedEndOfLineError
+		require.True(t, errors.As(err, &escapedEndOfLineError))
+		require.Equal(t, &EscapedEndOfLineError{`a \\`, 2, 4}, escapedEndOfLineError)
+		require.Equal(t, []Token{
+			{"", StartOfLineToken, 0, 0, 0, 0},
+			{"a", TextToken, 0, 1, 0, 1},
+			{" ", WhiteSpaceToken, 1, 2, 1, 2},
+			{`\\`, TextToken, 2, 4, 2, 4},
+			{"", EndOfLineToken, 4, 4, 4, 4},
+		}, tokens)
+
+		_, err = NewCucumberExpression(`a \\`, NewParameterTypeRegistry())
+		require.Equal(t, EscapedEndOfLineCode, ErrorCodeOf(err))
+	})
+
+	t.Run("tokenizes an escaped newline as whitespace", func(t *testing.T) {
+		tokens, err := Tokenize("a \\\nb")
+		require.NoError(t, err)
+		require.Equal(t, Token{" \\\n", WhiteSpaceToken, 1, 4, 1, 4}, tokens[2])
+	})
+
+	t.Run("measures offsets in runes and bytes", func(t *testing.T) {
+		tokens, err := Tokenize(`ñ\\(ü`)
+		require.NoError(t, err)
+		require.Equal(t, []Token{
+			{"", StartOfLineToken, 0, 0, 0, 0},
+			{"ñ(ü", TextToken, 0, 5, 0, 7},
+			{"", EndOfLineToken, 5, 5, 7, 7},
+		}, tokens)
+	})
+
+	t.Run("yields the tokens of an expression", func(t *testing.T) {
+		expected, err := Tokenize("a {int} (b)")
+		require.NoError(t, err)
+		var tokens []Token
+		for token := range Tokens("a {int} (b)") {
+			tokens = append(tokens, token)
+		}
+		require.Equal(t, expected, tokens)
+
+		tokens = nil
+		for token := range Tokens("a {int} (b)") {
+			if token.TokenType == BeginParameterToken {
+				break
+			}
+			tokens = append(tokens, token)
+		}
+		require.Equal(t, expected[:3], tokens)
+	})
+
+	t.Run("marshals tokens to JSON", func(t *testing.T) {
+		tokens, err := Tokenize("{int}")
+		require.NoError(t, err)
+		data, err := json.Marshal(tokens[1:4])
+		require.NoError(t, err)
+		require.JSONEq(t, `[
+			{"text": "{", "type": "BEGIN_PARAMETER", "start": 0, "end": 1, "byteStart": 0, "byteEnd": 1},
+			{"text": "int", "type": "TEXT", "start": 1, "end": 4, "byteStart": 1, "byteEnd": 4},
+			{"text": "}", "type": "END_PARAMETER", "start": 4, "end": 5, "byteStart": 4, "byteEnd": 5}
+		]`, string(data))
+	})
+}
+
+func tokenTypes(tokens []Token) []TokenType {
+	types := make([]TokenType, len(tokens))
+	for i, t := range tokens {
+		types[i] = t.TokenType
+	}
+	return types
+}
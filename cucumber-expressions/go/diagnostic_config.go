@@ -0,0 +1,35 @@
+package cucumberexpressions
+
+// Severity is how serious a diagnostic is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+	// SeverityOff suppresses diagnostics
+	SeverityOff Severity = "off"
+)
+
+// DiagnosticConfig changes the severity of diagnostics by code, such as
+// {"severities": {"CE104": "warning", "GH101": "off"}} in JSON. Gherkin
+// reads the same JSON, so a project can have one config for both.
+type DiagnosticConfig struct {
+	Severities map[ErrorCode]Severity `json:"severities"`
+}
+
+// Apply returns the diagnostics with the severities of the config, without
+// the ones it turns off
+func (c DiagnosticConfig) Apply(diagnostics []Diagnostic) []Diagnostic {
+	var result []Diagnostic
+	for _, diagnostic := range diagnostics {
+		if severity, ok := c.Severities[diagnostic.Code]; ok {
+			diagnostic.Severity = severity
+		}
+		if diagnostic.Severity == SeverityOff {
+			continue
+		}
+		result = append(result, diagnostic)
+	}
+	return result
+}
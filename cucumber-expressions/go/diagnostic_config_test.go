@@ -0,0 +1,30 @@
+package cucumberexpressions
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnosticConfig(t *testing.T) {
+	severities := func(diagnostics []Diagnostic) []string {
+		result := []string{}
+		for _, diagnostic := range diagnostics {
+			result = append(result, string(diagnostic.Code)+" "+string(diagnostic.Severity))
+		}
+		return result
+	}
+
+	t.Run("keeps the diagnostics without a config", func(t *testing.T) {
+		_, diagnostics := ParseWithRecovery("{a(b} (c")
+		require.Equal(t, []string{"CE104 error", "CE102 error"}, severities(DiagnosticConfig{}.Apply(diagnostics)))
+	})
+
+	t.Run("changes severities and turns diagnostics off", func(t *testing.T) {
+		var config DiagnosticConfig
+		require.NoError(t, json.Unmarshal([]byte(`{"severities": {"CE104": "warning", "CE102": "off", "GH101": "off"}}`), &config))
+		_, diagnostics := ParseWithRecovery("{a(b} (c")
+		require.Equal(t, []string{"CE104 warning"}, severities(config.Apply(diagnostics)))
+	})
+}
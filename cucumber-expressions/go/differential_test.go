@@ -0,0 +1,150 @@
+package cucumberexpressions
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestDifferential compares matching and expression generation with a
+// reference implementation. It only runs when
+// CUCUMBER_EXPRESSIONS_REFERENCE is set to a command speaking the protocol
+// of scripts/reference-match.js, e.g.
+//
+//	CUCUMBER_EXPRESSIONS_REFERENCE="node scripts/reference-match.js" go test -run TestDifferential
+func TestDifferential(t *testing.T) {
+	command := strings.Fields(os.Getenv("CUCUMBER_EXPRESSIONS_REFERENCE"))
+	if len(command) == 0 {
+		t.Skip("CUCUMBER_EXPRESSIONS_REFERENCE is not set")
+	}
+
+	requests := differentialCorpus(t)
+	var stdin bytes.Buffer
+	for _, request := range requests {
+		line, err := json.Marshal(request)
+		require.NoError(t, err)
+		stdin.Write(append(line, '\n'))
+	}
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = &stdin
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.Output()
+	require.NoError(t, err)
+
+	scanner := bufio.NewScanner(bytes.NewReader(stdout))
+	for _, request := range requests {
+		require.True(t, scanner.Scan(), "reference implementation returned fewer results than requests")
+		var expected differentialResult
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &expected))
+		actual := differentialRun(request)
+
+		if (expected.Error == "") != (actual.Error == "") {
+			t.Errorf("%s / %s\n  reference error: %q\n  go error:        %q", *request.Expression, request.Text, expected.Error, actual.Error)
+			continue
+		}
+		if canonicalJson(t, expected.Args) != canonicalJson(t, actual.Args) {
+			t.Errorf("%s / %s\n  reference args: %s\n  go args:        %s", *request.Expression, request.Text, canonicalJson(t, expected.Args), canonicalJson(t, actual.Args))
+		}
+		if canonicalJson(t, expected.Generated) != canonicalJson(t, actual.Generated) {
+			t.Errorf("generating from %s\n  reference: %s\n  go:        %s", request.Text, canonicalJson(t, expected.Generated), canonicalJson(t, actual.Generated))
+		}
+	}
+}
+
+type differentialRequest struct {
+	Expression *string `json:"expression,omitempty"`
+	Text       string  `json:"text"`
+}
+
+type differentialResult struct {
+	Args      interface{} `json:"args"`
+	Generated []string    `json:"generated"`
+	Error     string      `json:"error"`
+}
+
+// differentialCorpus combines examples.txt with generated samples, and
+// asks for expressions to be generated from every text.
+func differentialCorpus(t *testing.T) []differentialRequest {
+	examples, err := ioutil.ReadFile("./examples.txt")
+	require.NoError(t, err)
+	var requests []differentialRequest
+	for _, chunk := range strings.Split(string(examples), "---") {
+		lines := strings.Split(strings.TrimSpace(chunk), "\n")
+		expression := lines[0]
+		requests = append(requests, differentialRequest{Expression: &expression, Text: lines[1]})
+	}
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		sample := ExpressionSample{}.Generate(random, 8).Interface().(ExpressionSample)
+		requests = append(requests,
+			differentialRequest{Expression: &sample.Expression, Text: sample.Text},
+			differentialRequest{Expression: &sample.Expression, Text: sample.NearMiss},
+			differentialRequest{Text: sample.Text},
+		)
+	}
+	return requests
+}
+
+func differentialRun(request differentialRequest) (result differentialResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = differentialResult{Error: "panic"}
+		}
+	}()
+	parameterTypeRegistry := NewParameterTypeRegistry()
+	if request.Expression == nil {
+		result.Generated = []string{}
+		for _, generatedExpression := range NewCucumberExpressionGenerator(parameterTypeRegistry).GenerateExpressions(request.Text) {
+			result.Generated = append(result.Generated, generatedExpression.Source())
+		}
+		return result
+	}
+
+	var expression Expression
+	source := *request.Expression
+	if strings.HasPrefix(source, "/") && strings.HasSuffix(source, "/") {
+		r, err := regexp.Compile(source[1 : len(source)-1])
+		if err != nil {
+			return differentialResult{Error: err.Error()}
+		}
+		expression = NewRegularExpression(r, parameterTypeRegistry)
+	} else {
+		var err error
+		expression, err = NewCucumberExpression(source, parameterTypeRegistry)
+		if err != nil {
+			return differentialResult{Error: err.Error()}
+		}
+	}
+	args, err := expression.Match(request.Text)
+	if err != nil {
+		return differentialResult{Error: err.Error()}
+	}
+	if args != nil {
+		values := make([]interface{}, len(args))
+		for i, arg := range args {
+			values[i] = arg.GetValue()
+		}
+		result.Args = values
+	}
+	return result
+}
+
+// canonicalJson makes Go values and values decoded from JSON comparable
+func canonicalJson(t *testing.T, value interface{}) string {
+	encoded, err := json.Marshal(value)
+	require.NoError(t, err)
+	var decoded interface{}
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	encoded, err = json.Marshal(decoded)
+	require.NoError(t, err)
+	return string(encoded)
+}
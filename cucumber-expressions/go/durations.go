@@ -0,0 +1,57 @@
+package cucumberexpressions
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// durationUnits are the units of durations written in words, such as
+// "3 seconds", by singular and plural name
+var durationUnits = map[string]time.Duration{
+	"nanosecond":   time.Nanosecond,
+	"nanoseconds":  time.Nanosecond,
+	"microsecond":  time.Microsecond,
+	"microseconds": time.Microsecond,
+	"millisecond":  time.Millisecond,
+	"milliseconds": time.Millisecond,
+	"second":       time.Second,
+	"seconds":      time.Second,
+	"minute":       time.Minute,
+	"minutes":      time.Minute,
+	"hour":         time.Hour,
+	"hours":        time.Hour,
+	"day":          24 * time.Hour,
+	"days":         24 * time.Hour,
+}
+
+var durationInWordsRegexp = regexp.MustCompile(`^([-+]?\d*\.?\d+) ([a-z]+)$`)
+
+// parseDuration parses durations in the syntax of time.ParseDuration, such
+// as "2h45m", and in words, such as "3 seconds" or "1.5 hours"
+func parseDuration(text string) (time.Duration, error) {
+	match := durationInWordsRegexp.FindStringSubmatch(text)
+	if match == nil {
+		return time.ParseDuration(text)
+	}
+	unit, ok := durationUnits[match[2]]
+	if !ok {
+		return 0, &strconv.NumError{Func: "parseDuration", Num: text, Err: strconv.ErrSyntax}
+	}
+	if n, err := strconv.ParseInt(match[1], 10, 64); err == nil {
+		if n > math.MaxInt64/int64(unit) || n < math.MinInt64/int64(unit) {
+			return 0, &strconv.NumError{Func: "parseDuration", Num: text, Err: strconv.ErrRange}
+		}
+		return time.Duration(n) * unit, nil
+	}
+	f, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	d := f * float64(unit)
+	if d >= math.MaxInt64 || d <= math.MinInt64 {
+		return 0, &strconv.NumError{Func: "parseDuration", Num: text, Err: strconv.ErrRange}
+	}
+	return time.Duration(d), nil
+}
@@ -0,0 +1,170 @@
+package cucumberexpressions
+
+import (
+	"errors"
+)
+
+// ErrorCode identifies the kind of an error, so tools can link it to its
+// documentation or suppress it. Unlike messages, codes are the same in
+// every language and don't change between releases. Codes of problems with
+// an expression start at CE101, of parameter types at CE201, of arguments
+// at CE301 and of routing at CE401.
+type ErrorCode string
+
+const (
+	UndefinedParameterTypeCode               ErrorCode = "CE101"
+	MissingEndTokenCode                      ErrorCode = "CE102"
+	InvalidParameterTypeNameCode             ErrorCode = "CE104"
+	ParameterInOptionalCode                  ErrorCode = "CE107"
+	ParameterInAlternativeCode               ErrorCode = "CE108"
+	AmbiguousParameterTypeCode               ErrorCode = "CE109"
+	CouldNotParseCode                        ErrorCode = "CE110"
+	OptionalParameterDefaultsCode            ErrorCode = "CE111"
+	DefaultMismatchCode                      ErrorCode = "CE112"
+	InvalidParameterNameCode                 ErrorCode = "CE113"
+	DuplicateParameterNameCode               ErrorCode = "CE114"
+	SyntaxNotEnabledCode                     ErrorCode = "CE115"
+	EscapedEndOfLineCode                     ErrorCode = "CE116"
+	AnonymousParameterTypeAlreadyDefinedCode ErrorCode = "CE201"
+	ParameterTypeAlreadyDefinedCode          ErrorCode = "CE202"
+	PreferentialParameterTypeConflictCode    ErrorCode = "CE203"
+	IllegalParameterNameCharacterCode        ErrorCode = "CE204"
+	ParameterTypeRegexpFlagsCode             ErrorCode = "CE205"
+	UnknownTransformCode                     ErrorCode = "CE206"
+	UnsupportedTimeLayoutCode                ErrorCode = "CE207"
+	InvalidBoundaryCode                      ErrorCode = "CE208"
+	AmbiguousBoolWordCode                    ErrorCode = "CE209"
+	EmptyEnumCode                            ErrorCode = "CE210"
+	InvalidTemplateFunctionNameCode          ErrorCode = "CE211"
+	TemplateFunctionAlreadyDefinedCode       ErrorCode = "CE212"
+	SyntaxNotSwitchableCode                  ErrorCode = "CE213"
+	TransformFailedCode                      ErrorCode = "CE301"
+	ArgumentTypeMismatchCode                 ErrorCode = "CE302"
+	TemplateFailedCode                       ErrorCode = "CE303"
+	ArgumentOutOfRangeCode                   ErrorCode = "CE304"
+	InvalidHandlerCode                       ErrorCode = "CE401"
+	HandlerArgumentCountCode                 ErrorCode = "CE402"
+	NoRouteCode                              ErrorCode = "CE403"
+	AmbiguousRouteCode                       ErrorCode = "CE404"
+	UnknownArgumentFieldCode                 ErrorCode = "CE405"
+	UnexportedArgumentFieldCode              ErrorCode = "CE406"
+	HandlerPanicCode                         ErrorCode = "CE407"
+	HandlerParameterTypeCode                 ErrorCode = "CE408"
+)
+
+// errorCodes are the codes of the errors that are only a message of the
+// catalog
+var errorCodes = map[MessageKey]ErrorCode{
+	OptionalParameterDefaultsMessage:         OptionalParameterDefaultsCode,
+	DefaultMismatchMessage:                   DefaultMismatchCode,
+	PreferentialParameterTypeConflictMessage: PreferentialParameterTypeConflictCode,
+	IllegalParameterNameCharacterMessage:     IllegalParameterNameCharacterCode,
+	ParameterTypeRegexpFlagsMessage:          ParameterTypeRegexpFlagsCode,
+	UnknownTransformMessage:                  UnknownTransformCode,
+	UnsupportedTimeLayoutMessage:             UnsupportedTimeLayoutCode,
+	InvalidBoundaryMessage:                   InvalidBoundaryCode,
+	AmbiguousBoolWordMessage:                 AmbiguousBoolWordCode,
+	EmptyEnumMessage:                         EmptyEnumCode,
+	InvalidTemplateFunctionNameMessage:       InvalidTemplateFunctionNameCode,
+	TemplateFunctionAlreadyDefinedMessage:    TemplateFunctionAlreadyDefinedCode,
+	SyntaxNotSwitchableMessage:               SyntaxNotSwitchableCode,
+	ArgumentTypeMismatchMessage:              ArgumentTypeMismatchCode,
+	InvalidHandlerMessage:                    InvalidHandlerCode,
+	HandlerArgumentCountMessage:              HandlerArgumentCountCode,
+	UnknownArgumentFieldMessage:              UnknownArgumentFieldCode,
+	UnexportedArgumentFieldMessage:           UnexportedArgumentFieldCode,
+	HandlerParameterTypeMessage:              HandlerParameterTypeCode,
+	ArgumentOutOfRangeMessage:                ArgumentOutOfRangeCode,
+}
+
+// ErrorCodeOf returns the code of err, or of the first error it wraps that
+// has one, such as the error of a CompileError. It returns "" for errors
+// without a code, such as the ones of other packages.
+func ErrorCodeOf(err error) ErrorCode {
+	var c coded
+	if errors.As(err, &c) {
+		return c.Code()
+	}
+	return ""
+}
+
+// coded errors have an ErrorCode
+type coded interface {
+	error
+	Code() ErrorCode
+}
+
+func (e *CucumberExpressionError) Code() ErrorCode {
+	return e.code
+}
+
+func (e *MissingEndTokenError) Code() ErrorCode {
+	return MissingEndTokenCode
+}
+
+func (e *EscapedEndOfLineError) Code() ErrorCode {
+	return EscapedEndOfLineCode
+}
+
+func (e *InvalidParameterTypeNameError) Code() ErrorCode {
+	return InvalidParameterTypeNameCode
+}
+
+func (e *ParameterInOptionalError) Code() ErrorCode {
+	return ParameterInOptionalCode
+}
+
+func (e *ParameterInAlternativeError) Code() ErrorCode {
+	return ParameterInAlternativeCode
+}
+
+func (e *InvalidParameterNameError) Code() ErrorCode {
+	return InvalidParameterNameCode
+}
+
+func (e *DuplicateParameterNameError) Code() ErrorCode {
+	return DuplicateParameterNameCode
+}
+
+func (e *ParameterTypeAlreadyDefinedError) Code() ErrorCode {
+	if e.Name == "" {
+		return AnonymousParameterTypeAlreadyDefinedCode
+	}
+	return ParameterTypeAlreadyDefinedCode
+}
+
+func (e *AmbiguousParameterTypeError) Code() ErrorCode {
+	return AmbiguousParameterTypeCode
+}
+
+func (e *UndefinedParameterTypeError) Code() ErrorCode {
+	return UndefinedParameterTypeCode
+}
+
+func (e *SyntaxNotEnabledError) Code() ErrorCode {
+	return SyntaxNotEnabledCode
+}
+
+func (e *transformError) Code() ErrorCode {
+	return TransformFailedCode
+}
+
+func (e *TemplateError) Code() ErrorCode {
+	return TemplateFailedCode
+}
+
+func (e *NoRouteError) Code() ErrorCode {
+	return NoRouteCode
+}
+
+func (e *AmbiguousRouteError) Code() ErrorCode {
+	return AmbiguousRouteCode
+}
+
+func (e *PanicError) Code() ErrorCode {
+	return HandlerPanicCode
+}
+
+func (e *messageError) Code() ErrorCode {
+	return errorCodes[e.key]
+}
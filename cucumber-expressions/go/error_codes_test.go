@@ -0,0 +1,59 @@
+package cucumberexpressions
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCodes(t *testing.T) {
+	t.Run("has a code for every problem with an expression", func(t *testing.T) {
+		for expr, code := range map[string]ErrorCode{
+			"three (blind mice": MissingEndTokenCode,
+			"{a(b}":             InvalidParameterTypeNameCode,
+			"x/{int}":           ParameterInAlternativeCode,
+		} {
+			_, err := Parse(expr)
+			require.Error(t, err, expr)
+			require.Equal(t, code, ErrorCodeOf(err), expr)
+		}
+
+		registry := NewParameterTypeRegistry()
+		for expr, code := range map[string]ErrorCode{
+			"{unknown}": UndefinedParameterTypeCode,
+			"({int})":   ParameterInOptionalCode,
+		} {
+			_, err := NewCucumberExpression(expr, registry)
+			require.Error(t, err, expr)
+			require.Equal(t, code, ErrorCodeOf(err), expr)
+		}
+	})
+
+	t.Run("has a code for errors with a message of the catalog", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		parameterType, err := NewParameterType("int", []*regexp.Regexp{regexp.MustCompile(`\d+`)}, "int", nil, false, false, false)
+		require.NoError(t, err)
+		require.Equal(t, ParameterTypeAlreadyDefinedCode, ErrorCodeOf(registry.DefineParameterType(parameterType)))
+	})
+
+	t.Run("has the code of the errors it wraps", func(t *testing.T) {
+		_, err := CompileAll([]string{"{int}", "{unknown}"}, NewParameterTypeRegistry(), 1)
+		require.Error(t, err)
+		require.Equal(t, UndefinedParameterTypeCode, ErrorCodeOf(err))
+	})
+
+	t.Run("has no code for errors of other packages", func(t *testing.T) {
+		require.Equal(t, ErrorCode(""), ErrorCodeOf(errors.New("boom")))
+		require.Equal(t, ErrorCode(""), ErrorCodeOf(nil))
+	})
+
+	t.Run("uses every code once", func(t *testing.T) {
+		seen := map[ErrorCode]MessageKey{}
+		for key, code := range errorCodes {
+			require.NotContains(t, seen, code)
+			seen[code] = key
+		}
+	})
+}
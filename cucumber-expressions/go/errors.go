@@ -1,12 +1,12 @@
 package cucumberexpressions
 
 import (
-	"fmt"
 	"strings"
 )
 
 type CucumberExpressionError struct {
-	s string
+	s    string
+	code ErrorCode
 }
 
 func NewCucumberExpressionError(text string) error {
@@ -17,8 +17,190 @@ func (e *CucumberExpressionError) Error() string {
 	return e.s
 }
 
+// MissingEndTokenError is an optional of an expression that is not closed. Start and End are the offsets in runes of its beginning.
+type MissingEndTokenError struct {
+	Expression  string
+	Start       int
+	End         int
+	BeginSymbol string
+	EndSymbol   string
+}
+
+func createMissingEndToken(expression string, beginToken TokenType, endToken TokenType, current Token) error {
+	return &MissingEndTokenError{expression, current.Start, current.End, symbolOf(beginToken), symbolOf(endToken)}
+}
+
+func (e *MissingEndTokenError) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e *MissingEndTokenError) localize(messages Messages) string {
+	problem, hint := e.describe(messages)
+	return messages.problem(e.Expression, e.Start, e.End, problem, hint)
+}
+
+func (e *MissingEndTokenError) span() (int, int) {
+	return e.Start, e.End
+}
+
+func (e *MissingEndTokenError) describe(messages Messages) (string, string) {
+	return messages.format(MissingEndTokenMessage, e.BeginSymbol, e.EndSymbol), messages.format(EscapeOptionalHintMessage)
+}
+
+// InvalidParameterTypeNameError is a character of a parameter type name
+// that is not allowed. Start and End are the offsets in runes of the
+// character.
+type InvalidParameterTypeNameError struct {
+	Expression string
+	Start      int
+	End        int
+}
+
+func createInvalidParameterTypeName(expression string, current Token) error {
+	return &InvalidParameterTypeNameError{expression, current.Start, current.End}
+}
+
+func (e *InvalidParameterTypeNameError) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e *InvalidParameterTypeNameError) localize(messages Messages) string {
+	problem, hint := e.describe(messages)
+	return messages.problem(e.Expression, e.Start, e.End, problem, hint)
+}
+
+func (e *InvalidParameterTypeNameError) span() (int, int) {
+	return e.Start, e.End
+}
+
+func (e *InvalidParameterTypeNameError) describe(messages Messages) (string, string) {
+	return messages.format(InvalidParameterTypeNameMessage), messages.format(InvalidParameterTypeNameHintMessage)
+}
+
+// EscapedEndOfLineError is an expression that ends with a double escape
+// character, which has nothing to escape. Start and End are the offsets in
+// runes of the escape characters.
+type EscapedEndOfLineError struct {
+	Expression string
+	Start      int
+	End        int
+}
+
+func (e *EscapedEndOfLineError) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e *EscapedEndOfLineError) localize(messages Messages) string {
+	problem, hint := e.describe(messages)
+	return messages.problem(e.Expression, e.Start, e.End, problem, hint)
+}
+
+func (e *EscapedEndOfLineError) span() (int, int) {
+	return e.Start, e.End
+}
+
+func (e *EscapedEndOfLineError) describe(messages Messages) (string, string) {
+	return messages.format(EscapedEndOfLineMessage), messages.format(EscapedEndOfLineHintMessage)
+}
+
+func createCouldNotParse(expression string, current Token) error {
+	return &CucumberExpressionError{
+		s: englishMessages.problem(
+			expression,
+			current.Start,
+			current.End,
+			"Could not parse the expression from here",
+			"This is a bug in the parser, please report it",
+		),
+		code: CouldNotParseCode,
+	}
+}
+
+// ParameterInOptionalError is an expression with a parameter in an
+// optional. Start and End are the offsets in runes of the parameter.
+type ParameterInOptionalError struct {
+	Expression string
+	Start      int
+	End        int
+}
+
+func createParameterInOptional(expression string, parameter Node) error {
+	return &ParameterInOptionalError{expression, parameter.Start, parameter.End}
+}
+
+func (e *ParameterInOptionalError) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e *ParameterInOptionalError) localize(messages Messages) string {
+	problem, _ := e.describe(messages)
+	return problem
+}
+
+func (e *ParameterInOptionalError) span() (int, int) {
+	return e.Start, e.End
+}
+
+func (e *ParameterInOptionalError) describe(messages Messages) (string, string) {
+	return messages.format(ParameterInOptionalMessage, e.Expression), ""
+}
+
+// ParameterInAlternativeError is an expression with a parameter in an
+// alternative. Start and End are the offsets in runes of the parameter.
+type ParameterInAlternativeError struct {
+	Expression string
+	Start      int
+	End        int
+}
+
+func createParameterInAlternative(expression string, parameter Node) error {
+	return &ParameterInAlternativeError{expression, parameter.Start, parameter.End}
+}
+
+func (e *ParameterInAlternativeError) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e *ParameterInAlternativeError) localize(messages Messages) string {
+	problem, _ := e.describe(messages)
+	return problem
+}
+
+func (e *ParameterInAlternativeError) span() (int, int) {
+	return e.Start, e.End
+}
+
+func (e *ParameterInAlternativeError) describe(messages Messages) (string, string) {
+	return messages.format(ParameterInAlternativeMessage, e.Expression), ""
+}
+
+// expressionProblem is an error at a span of an expression
+type expressionProblem interface {
+	localizable
+	span() (start int, end int)
+	describe(messages Messages) (problem string, hint string)
+}
+
+// pointAt returns a caret under the rune at index
+func pointAt(index int) string {
+	return strings.Repeat(" ", index) + "^"
+}
+
+// pointAtSpan returns carets under the first and the last rune from start
+// to end, joined by dashes
+func pointAtSpan(start int, end int) string {
+	pointer := pointAt(start)
+	if start+1 < end {
+		pointer += strings.Repeat("-", end-start-2) + "^"
+	}
+	return pointer
+}
+
 type AmbiguousParameterTypeError struct {
-	s string
+	parameterTypeRegexp        string
+	expressionRegexp           string
+	parameterTypeNames         []string
+	generatedExpressionSources []string
 }
 
 func NewAmbiguousParameterTypeError(parameterTypeRegexp, expressionRegexp string, parameterTypes []*ParameterType, generatedExpressions []*GeneratedExpression) error {
@@ -30,40 +212,138 @@ func NewAmbiguousParameterTypeError(parameterTypeRegexp, expressionRegexp string
 	for i, generatedExpression := range generatedExpressions {
 		generatedExpressionSources[i] = generatedExpression.Source()
 	}
-	return &AmbiguousParameterTypeError{
-		s: fmt.Sprintf(
-			`Your Regular Expression /%s/
-matches multiple parameter types with regexp /%s/:
-   %s
+	return &AmbiguousParameterTypeError{parameterTypeRegexp, expressionRegexp, parameterTypeNames, generatedExpressionSources}
+}
+
+func (e *AmbiguousParameterTypeError) Error() string {
+	return e.localize(englishMessages)
+}
 
-I couldn't decide which one to use. You have two options:
+func (e *AmbiguousParameterTypeError) localize(messages Messages) string {
+	return messages.format(
+		AmbiguousParameterTypeMessage,
+		e.expressionRegexp,
+		e.parameterTypeRegexp,
+		strings.Join(e.parameterTypeNames, "\n   "),
+		strings.Join(e.generatedExpressionSources, "\n   "),
+	)
+}
 
-1) Use a Cucumber Expression instead of a Regular Expression. Try one of these:
-   %s
+// UndefinedParameterTypeError is a parameter of an expression whose type
+// is not defined. Start and End are the offsets in runes of the parameter
+// in Expression, which is empty when the error is not of an expression.
+type UndefinedParameterTypeError struct {
+	// TypeName is the name of the parameter type
+	TypeName string
+	// Suggestions are the names of the defined parameter types closest to
+	// TypeName, if any are close
+	Suggestions []string
+	Expression  string
+	Start       int
+	End         int
+}
+
+func NewUndefinedParameterTypeError(typeName string) error {
+	return &UndefinedParameterTypeError{TypeName: typeName}
+}
 
-2) Make one of the parameter types preferential and continue to use a Regular Expression.
+func (e *UndefinedParameterTypeError) Error() string {
+	return e.localize(englishMessages)
+}
 
-`,
-			expressionRegexp,
-			parameterTypeRegexp,
-			strings.Join(parameterTypeNames, "\n   "),
-			strings.Join(generatedExpressionSources, "\n   "),
-		),
+func (e *UndefinedParameterTypeError) localize(messages Messages) string {
+	problem, hint := e.describe(messages)
+	if hint == "" {
+		return problem
 	}
+	return problem + ". " + hint
 }
 
-func (e *AmbiguousParameterTypeError) Error() string {
-	return e.s
+func (e *UndefinedParameterTypeError) span() (int, int) {
+	return e.Start, e.End
 }
 
-type UndefinedParameterTypeError struct {
-	s string
+func (e *UndefinedParameterTypeError) describe(messages Messages) (string, string) {
+	problem := messages.format(UndefinedParameterTypeMessage, e.TypeName)
+	if len(e.Suggestions) == 0 {
+		return problem, ""
+	}
+	suggestions := make([]string, len(e.Suggestions))
+	for i, suggestion := range e.Suggestions {
+		suggestions[i] = "{" + suggestion + "}"
+	}
+	return problem, messages.format(DidYouMeanMessage, strings.Join(suggestions, ", "))
 }
 
-func NewUndefinedParameterTypeError(typeName string) error {
-	return &UndefinedParameterTypeError{s: fmt.Sprintf("Undefined parameter type {%s}", typeName)}
+// InvalidParameterNameError is a parameter whose name, such as count in
+// {count:int}, can't name its argument. Start and End are the offsets in
+// runes of the parameter.
+type InvalidParameterNameError struct {
+	Name       string
+	Expression string
+	Start      int
+	End        int
 }
 
-func (e *UndefinedParameterTypeError) Error() string {
-	return e.s
+func (e *InvalidParameterNameError) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e *InvalidParameterNameError) localize(messages Messages) string {
+	problem, _ := e.describe(messages)
+	return problem
+}
+
+func (e *InvalidParameterNameError) span() (int, int) {
+	return e.Start, e.End
+}
+
+func (e *InvalidParameterNameError) describe(messages Messages) (string, string) {
+	return messages.format(InvalidParameterNameMessage, e.Name, e.Expression), ""
+}
+
+// DuplicateParameterNameError is a parameter with the name of an earlier
+// parameter of the expression. Start and End are the offsets in runes of
+// the later one.
+type DuplicateParameterNameError struct {
+	Name       string
+	Expression string
+	Start      int
+	End        int
+}
+
+func (e *DuplicateParameterNameError) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e *DuplicateParameterNameError) localize(messages Messages) string {
+	problem, _ := e.describe(messages)
+	return problem
+}
+
+func (e *DuplicateParameterNameError) span() (int, int) {
+	return e.Start, e.End
+}
+
+func (e *DuplicateParameterNameError) describe(messages Messages) (string, string) {
+	return messages.format(DuplicateParameterNameMessage, e.Name, e.Expression), ""
+}
+
+// ParameterTypeAlreadyDefinedError is a parameter type with the name of
+// one that is already defined in the registry. Name is empty for the
+// anonymous parameter type. Parameter types are defined outside of
+// expressions, so it has no position.
+type ParameterTypeAlreadyDefinedError struct {
+	Name string
+}
+
+func (e *ParameterTypeAlreadyDefinedError) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e *ParameterTypeAlreadyDefinedError) localize(messages Messages) string {
+	if e.Name == "" {
+		return messages.format(AnonymousParameterTypeAlreadyDefinedMessage)
+	}
+	return messages.format(ParameterTypeAlreadyDefinedMessage, e.Name)
 }
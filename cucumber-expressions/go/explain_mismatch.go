@@ -0,0 +1,150 @@
+package cucumberexpressions
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// Mismatch explains why an expression doesn't match a text
+type Mismatch struct {
+	// Position is the byte offset in the text where matching fails
+	Position int
+	// Matched is the longest beginning of the text that the beginning of
+	// the expression matches
+	Matched string
+	// Remaining is the rest of the text
+	Remaining string
+	// Expected is the part of the expression that doesn't match at
+	// Position: text, a regexp, or empty when the text should have ended
+	Expected string
+	// ParameterRegexp is set when the regexp of a parameter rejected the
+	// text at Position
+	ParameterRegexp string
+	// ParameterType is the type of that parameter, if known
+	ParameterType *ParameterType
+}
+
+func (m *Mismatch) String() string {
+	switch {
+	case m.ParameterType != nil:
+		return fmt.Sprintf("{%s} does not match %q at position %d", m.ParameterType.Name(), m.Remaining, m.Position)
+	case m.ParameterRegexp != "":
+		return fmt.Sprintf("(%s) does not match %q at position %d", m.ParameterRegexp, m.Remaining, m.Position)
+	case m.Expected == "":
+		return fmt.Sprintf("expected the end of the text at position %d, got %q", m.Position, m.Remaining)
+	}
+	return fmt.Sprintf("expected %q at position %d, got %q", m.Expected, m.Position, m.Remaining)
+}
+
+// ExplainMismatch returns why an expression doesn't match a text, or nil
+// when it does. Regular expressions are explained as if they were anchored
+// at the beginning of the text.
+func ExplainMismatch(expression Expression, text string) (*Mismatch, error) {
+	if expression.Regexp().MatchString(text) {
+		return nil, nil
+	}
+	re, err := syntax.Parse(expression.Regexp().String(), syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	nodes := mismatchNodes(re)
+
+	// Find the longest sequence of nodes matching the beginning of the text
+	position := 0
+	k := 0
+	for ; k < len(nodes); k++ {
+		prefix, err := regexp.Compile(`^(?:` + concatenation(nodes[:k+1]) + `)`)
+		if err != nil {
+			return nil, err
+		}
+		prefix.Longest()
+		loc := prefix.FindStringIndex(text)
+		if loc == nil {
+			break
+		}
+		position = loc[1]
+	}
+
+	mismatch := &Mismatch{Position: position, Matched: text[:position], Remaining: text[position:]}
+	if k == len(nodes) {
+		// The text goes on after the end of the expression
+		return mismatch, nil
+	}
+
+	switch nodes[k].Op {
+	case syntax.OpLiteral:
+		var expected strings.Builder
+		for i := k; i < len(nodes) && nodes[i].Op == syntax.OpLiteral; i++ {
+			expected.WriteString(string(nodes[i].Rune))
+		}
+		mismatch.Expected = expected.String()
+	case syntax.OpCapture:
+		parameterIndex := 0
+		for _, node := range nodes[:k] {
+			parameterIndex += countParameters(node)
+		}
+		groupBuilder := NewTreeRegexp(expression.Regexp()).GroupBuilder().Children()[parameterIndex]
+		mismatch.Expected = groupBuilder.Source()
+		mismatch.ParameterRegexp = groupBuilder.Source()
+		mismatch.ParameterType = mismatchParameterType(expression, parameterIndex, groupBuilder.Source(), text)
+	default:
+		mismatch.Expected = nodes[k].String()
+	}
+	return mismatch, nil
+}
+
+// mismatchNodes returns the parts of a regexp to match one by one, with
+// literal text split into single characters and without anchors
+func mismatchNodes(re *syntax.Regexp) []*syntax.Regexp {
+	parts := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		parts = re.Sub
+	}
+	var nodes []*syntax.Regexp
+	for _, part := range parts {
+		switch part.Op {
+		case syntax.OpBeginText, syntax.OpEndText, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpEmptyMatch:
+		case syntax.OpLiteral:
+			for _, r := range part.Rune {
+				nodes = append(nodes, &syntax.Regexp{Op: syntax.OpLiteral, Flags: part.Flags, Rune: []rune{r}})
+			}
+		default:
+			nodes = append(nodes, part)
+		}
+	}
+	return nodes
+}
+
+func concatenation(nodes []*syntax.Regexp) string {
+	var result strings.Builder
+	for _, node := range nodes {
+		result.WriteString(`(?:` + node.String() + `)`)
+	}
+	return result.String()
+}
+
+// countParameters counts the capture groups that aren't nested in other
+// capture groups, which are the parameters of an expression
+func countParameters(node *syntax.Regexp) int {
+	if node.Op == syntax.OpCapture {
+		return 1
+	}
+	count := 0
+	for _, sub := range node.Sub {
+		count += countParameters(sub)
+	}
+	return count
+}
+
+func mismatchParameterType(expression Expression, parameterIndex int, parameterRegexp string, text string) *ParameterType {
+	switch e := expression.(type) {
+	case *CucumberExpression:
+		return e.parameterTypes[parameterIndex]
+	case *RegularExpression:
+		parameterType, _ := e.parameterTypeRegistry.LookupByRegexp(parameterRegexp, e.expressionRegexp.String(), text)
+		return parameterType
+	}
+	return nil
+}
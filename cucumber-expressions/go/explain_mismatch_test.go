@@ -0,0 +1,64 @@
+package cucumberexpressions
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainMismatch(t *testing.T) {
+	explain := func(expr string, text string) *Mismatch {
+		expression, err := NewCucumberExpression(expr, NewParameterTypeRegistry())
+		require.NoError(t, err)
+		mismatch, err := ExplainMismatch(expression, text)
+		require.NoError(t, err)
+		return mismatch
+	}
+
+	t.Run("returns nil when the expression matches", func(t *testing.T) {
+		require.Nil(t, explain("I have {int} cuke(s)", "I have 42 cukes"))
+	})
+
+	t.Run("explains diverging text", func(t *testing.T) {
+		mismatch := explain("I have {int} cuke(s)", "I have 42 cucumbers")
+		require.Equal(t, "I have 42 cu", mismatch.Matched)
+		require.Equal(t, "cumbers", mismatch.Remaining)
+		require.Equal(t, "ke", mismatch.Expected)
+		require.Nil(t, mismatch.ParameterType)
+		require.Equal(t, `expected "ke" at position 12, got "cumbers"`, mismatch.String())
+	})
+
+	t.Run("explains parameters rejecting the text", func(t *testing.T) {
+		mismatch := explain("I have {int} cuke(s) in my {word}", "I have many cukes in my belly")
+		require.Equal(t, "I have ", mismatch.Matched)
+		require.Equal(t, "int", mismatch.ParameterType.Name())
+		require.Equal(t, `(?:-?\d+)|(?:\d+)`, mismatch.ParameterRegexp)
+		require.Equal(t, `{int} does not match "many cukes in my belly" at position 7`, mismatch.String())
+	})
+
+	t.Run("explains alternatives", func(t *testing.T) {
+		mismatch := explain("I have a red/green cuke", "I have a blue cuke")
+		require.Equal(t, "I have a ", mismatch.Matched)
+		require.Equal(t, `expected "red|green" at position 9, got "blue cuke"`, mismatch.String())
+	})
+
+	t.Run("explains text going on after the expression", func(t *testing.T) {
+		mismatch := explain("I have {int} cukes", "I have 42 cukes today")
+		require.Equal(t, `expected the end of the text at position 15, got " today"`, mismatch.String())
+	})
+
+	t.Run("finds the parameter type of regular expression groups", func(t *testing.T) {
+		expression := NewRegularExpression(regexp.MustCompile(`^I have (\d+) (?:of )?("[^"]*") in (\w+)$`), NewParameterTypeRegistry())
+		mismatch, err := ExplainMismatch(expression, `I have three "cukes" in bags`)
+		require.NoError(t, err)
+		require.Equal(t, "int", mismatch.ParameterType.Name())
+		require.Equal(t, `\d+`, mismatch.ParameterRegexp)
+
+		mismatch, err = ExplainMismatch(expression, `I have 3 of "cukes" in -bags`)
+		require.NoError(t, err)
+		require.Nil(t, mismatch.ParameterType)
+		require.Equal(t, `\w+`, mismatch.ParameterRegexp)
+		require.Equal(t, `(\w+) does not match "-bags" at position 23`, mismatch.String())
+	})
+}
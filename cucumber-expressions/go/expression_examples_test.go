@@ -20,6 +20,10 @@ func TestExamples(t *testing.T) {
 		lines := strings.Split(strings.TrimSpace(chunk), "\n")
 		expressionText, text, expectedArgs := lines[0], lines[1], lines[2]
 		t.Run(fmt.Sprintf("works with %s", expressionText), func(t *testing.T) {
+			if !strings.HasPrefix(expressionText, "/") {
+				_, err := Parse(expressionText)
+				require.NoError(t, err)
+			}
 			args := MatchExample(t, expressionText, text)
 			argsJson, err := json.Marshal(args)
 			require.NoError(t, err)
@@ -0,0 +1,154 @@
+package cucumberexpressions
+
+import (
+	"iter"
+	"reflect"
+	"sync"
+)
+
+// ExpressionSet matches text against a list of expressions that can be
+// reloaded while it is in use, for long-lived services such as chat bots
+// that map commands to handlers. Reload compiles the new expressions before
+// swapping them in atomically, so a set never has half of them. Matches that
+// started before a reload finish with the expressions they started with.
+//
+// A registry must not be changed while a set uses it: reload the set with a
+// new registry instead.
+type ExpressionSet struct {
+	mutex   sync.RWMutex
+	current *expressionGeneration
+}
+
+// expressionGeneration is the expressions of a set between two reloads,
+// with the matches that use them
+type expressionGeneration struct {
+	parameterTypeRegistry *ParameterTypeRegistry
+	expressions           []Expression
+	index                 *literalIndex
+	inFlight              sync.WaitGroup
+}
+
+func NewExpressionSet(expressions []string, parameterTypeRegistry *ParameterTypeRegistry) (*ExpressionSet, error) {
+	generation, err := newExpressionGeneration(expressions, parameterTypeRegistry)
+	if err != nil {
+		return nil, err
+	}
+	return &ExpressionSet{current: generation}, nil
+}
+
+func newExpressionGeneration(expressions []string, parameterTypeRegistry *ParameterTypeRegistry) (*expressionGeneration, error) {
+	compiled, err := CompileAll(expressions, parameterTypeRegistry, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &expressionGeneration{
+		parameterTypeRegistry: parameterTypeRegistry,
+		expressions:           compiled,
+		index:                 newLiteralIndex(compiled),
+	}, nil
+}
+
+// Reload replaces the expressions and registry of the set. When one of the
+// expressions doesn't compile the set keeps the ones it has, and the error
+// is CompileErrors. Otherwise Reload returns once the matches that used the
+// replaced expressions have finished.
+func (s *ExpressionSet) Reload(expressions []string, parameterTypeRegistry *ParameterTypeRegistry) error {
+	generation, err := newExpressionGeneration(expressions, parameterTypeRegistry)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	replaced := s.current
+	s.current = generation
+	s.mutex.Unlock()
+
+	replaced.inFlight.Wait()
+	return nil
+}
+
+// Match matches text against the expressions of the set in order, and
+// returns the first one that matches with its arguments. The expression is
+// nil when none matches.
+//
+// Expressions whose regexp requires a literal that is not in the text are
+// skipped without matching them, so they don't report errors for the text,
+// such as an ambiguous parameter type.
+func (s *ExpressionSet) Match(text string, typeHints ...reflect.Type) (Expression, []*Argument, error) {
+	generation := s.acquire()
+	defer generation.inFlight.Done()
+
+	candidates := generation.index.candidates(text)
+	for i, expression := range generation.expressions {
+		if !candidates[i] {
+			continue
+		}
+		args, err := expression.Match(text, typeHints...)
+		if err != nil {
+			return nil, nil, err
+		}
+		if args != nil {
+			return expression, args, nil
+		}
+	}
+	return nil, nil, nil
+}
+
+// ExpressionMatch is an expression that matched text, with its arguments
+type ExpressionMatch struct {
+	Expression Expression
+	Arguments  []*Argument
+}
+
+// Matches yields the expressions of the set that match text, in order, so
+// the caller can stop at the first one it accepts. It stops after yielding
+// an error. Reload waits until the caller stops ranging over the matches,
+// so it must not be called in the loop. Expressions are skipped like in
+// Match.
+func (s *ExpressionSet) Matches(text string, typeHints ...reflect.Type) iter.Seq2[ExpressionMatch, error] {
+	return func(yield func(ExpressionMatch, error) bool) {
+		generation := s.acquire()
+		defer generation.inFlight.Done()
+
+		candidates := generation.index.candidates(text)
+		for i, expression := range generation.expressions {
+			if !candidates[i] {
+				continue
+			}
+			args, err := expression.Match(text, typeHints...)
+			if err != nil {
+				yield(ExpressionMatch{}, err)
+				return
+			}
+			if args != nil && !yield(ExpressionMatch{Expression: expression, Arguments: args}, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Expressions returns the expressions of the set, in order.
+func (s *ExpressionSet) Expressions() []Expression {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	result := make([]Expression, len(s.current.expressions))
+	copy(result, s.current.expressions)
+	return result
+}
+
+// ParameterTypeRegistry returns the registry the expressions of the set
+// were compiled with.
+func (s *ExpressionSet) ParameterTypeRegistry() *ParameterTypeRegistry {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.current.parameterTypeRegistry
+}
+
+// acquire returns the current generation, counting a match in flight until
+// the caller is done with it
+func (s *ExpressionSet) acquire() *expressionGeneration {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	s.current.inFlight.Add(1)
+	return s.current
+}
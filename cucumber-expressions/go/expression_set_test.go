@@ -0,0 +1,148 @@
+package cucumberexpressions
+
+import (
+	"reflect"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressionSet(t *testing.T) {
+	t.Run("matches the first expression that matches", func(t *testing.T) {
+		set, err := NewExpressionSet([]string{"deploy {word}", "deploy {word} to {word}", "{}"}, NewParameterTypeRegistry())
+		require.NoError(t, err)
+
+		expression, args, err := set.Match("deploy api to production")
+		require.NoError(t, err)
+		require.Equal(t, "deploy {word} to {word}", expression.Source())
+		require.Equal(t, "production", args[1].GetValue())
+
+		expression, _, err = set.Match("hello")
+		require.NoError(t, err)
+		require.Equal(t, "{}", expression.Source())
+	})
+
+	t.Run("matches nothing", func(t *testing.T) {
+		set, err := NewExpressionSet([]string{"deploy {word}"}, NewParameterTypeRegistry())
+		require.NoError(t, err)
+
+		expression, args, err := set.Match("hello")
+		require.NoError(t, err)
+		require.Nil(t, expression)
+		require.Nil(t, args)
+	})
+
+	t.Run("yields the expressions that match", func(t *testing.T) {
+		set, err := NewExpressionSet([]string{"deploy {word}", "deploy {word} to {word}", "{}"}, NewParameterTypeRegistry())
+		require.NoError(t, err)
+
+		var sources []string
+		for match, err := range set.Matches("deploy api to production") {
+			require.NoError(t, err)
+			sources = append(sources, match.Expression.Source())
+		}
+		require.Equal(t, []string{"deploy {word} to {word}", "{}"}, sources)
+
+		for match := range set.Matches("deploy api to production") {
+			require.Equal(t, "api", match.Arguments[0].GetValue())
+			break
+		}
+		require.NoError(t, set.Reload([]string{"{}"}, NewParameterTypeRegistry()))
+	})
+
+	t.Run("reloads expressions and registry", func(t *testing.T) {
+		set, err := NewExpressionSet([]string{"deploy {word}"}, NewParameterTypeRegistry())
+		require.NoError(t, err)
+
+		registry := NewParameterTypeRegistry()
+		environment, err := NewParameterType("environment", []*regexp.Regexp{regexp.MustCompile("staging|production")}, "environment", nil, false, false, false)
+		require.NoError(t, err)
+		require.NoError(t, registry.DefineParameterType(environment))
+		require.NoError(t, set.Reload([]string{"deploy to {environment}"}, registry))
+
+		expression, _, err := set.Match("deploy to staging")
+		require.NoError(t, err)
+		require.Equal(t, "deploy to {environment}", expression.Source())
+		require.Len(t, set.Expressions(), 1)
+		require.Equal(t, registry, set.ParameterTypeRegistry())
+	})
+
+	t.Run("keeps its expressions when a reload does not compile", func(t *testing.T) {
+		set, err := NewExpressionSet([]string{"deploy {word}"}, NewParameterTypeRegistry())
+		require.NoError(t, err)
+
+		err = set.Reload([]string{"deploy {int}", "deploy to {environment}"}, NewParameterTypeRegistry())
+		compileErrors, ok := err.(CompileErrors)
+		require.True(t, ok)
+		require.Equal(t, 1, compileErrors[0].Index)
+
+		expression, _, err := set.Match("deploy api")
+		require.NoError(t, err)
+		require.Equal(t, "deploy {word}", expression.Source())
+	})
+
+	t.Run("waits for matches with the replaced expressions", func(t *testing.T) {
+		blocking := &blockingExpression{started: make(chan bool), release: make(chan bool)}
+		expressions := []Expression{blocking}
+		set := &ExpressionSet{current: &expressionGeneration{expressions: expressions, index: newLiteralIndex(expressions)}}
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			expression, _, err := set.Match("anything")
+			require.NoError(t, err)
+			require.Equal(t, blocking, expression)
+		}()
+		<-blocking.started
+
+		reloaded := make(chan bool)
+		go func() {
+			require.NoError(t, set.Reload([]string{"anything"}, NewParameterTypeRegistry()))
+			close(reloaded)
+		}()
+
+		// Matching before the swap would block on the old expression, so
+		// the swap is polled without matching
+		require.Eventually(t, func() bool {
+			return set.Expressions()[0] != blocking
+		}, time.Second, time.Millisecond)
+		// New matches use the new expressions while the old match is in flight
+		expression, _, err := set.Match("anything")
+		require.NoError(t, err)
+		require.Equal(t, "anything", expression.Source())
+		select {
+		case <-reloaded:
+			t.Fatal("reload returned before the match finished")
+		default:
+		}
+
+		close(blocking.release)
+		<-reloaded
+		wg.Wait()
+	})
+}
+
+// blockingExpression matches everything once it is released
+type blockingExpression struct {
+	once    sync.Once
+	started chan bool
+	release chan bool
+}
+
+func (b *blockingExpression) Match(text string, typeHints ...reflect.Type) ([]*Argument, error) {
+	b.once.Do(func() { close(b.started) })
+	<-b.release
+	return []*Argument{}, nil
+}
+
+func (b *blockingExpression) Regexp() *regexp.Regexp {
+	return regexp.MustCompile(".*")
+}
+
+func (b *blockingExpression) Source() string {
+	return "blocking"
+}
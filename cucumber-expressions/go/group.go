@@ -5,6 +5,7 @@ type Group struct {
 	start    int
 	end      int
 	children []*Group
+	name     string
 }
 
 func NewGroup(value *string, start, end int, children []*Group) *Group {
@@ -28,6 +29,11 @@ func (g *Group) End() int {
 	return g.end
 }
 
+// Name returns the name of the capture group, if it was a named group.
+func (g *Group) Name() string {
+	return g.name
+}
+
 func (g *Group) Children() []*Group {
 	return g.children
 }
@@ -4,6 +4,7 @@ type GroupBuilder struct {
 	groupBuilders []*GroupBuilder
 	capturing     bool
 	source        string
+	name          string
 }
 
 func NewGroupBuilder() *GroupBuilder {
@@ -22,7 +23,9 @@ func (g *GroupBuilder) Build(submatches []*Submatch, indexIterator *IntIterator)
 	for i, child := range g.groupBuilders {
 		children[i] = child.Build(submatches, indexIterator)
 	}
-	return NewGroup(submatch.value, submatch.start, submatch.end, children)
+	group := NewGroup(submatch.value, submatch.start, submatch.end, children)
+	group.name = g.name
+	return group
 }
 
 func (g *GroupBuilder) SetNonCapturing() {
@@ -50,3 +53,12 @@ func (g *GroupBuilder) SetSource(value string) {
 func (g *GroupBuilder) Source() string {
 	return g.source
 }
+
+func (g *GroupBuilder) SetName(name string) {
+	g.name = name
+}
+
+// Name returns the name of a named capture group, or an empty string.
+func (g *GroupBuilder) Name() string {
+	return g.name
+}
@@ -0,0 +1,46 @@
+package cucumberexpressions
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// newJSONParameterType returns {json}, which matches JSON objects and
+// arrays, such as {"name": "cuke"} in `I post {"name": "cuke"}`, and
+// transforms them to a map[string]interface{} or an []interface{}
+func newJSONParameterType() *ParameterType {
+	parameterType, err := NewParameterType(
+		"json",
+		JSON_REGEXPS,
+		"interface{}",
+		func(args ...*string) interface{} {
+			var value interface{}
+			if err := json.Unmarshal([]byte(*args[0]), &value); err != nil {
+				panic(&transformError{"json", err})
+			}
+			return value
+		},
+		false,
+		false,
+		false,
+	)
+	if err != nil {
+		panic(err)
+	}
+	parameterType.decodesJSON = true
+	return parameterType
+}
+
+// jsonValue decodes the JSON text of an argument to valueType, such as a
+// struct
+func jsonValue(argument *Argument, valueType reflect.Type) (reflect.Value, error) {
+	values := argument.values()
+	if values == nil || values[0] == nil {
+		return reflect.Zero(valueType), nil
+	}
+	value := reflect.New(valueType)
+	if err := json.Unmarshal([]byte(*values[0]), value.Interface()); err != nil {
+		return reflect.Value{}, &transformError{argument.ParameterType().Name(), err}
+	}
+	return value.Elem(), nil
+}
@@ -0,0 +1,62 @@
+package cucumberexpressions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONParameterType(t *testing.T) {
+	t.Run("transforms JSON objects and arrays", func(t *testing.T) {
+		expression, err := NewCucumberExpression("I post {json} to {word}", NewParameterTypeRegistry())
+		require.NoError(t, err)
+
+		args, err := expression.Match(`I post {"name": "cuke", "tags": ["a"]} to users`)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"name": "cuke", "tags": []interface{}{"a"}}, args[0].GetValue())
+		require.Equal(t, "users", args[1].GetValue())
+
+		args, err = expression.Match(`I post [1, 2] to users`)
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{1.0, 2.0}, args[0].GetValue())
+
+		args, err = expression.Match(`I post "cuke" to users`)
+		require.NoError(t, err)
+		require.Nil(t, args)
+	})
+
+	t.Run("reports invalid JSON", func(t *testing.T) {
+		expression, err := NewCucumberExpression("I post {json}", NewParameterTypeRegistry())
+		require.NoError(t, err)
+		args, err := expression.Match(`I post {"name": cuke}`)
+		require.NoError(t, err)
+		_, err = transformedValue(args[0])
+		require.EqualError(t, err, "Could not transform {json}: invalid character 'c' looking for beginning of value")
+		require.Equal(t, TransformFailedCode, ErrorCodeOf(err))
+	})
+
+	t.Run("decodes JSON to the types of handler parameters", func(t *testing.T) {
+		type user struct {
+			Name string   `json:"name"`
+			Tags []string `json:"tags"`
+		}
+		router := NewRouter(NewParameterTypeRegistry(), FirstRoute)
+		var posted user
+		var raw interface{}
+		require.NoError(t, router.Add("I post {json}", func(u user) {
+			posted = u
+		}))
+		require.NoError(t, router.Add("I put {json}", func(value interface{}) {
+			raw = value
+		}))
+
+		require.NoError(t, router.Dispatch(context.Background(), `I post {"name": "cuke", "tags": ["a"]}`))
+		require.Equal(t, user{Name: "cuke", Tags: []string{"a"}}, posted)
+		require.NoError(t, router.Dispatch(context.Background(), `I put {"name": "cuke"}`))
+		require.Equal(t, map[string]interface{}{"name": "cuke"}, raw)
+
+		err := router.Dispatch(context.Background(), `I post {"name": 1}`)
+		require.EqualError(t, err, "Could not transform {json}: json: cannot unmarshal number into Go struct field user.name of type string")
+	})
+}
@@ -0,0 +1,150 @@
+package cucumberexpressions
+
+import (
+	"regexp"
+	"regexp/syntax"
+)
+
+// literalIndex finds the expressions that may match a text by the literal
+// each of them requires, so the regexps of the others don't need to run.
+// It is an Aho-Corasick automaton of the literals, which finds all of them
+// in a single pass over the text.
+type literalIndex struct {
+	// required tells which expressions have a literal. The ones without
+	// are candidates for any text.
+	required []bool
+	nodes    []literalIndexNode
+}
+
+type literalIndexNode struct {
+	next map[byte]int
+	// fail is the node of the longest suffix of this node's text that is
+	// also in the automaton
+	fail int
+	// expressions are the indexes of the expressions whose literal ends at
+	// this node, directly or through fail
+	expressions []int
+}
+
+func newLiteralIndex(expressions []Expression) *literalIndex {
+	index := &literalIndex{
+		required: make([]bool, len(expressions)),
+		nodes:    []literalIndexNode{{next: map[byte]int{}}},
+	}
+	for i, expression := range expressions {
+		literal := requiredLiteral(expression.Regexp())
+		if literal == "" {
+			continue
+		}
+		index.required[i] = true
+		node := 0
+		for j := 0; j < len(literal); j++ {
+			next, ok := index.nodes[node].next[literal[j]]
+			if !ok {
+				next = len(index.nodes)
+				index.nodes = append(index.nodes, literalIndexNode{next: map[byte]int{}})
+				index.nodes[node].next[literal[j]] = next
+			}
+			node = next
+		}
+		index.nodes[node].expressions = append(index.nodes[node].expressions, i)
+	}
+	index.link()
+	return index
+}
+
+// link sets the fail node of every node, breadth first so the fail nodes
+// of shorter texts are set before they are followed
+func (index *literalIndex) link() {
+	queue := make([]int, 0, len(index.nodes))
+	for _, child := range index.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for b, child := range index.nodes[node].next {
+			fail := index.nodes[node].fail
+			for fail != 0 && !index.hasNext(fail, b) {
+				fail = index.nodes[fail].fail
+			}
+			if next, ok := index.nodes[fail].next[b]; ok {
+				fail = next
+			}
+			index.nodes[child].fail = fail
+			index.nodes[child].expressions = append(index.nodes[child].expressions, index.nodes[fail].expressions...)
+			queue = append(queue, child)
+		}
+	}
+}
+
+func (index *literalIndex) hasNext(node int, b byte) bool {
+	_, ok := index.nodes[node].next[b]
+	return ok
+}
+
+// candidates tells for each expression if it may match text
+func (index *literalIndex) candidates(text string) []bool {
+	result := make([]bool, len(index.required))
+	for i, required := range index.required {
+		result[i] = !required
+	}
+	node := 0
+	for i := 0; i < len(text); i++ {
+		for node != 0 && !index.hasNext(node, text[i]) {
+			node = index.nodes[node].fail
+		}
+		if next, ok := index.nodes[node].next[text[i]]; ok {
+			node = next
+		}
+		for _, expression := range index.nodes[node].expressions {
+			result[expression] = true
+		}
+	}
+	return result
+}
+
+// requiredLiteral returns the longest literal that every text a regexp
+// matches contains, or "" when there is none, such as when the text is
+// in an optional, an alternation or matched case-insensitively
+func requiredLiteral(re *regexp.Regexp) string {
+	if re == nil {
+		return ""
+	}
+	parsed, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return ""
+	}
+	longest := ""
+	for _, literal := range requiredLiterals(parsed) {
+		if len(literal) > len(longest) {
+			longest = literal
+		}
+	}
+	return longest
+}
+
+func requiredLiterals(re *syntax.Regexp) []string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			return nil
+		}
+		return []string{string(re.Rune)}
+	case syntax.OpCapture, syntax.OpPlus:
+		return requiredLiterals(re.Sub[0])
+	case syntax.OpRepeat:
+		if re.Min == 0 {
+			return nil
+		}
+		return requiredLiterals(re.Sub[0])
+	case syntax.OpConcat:
+		var literals []string
+		for _, sub := range re.Sub {
+			literals = append(literals, requiredLiterals(sub)...)
+		}
+		return literals
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,78 @@
+package cucumberexpressions
+
+import (
+	"regexp"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiteralIndex(t *testing.T) {
+	t.Run("finds the longest required literal", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		for source, literal := range map[string]string{
+			"I have {int} cucumbers":         " cucumbers",
+			"{int} cucumber(s)":              " cucumber",
+			"I have a cucumber/gherkin":      "I have a ",
+			"{word}":                         "",
+			"(a lot of )cucumbers in my bag": "cucumbers in my bag",
+		} {
+			expression, err := NewCucumberExpression(source, registry)
+			require.NoError(t, err)
+			require.Equal(t, literal, requiredLiteral(expression.Regexp()), source)
+		}
+
+		for source, literal := range map[string]string{
+			`^I have (\d+) cucumbers?$`: " cucumber",
+			`^(?i)i have cucumbers$`:    "",
+			`^(cukes|gherkins) x+yz$`:   "yz",
+			`^a{2,3}b{0,2}c$`:           "a",
+			`^(?:abc)*d$`:               "d",
+		} {
+			require.Equal(t, literal, requiredLiteral(regexp.MustCompile(source)), source)
+		}
+	})
+
+	t.Run("finds the expressions whose literals are in the text", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		var expressions []Expression
+		for _, source := range []string{"she sells {word}", "sea shells", "{word}", "he {word}", "hers"} {
+			expression, err := NewCucumberExpression(source, registry)
+			require.NoError(t, err)
+			expressions = append(expressions, expression)
+		}
+		index := newLiteralIndex(expressions)
+
+		require.Equal(t, []bool{true, false, true, true, false}, index.candidates("she sells cucumbers"))
+		require.Equal(t, []bool{false, true, true, false, true}, index.candidates("ushers sea shells"))
+		require.Equal(t, []bool{false, false, true, false, false}, index.candidates(""))
+	})
+
+	t.Run("keeps the expressions that match", func(t *testing.T) {
+		err := quick.Check(func(sample ExpressionSample, other ExpressionSample) bool {
+			registry := NewParameterTypeRegistry()
+			var expressions []Expression
+			for _, source := range []string{sample.Expression, other.Expression} {
+				expression, err := NewCucumberExpression(source, registry)
+				require.NoError(t, err)
+				expressions = append(expressions, expression)
+			}
+			index := newLiteralIndex(expressions)
+
+			for _, text := range []string{sample.Text, sample.NearMiss, other.Text, other.NearMiss} {
+				candidates := index.candidates(text)
+				for i, expression := range expressions {
+					args, err := expression.Match(text)
+					require.NoError(t, err)
+					if args != nil && !candidates[i] {
+						t.Errorf("%s matches %q but is not a candidate", expression.Source(), text)
+						return false
+					}
+				}
+			}
+			return true
+		}, nil)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,99 @@
+package cucumberexpressions
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// maxLogLineLength is the length of the longest line Extract reads
+const maxLogLineLength = 1024 * 1024
+
+// LogExtractor extracts fields from log lines with a Cucumber Expression,
+// as an alternative to a regular expression that is easier to read:
+//
+//	{} level={word} took {int}ms
+//
+// The fields are named after their parameter types, with 2, 3 and so on
+// appended when a parameter type is used more than once. The anonymous parameter
+// type {} matches text to skip, and is not a field.
+type LogExtractor struct {
+	expression Expression
+}
+
+// LogRecord is the fields of a log line that matched
+type LogRecord struct {
+	Line   int
+	Text   string
+	Fields map[string]interface{}
+}
+
+func NewLogExtractor(expression string, parameterTypeRegistry *ParameterTypeRegistry) (*LogExtractor, error) {
+	compiled, err := NewCucumberExpression(expression, parameterTypeRegistry)
+	if err != nil {
+		return nil, err
+	}
+	return &LogExtractor{expression: compiled}, nil
+}
+
+// ExtractLine returns the fields of a line, or nil when it doesn't match.
+func (e *LogExtractor) ExtractLine(line string) (map[string]interface{}, error) {
+	args, err := e.expression.Match(line)
+	if err != nil || args == nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	for i, name := range argumentNames(args) {
+		if args[i].ParameterType().isDeAnonymized() {
+			continue
+		}
+		value, err := transformedValue(args[i])
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = value
+	}
+	return fields, nil
+}
+
+// Extract reads lines from reader and calls emit with the fields of each
+// line that matches, until the end of reader or an error. Lines that don't
+// match are skipped.
+func (e *LogExtractor) Extract(reader io.Reader, emit func(record LogRecord) error) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, maxLogLineLength)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		fields, err := e.ExtractLine(text)
+		if err != nil {
+			return &logLineError{line, err}
+		}
+		if fields == nil {
+			continue
+		}
+		if err := emit(LogRecord{Line: line, Text: text, Fields: fields}); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// logLineError is an error extracting the fields of a log line
+type logLineError struct {
+	line int
+	err  error
+}
+
+func (e *logLineError) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e *logLineError) localize(messages Messages) string {
+	return messages.format(LogLineMessage, e.line, e.err)
+}
+
+func (e *logLineError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,92 @@
+package cucumberexpressions
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogExtractor(t *testing.T) {
+	t.Run("extracts typed fields from a line", func(t *testing.T) {
+		extractor, err := NewLogExtractor("{} level={word} took {int}ms", NewParameterTypeRegistry())
+		require.NoError(t, err)
+
+		fields, err := extractor.ExtractLine("2021-03-04T10:00:00Z level=info took 42ms")
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"word": "info", "int": 42}, fields)
+	})
+
+	t.Run("numbers repeated parameter types", func(t *testing.T) {
+		extractor, err := NewLogExtractor("moved from {int} to {int} in {float}s", NewParameterTypeRegistry())
+		require.NoError(t, err)
+
+		fields, err := extractor.ExtractLine("moved from 3 to 7 in 0.5s")
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"int": 3, "int2": 7, "float": 0.5}, fields)
+	})
+
+	t.Run("does not extract lines that don't match", func(t *testing.T) {
+		extractor, err := NewLogExtractor("took {int}ms", NewParameterTypeRegistry())
+		require.NoError(t, err)
+
+		fields, err := extractor.ExtractLine("started")
+		require.NoError(t, err)
+		require.Nil(t, fields)
+	})
+
+	t.Run("streams the records of matching lines", func(t *testing.T) {
+		extractor, err := NewLogExtractor("{} took {int}ms", NewParameterTypeRegistry())
+		require.NoError(t, err)
+
+		log := "GET / took 12ms\r\nstarted\nPOST /login took 340ms\n"
+		var records []LogRecord
+		require.NoError(t, extractor.Extract(strings.NewReader(log), func(record LogRecord) error {
+			records = append(records, record)
+			return nil
+		}))
+		require.Equal(t, []LogRecord{
+			{Line: 1, Text: "GET / took 12ms", Fields: map[string]interface{}{"int": 12}},
+			{Line: 3, Text: "POST /login took 340ms", Fields: map[string]interface{}{"int": 340}},
+		}, records)
+	})
+
+	t.Run("stops at the error of emit", func(t *testing.T) {
+		extractor, err := NewLogExtractor("{int}", NewParameterTypeRegistry())
+		require.NoError(t, err)
+
+		stop := errors.New("stop")
+		lines := 0
+		err = extractor.Extract(strings.NewReader("1\n2\n3\n"), func(record LogRecord) error {
+			lines++
+			return stop
+		})
+		require.Equal(t, stop, err)
+		require.Equal(t, 1, lines)
+	})
+
+	t.Run("returns transform errors with the line", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		port, err := NewParameterType("port", []*regexp.Regexp{regexp.MustCompile(`\d+`)}, "int", func(args ...*string) interface{} {
+			p, err := strconv.ParseUint(*args[0], 10, 16)
+			if err != nil {
+				panic(err)
+			}
+			return p
+		}, false, false, false)
+		require.NoError(t, err)
+		require.NoError(t, registry.DefineParameterType(port))
+		extractor, err := NewLogExtractor("listening on {port}", registry)
+		require.NoError(t, err)
+
+		err = extractor.Extract(strings.NewReader("listening on 80\nlistening on 99999\n"), func(record LogRecord) error {
+			return nil
+		})
+		var numError *strconv.NumError
+		require.True(t, errors.As(err, &numError))
+		require.EqualError(t, err, `line 2: strconv.ParseUint: parsing "99999": value out of range`)
+	})
+}
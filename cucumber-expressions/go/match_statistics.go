@@ -0,0 +1,138 @@
+package cucumberexpressions
+
+import (
+	"container/list"
+	"reflect"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MatchStatistics collects counters for expressions wrapped with Instrument.
+// Expressions that are attempted often but rarely hit are candidates for
+// reordering or a literal prefilter.
+type MatchStatistics struct {
+	mutex sync.Mutex
+	// statistics are in the order the expressions were first attempted
+	statistics             []*ExpressionStatistics
+	statisticsByExpression map[Expression]*ExpressionStatistics
+	recentMatchCapacity    int
+	recentMatches          *list.List
+	recentMatchesByText    map[string]*list.Element
+}
+
+// ExpressionStatistics are the counters of an instrumented expression.
+// Expressions with the same source, such as a Cucumber Expression and a
+// regular expression, or the same expression compiled with two registries,
+// are counted separately.
+type ExpressionStatistics struct {
+	Expression Expression
+	Source     string
+	Attempts   int
+	Hits       int
+	Duration   time.Duration
+}
+
+type RecentMatch struct {
+	Text       string
+	Expression Expression
+	Source     string
+}
+
+// NewMatchStatistics remembers up to recentMatchCapacity recently matched
+// texts, evicting the least recently used one.
+func NewMatchStatistics(recentMatchCapacity int) *MatchStatistics {
+	return &MatchStatistics{
+		statisticsByExpression: map[Expression]*ExpressionStatistics{},
+		recentMatchCapacity:    recentMatchCapacity,
+		recentMatches:          list.New(),
+		recentMatchesByText:    map[string]*list.Element{},
+	}
+}
+
+func (m *MatchStatistics) Instrument(expression Expression) Expression {
+	return &instrumentedExpression{expression: expression, statistics: m}
+}
+
+// Expressions returns the statistics of all instrumented expressions that
+// have been attempted, most attempted first.
+func (m *MatchStatistics) Expressions() []ExpressionStatistics {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	result := make([]ExpressionStatistics, 0, len(m.statistics))
+	for _, statistics := range m.statistics {
+		result = append(result, *statistics)
+	}
+	sort.SliceStable(result, func(i int, j int) bool {
+		if result[i].Attempts != result[j].Attempts {
+			return result[i].Attempts > result[j].Attempts
+		}
+		return result[i].Source < result[j].Source
+	})
+	return result
+}
+
+// RecentMatches returns the recently matched texts, most recent first.
+func (m *MatchStatistics) RecentMatches() []RecentMatch {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	result := make([]RecentMatch, 0, m.recentMatches.Len())
+	for element := m.recentMatches.Front(); element != nil; element = element.Next() {
+		result = append(result, element.Value.(RecentMatch))
+	}
+	return result
+}
+
+func (m *MatchStatistics) record(expression Expression, text string, hit bool, duration time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	statistics, ok := m.statisticsByExpression[expression]
+	if !ok {
+		statistics = &ExpressionStatistics{Expression: expression, Source: expression.Source()}
+		m.statisticsByExpression[expression] = statistics
+		m.statistics = append(m.statistics, statistics)
+	}
+	statistics.Attempts++
+	statistics.Duration += duration
+	if !hit {
+		return
+	}
+	statistics.Hits++
+
+	if m.recentMatchCapacity <= 0 {
+		return
+	}
+	recentMatch := RecentMatch{Text: text, Expression: expression, Source: statistics.Source}
+	if element, ok := m.recentMatchesByText[text]; ok {
+		element.Value = recentMatch
+		m.recentMatches.MoveToFront(element)
+		return
+	}
+	m.recentMatchesByText[text] = m.recentMatches.PushFront(recentMatch)
+	if m.recentMatches.Len() > m.recentMatchCapacity {
+		oldest := m.recentMatches.Back()
+		m.recentMatches.Remove(oldest)
+		delete(m.recentMatchesByText, oldest.Value.(RecentMatch).Text)
+	}
+}
+
+type instrumentedExpression struct {
+	expression Expression
+	statistics *MatchStatistics
+}
+
+func (i *instrumentedExpression) Match(text string, typeHints ...reflect.Type) ([]*Argument, error) {
+	start := time.Now()
+	args, err := i.expression.Match(text, typeHints...)
+	i.statistics.record(i.expression, text, args != nil, time.Since(start))
+	return args, err
+}
+
+func (i *instrumentedExpression) Regexp() *regexp.Regexp {
+	return i.expression.Regexp()
+}
+
+func (i *instrumentedExpression) Source() string {
+	return i.expression.Source()
+}
@@ -0,0 +1,81 @@
+package cucumberexpressions
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchStatistics(t *testing.T) {
+	instrument := func(t *testing.T, statistics *MatchStatistics, expr string) Expression {
+		expression, err := NewCucumberExpression(expr, NewParameterTypeRegistry())
+		require.NoError(t, err)
+		return statistics.Instrument(expression)
+	}
+
+	t.Run("counts attempts and hits per expression", func(t *testing.T) {
+		statistics := NewMatchStatistics(0)
+		cukes := instrument(t, statistics, "I have {int} cukes")
+		belly := instrument(t, statistics, "my belly")
+
+		for _, text := range []string{"I have 1 cukes", "my belly", "I have 2 cukes"} {
+			_, err := cukes.Match(text)
+			require.NoError(t, err)
+			_, err = belly.Match(text)
+			require.NoError(t, err)
+		}
+
+		expressions := statistics.Expressions()
+		require.Len(t, expressions, 2)
+		require.Equal(t, "I have {int} cukes", expressions[0].Source)
+		require.Equal(t, 3, expressions[0].Attempts)
+		require.Equal(t, 2, expressions[0].Hits)
+		require.Equal(t, "my belly", expressions[1].Source)
+		require.Equal(t, 3, expressions[1].Attempts)
+		require.Equal(t, 1, expressions[1].Hits)
+	})
+
+	t.Run("instrumented expressions still match", func(t *testing.T) {
+		expression := instrument(t, NewMatchStatistics(0), "I have {int} cukes")
+		args, err := expression.Match("I have 7 cukes")
+		require.NoError(t, err)
+		require.Equal(t, 7, args[0].GetValue())
+		require.Equal(t, "I have {int} cukes", expression.Source())
+	})
+
+	t.Run("remembers the most recently matched texts", func(t *testing.T) {
+		statistics := NewMatchStatistics(2)
+		expression := instrument(t, statistics, "I have {int} cukes")
+
+		for _, text := range []string{"I have 1 cukes", "I have 2 cukes", "I have 1 cukes", "I have 3 cukes", "no match"} {
+			_, err := expression.Match(text)
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, []RecentMatch{
+			{Text: "I have 3 cukes", Expression: expression.(*instrumentedExpression).expression, Source: "I have {int} cukes"},
+			{Text: "I have 1 cukes", Expression: expression.(*instrumentedExpression).expression, Source: "I have {int} cukes"},
+		}, statistics.RecentMatches())
+	})
+
+	t.Run("counts expressions with the same source separately", func(t *testing.T) {
+		statistics := NewMatchStatistics(0)
+		cucumberExpression := instrument(t, statistics, "I have cukes")
+		regularExpression := statistics.Instrument(NewRegularExpression(regexp.MustCompile("I have cukes"), NewParameterTypeRegistry()))
+
+		_, err := cucumberExpression.Match("I have cukes")
+		require.NoError(t, err)
+		_, err = regularExpression.Match("I have cukes")
+		require.NoError(t, err)
+		_, err = regularExpression.Match("I have cukes")
+		require.NoError(t, err)
+
+		expressions := statistics.Expressions()
+		require.Len(t, expressions, 2)
+		require.IsType(t, &RegularExpression{}, expressions[0].Expression)
+		require.Equal(t, 2, expressions[0].Attempts)
+		require.IsType(t, &CucumberExpression{}, expressions[1].Expression)
+		require.Equal(t, 1, expressions[1].Attempts)
+	})
+}
@@ -0,0 +1,227 @@
+package cucumberexpressions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// MessageKey identifies the text of an error message
+type MessageKey string
+
+const (
+	ProblemAtColumnMessage                      MessageKey = "problem_at_column"
+	MissingEndTokenMessage                      MessageKey = "missing_end_token"
+	EscapeOptionalHintMessage                   MessageKey = "escape_optional_hint"
+	EscapedEndOfLineMessage                     MessageKey = "escaped_end_of_line"
+	EscapedEndOfLineHintMessage                 MessageKey = "escaped_end_of_line_hint"
+	InvalidParameterTypeNameMessage             MessageKey = "invalid_parameter_type_name"
+	InvalidParameterTypeNameHintMessage         MessageKey = "invalid_parameter_type_name_hint"
+	ParameterInOptionalMessage                  MessageKey = "parameter_in_optional"
+	ParameterInAlternativeMessage               MessageKey = "parameter_in_alternative"
+	UndefinedParameterTypeMessage               MessageKey = "undefined_parameter_type"
+	DidYouMeanMessage                           MessageKey = "did_you_mean"
+	AmbiguousParameterTypeMessage               MessageKey = "ambiguous_parameter_type"
+	AnonymousParameterTypeAlreadyDefinedMessage MessageKey = "anonymous_parameter_type_already_defined"
+	ParameterTypeAlreadyDefinedMessage          MessageKey = "parameter_type_already_defined"
+	PreferentialParameterTypeConflictMessage    MessageKey = "preferential_parameter_type_conflict"
+	IllegalParameterNameCharacterMessage        MessageKey = "illegal_parameter_name_character"
+	ParameterTypeRegexpFlagsMessage             MessageKey = "parameter_type_regexp_flags"
+	CompileErrorMessage                         MessageKey = "compile_error"
+	CompileErrorsMessage                        MessageKey = "compile_errors"
+	TransformFailedMessage                      MessageKey = "transform_failed"
+	ArgumentTypeMismatchMessage                 MessageKey = "argument_type_mismatch"
+	InvalidHandlerMessage                       MessageKey = "invalid_handler"
+	HandlerArgumentCountMessage                 MessageKey = "handler_argument_count"
+	NoRouteMessage                              MessageKey = "no_route"
+	AmbiguousRouteMessage                       MessageKey = "ambiguous_route"
+	LogLineMessage                              MessageKey = "log_line"
+	UnknownArgumentFieldMessage                 MessageKey = "unknown_argument_field"
+	UnexportedArgumentFieldMessage              MessageKey = "unexported_argument_field"
+	HandlerPanicMessage                         MessageKey = "handler_panic"
+	UnknownTransformMessage                     MessageKey = "unknown_transform"
+	UnsupportedTimeLayoutMessage                MessageKey = "unsupported_time_layout"
+	OptionalParameterDefaultsMessage            MessageKey = "optional_parameter_defaults"
+	DefaultMismatchMessage                      MessageKey = "default_mismatch"
+	InvalidBoundaryMessage                      MessageKey = "invalid_boundary"
+	AmbiguousBoolWordMessage                    MessageKey = "ambiguous_bool_word"
+	EmptyEnumMessage                            MessageKey = "empty_enum"
+	InvalidParameterNameMessage                 MessageKey = "invalid_parameter_name"
+	DuplicateParameterNameMessage               MessageKey = "duplicate_parameter_name"
+	InvalidTemplateFunctionNameMessage          MessageKey = "invalid_template_function_name"
+	TemplateFunctionAlreadyDefinedMessage       MessageKey = "template_function_already_defined"
+	TemplateFailedMessage                       MessageKey = "template_failed"
+	HandlerParameterTypeMessage                 MessageKey = "handler_parameter_type"
+	ArgumentOutOfRangeMessage                   MessageKey = "argument_out_of_range"
+	SyntaxNotEnabledMessage                     MessageKey = "syntax_not_enabled"
+	SyntaxNotEnabledHintMessage                 MessageKey = "syntax_not_enabled_hint"
+	SyntaxNotSwitchableMessage                  MessageKey = "syntax_not_switchable"
+)
+
+// Messages are the texts of error messages in a language. They are fmt
+// formats with the same arguments as the English ones, which can be
+// reordered with explicit argument indexes such as %[2]s.
+type Messages map[MessageKey]string
+
+var englishMessages = Messages{
+	ProblemAtColumnMessage:                      "This Cucumber Expression has a problem at column %d:",
+	MissingEndTokenMessage:                      "The '%s' does not have a matching '%s'",
+	EscapeOptionalHintMessage:                   "If you did not intend to use optional text you can use '\\\\(' to escape the '('",
+	EscapedEndOfLineMessage:                     "The end of line can not be escaped",
+	EscapedEndOfLineHintMessage:                 "'\\\\' only escapes a '(', '{' or '/' after it",
+	InvalidParameterTypeNameMessage:             "Parameter names may not contain '{', '(', ')' or '/'",
+	InvalidParameterTypeNameHintMessage:         "Did you mean to use a regular expression?",
+	ParameterInOptionalMessage:                  "Parameter types cannot be optional: %s",
+	ParameterInAlternativeMessage:               "Parameter types cannot be alternative: %s",
+	UndefinedParameterTypeMessage:               "Undefined parameter type {%s}",
+	DidYouMeanMessage:                           "Did you mean %s?",
+	AnonymousParameterTypeAlreadyDefinedMessage: "The anonymous parameter type has already been defined",
+	ParameterTypeAlreadyDefinedMessage:          "There is already a parameter type with name %s",
+	PreferentialParameterTypeConflictMessage:    "There can only be one preferential parameter type per regexp. The regexp /%s/ is used for two preferential parameter types, {%s} and {%s}",
+	IllegalParameterNameCharacterMessage:        "illegal character '%s' in parameter name {%s}",
+	ParameterTypeRegexpFlagsMessage:             "ParameterType Regexps can't use flags",
+	CompileErrorMessage:                         "expression %d (%s): %s",
+	CompileErrorsMessage:                        "%d of the expressions could not be compiled:\n%s",
+	TransformFailedMessage:                      "Could not transform {%s}: %s",
+	ArgumentTypeMismatchMessage:                 "The value of {%s} is of type %s, not %s",
+	InvalidHandlerMessage:                       "The handler of %s must be a function that returns nothing or an error, not a %s",
+	HandlerArgumentCountMessage:                 "The handler of %s has %d parameters, but the expression has %d",
+	NoRouteMessage:                              "No route matches '%s'",
+	AmbiguousRouteMessage:                       "Several routes match '%s':\n   %s",
+	LogLineMessage:                              "line %d: %s",
+	UnknownArgumentFieldMessage:                 "The handler of %s has a field %s for the argument %s, which the expression doesn't have",
+	UnexportedArgumentFieldMessage:              "The handler of %s has a field %s for an argument, which must be exported",
+	HandlerPanicMessage:                         "The handler of %s panicked: %v",
+	UnknownTransformMessage:                     "The parameter type {%s} has an unknown transform %s",
+	UnsupportedTimeLayoutMessage:                "The layout %q of {%s} is not supported",
+	OptionalParameterDefaultsMessage:            "The expression %s has %d parameters in optionals, but %d defaults",
+	DefaultMismatchMessage:                      "The default %q does not match {%s}",
+	InvalidBoundaryMessage:                      "The character %q is whitespace or special, so it can't be a boundary",
+	AmbiguousBoolWordMessage:                    "The word %q of {bool} is already %t",
+	EmptyEnumMessage:                            "The enum {%s} has no values",
+	InvalidParameterNameMessage:                 "The parameter name %q in %s must be a letter or '_' followed by letters, digits or '_'",
+	DuplicateParameterNameMessage:               "The parameter name %q is used twice in %s",
+	InvalidTemplateFunctionNameMessage:          "The template function name %q must be a letter or '_' followed by letters, digits or '_'",
+	TemplateFunctionAlreadyDefinedMessage:       "There is already a template function with name %s",
+	TemplateFailedMessage:                       "Could not resolve %s at column %d: %s",
+	HandlerParameterTypeMessage:                 "The handler of %s takes %s for {%s}, whose values are of type %s",
+	ArgumentOutOfRangeMessage:                   "The value %v of {%s} is out of the range of %s",
+	SyntaxNotEnabledMessage:                     "%s requires enabling the %s syntax",
+	SyntaxNotEnabledHintMessage:                 "It can be enabled with ParameterTypeRegistry.SetSyntaxEnabled(%q, true)",
+	SyntaxNotSwitchableMessage:                  "The %s syntax can't be enabled or disabled",
+	AmbiguousParameterTypeMessage: `Your Regular Expression /%s/
+matches multiple parameter types with regexp /%s/:
+   %s
+
+I couldn't decide which one to use. You have two options:
+
+1) Use a Cucumber Expression instead of a Regular Expression. Try one of these:
+   %s
+
+2) Make one of the parameter types preferential and continue to use a Regular Expression.
+
+`,
+}
+
+var messagesByLanguage = map[string]Messages{"en": englishMessages}
+var messagesMutex sync.RWMutex
+
+// AddMessages adds the error messages of a language, such as "de" or
+// "pt-BR". Messages it doesn't have are in English.
+func AddMessages(language string, messages Messages) {
+	messagesMutex.Lock()
+	defer messagesMutex.Unlock()
+	merged := Messages{}
+	for key, text := range messagesByLanguage[language] {
+		merged[key] = text
+	}
+	for key, text := range messages {
+		merged[key] = text
+	}
+	messagesByLanguage[language] = merged
+}
+
+// LocalizeError returns the message of err in a language, falling back
+// from a regional language such as "pt-BR" to "pt", and then to English.
+// Errors that aren't from this package keep their message.
+func LocalizeError(err error, language string) string {
+	var l localizable
+	if errors.As(err, &l) {
+		return l.localize(messagesFor(language))
+	}
+	return err.Error()
+}
+
+func messagesFor(language string) Messages {
+	messagesMutex.RLock()
+	defer messagesMutex.RUnlock()
+	if messages, ok := messagesByLanguage[language]; ok {
+		return messages
+	}
+	if i := strings.IndexAny(language, "-_"); i > 0 {
+		if messages, ok := messagesByLanguage[language[:i]]; ok {
+			return messages
+		}
+	}
+	return englishMessages
+}
+
+// format formats the message with key, or the English one when there is
+// no translation
+func (m Messages) format(key MessageKey, args ...interface{}) string {
+	text, ok := m[key]
+	if !ok {
+		text = englishMessages[key]
+	}
+	return fmt.Sprintf(text, m.localizeArgs(args)...)
+}
+
+func (m Messages) localizeArgs(args []interface{}) []interface{} {
+	localized := make([]interface{}, len(args))
+	for i, arg := range args {
+		if l, ok := arg.(localizable); ok {
+			localized[i] = l.localize(m)
+		} else {
+			localized[i] = arg
+		}
+	}
+	return localized
+}
+
+// problem renders a problem with an expression with a pointer under the
+// offending part, such as:
+//
+//	This Cucumber Expression has a problem at column 7:
+//
+//	three (blind mice
+//	      ^
+//	The '(' does not have a matching ')'.
+//	If you did not intend to use optional text you can use '\(' to escape the '('
+func (m Messages) problem(expression string, start int, end int, problem string, hint string) string {
+	return fmt.Sprintf("%s\n\n%s\n%s\n%s.\n%s", m.format(ProblemAtColumnMessage, start+1), expression, pointAtSpan(start, end), problem, hint)
+}
+
+// localizable errors have messages in other languages than English
+type localizable interface {
+	error
+	localize(messages Messages) string
+}
+
+// messageError is an error with a message of the catalog
+type messageError struct {
+	key  MessageKey
+	args []interface{}
+}
+
+func newMessageError(key MessageKey, args ...interface{}) error {
+	return &messageError{key, args}
+}
+
+func (e *messageError) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e *messageError) localize(messages Messages) string {
+	return messages.format(e.key, e.args...)
+}
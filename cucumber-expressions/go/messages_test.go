@@ -0,0 +1,66 @@
+package cucumberexpressions
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMessages(t *testing.T) {
+	AddMessages("de", Messages{
+		ProblemAtColumnMessage:        "Dieser Cucumber-Ausdruck hat ein Problem in Spalte %d:",
+		MissingEndTokenMessage:        "Zu '%s' fehlt das passende '%s'",
+		UndefinedParameterTypeMessage: "Unbekannter Parametertyp {%s}",
+		CompileErrorsMessage:          "%d der Ausdrücke konnten nicht kompiliert werden:\n%s",
+	})
+
+	t.Run("localizes parse errors", func(t *testing.T) {
+		_, err := Parse("three (blind mice")
+		require.Equal(t, `Dieser Cucumber-Ausdruck hat ein Problem in Spalte 7:
+
+three (blind mice
+      ^
+Zu '(' fehlt das passende ')'.
+If you did not intend to use optional text you can use '\\(' to escape the '('`, LocalizeError(err, "de"))
+	})
+
+	t.Run("keeps English messages for Error", func(t *testing.T) {
+		_, err := NewCucumberExpression("{unknown}", NewParameterTypeRegistry())
+		require.EqualError(t, err, "Undefined parameter type {unknown}")
+		require.Equal(t, "Unbekannter Parametertyp {unknown}", LocalizeError(err, "de"))
+	})
+
+	t.Run("falls back from a regional language", func(t *testing.T) {
+		_, err := NewCucumberExpression("{unknown}", NewParameterTypeRegistry())
+		require.Equal(t, "Unbekannter Parametertyp {unknown}", LocalizeError(err, "de-CH"))
+	})
+
+	t.Run("falls back to English", func(t *testing.T) {
+		_, err := NewCucumberExpression("{unknown}", NewParameterTypeRegistry())
+		require.Equal(t, "Undefined parameter type {unknown}", LocalizeError(err, "fr"))
+	})
+
+	t.Run("localizes wrapped errors", func(t *testing.T) {
+		_, err := CompileAll([]string{"{unknown}"}, NewParameterTypeRegistry(), 1)
+		require.Equal(t, "1 der Ausdrücke konnten nicht kompiliert werden:\nexpression 0 ({unknown}): Unbekannter Parametertyp {unknown}", LocalizeError(err, "de"))
+	})
+
+	t.Run("localizes registry errors", func(t *testing.T) {
+		AddMessages("de", Messages{
+			ParameterTypeAlreadyDefinedMessage: "Es gibt schon einen Parametertyp %s",
+		})
+		err := NewParameterTypeRegistry().DefineParameterType(createParameterType(t, "int"))
+		require.EqualError(t, err, "There is already a parameter type with name int")
+		require.Equal(t, "Es gibt schon einen Parametertyp int", LocalizeError(err, "de"))
+	})
+
+	t.Run("keeps the message of other errors", func(t *testing.T) {
+		require.Equal(t, "other", LocalizeError(errors.New("other"), "de"))
+	})
+}
+
+func createParameterType(t *testing.T, name string) *ParameterType {
+	parameterType, err := NewParameterType(name, nil, name, nil, false, false, false)
+	require.NoError(t, err)
+	return parameterType
+}
@@ -0,0 +1,74 @@
+package cucumberexpressions
+
+import (
+	"regexp"
+	"strings"
+)
+
+// numberFormat is how the numbers of a locale are written: "1,000.5" in
+// English and "1.000,5" in German
+type numberFormat struct {
+	decimalSeparator  rune
+	groupingSeparator rune
+}
+
+// numberFormats are the number formats by locale. Locales that aren't
+// there use the number format of their language, or the English one.
+var numberFormats = map[string]numberFormat{
+	"da":    {',', '.'},
+	"de":    {',', '.'},
+	"de-CH": {'.', '\''},
+	"en":    {'.', ','},
+	"es":    {',', '.'},
+	"hi":    {'.', ','},
+	"id":    {',', '.'},
+	"it":    {',', '.'},
+	"ja":    {'.', ','},
+	"ko":    {'.', ','},
+	"nl":    {',', '.'},
+	"pt":    {',', '.'},
+	"tr":    {',', '.'},
+	"zh":    {'.', ','},
+}
+
+func numberFormatFor(locale string) numberFormat {
+	locale = strings.Replace(locale, "_", "-", 1)
+	if format, ok := numberFormats[locale]; ok {
+		return format
+	}
+	if i := strings.Index(locale, "-"); i > 0 {
+		if format, ok := numberFormats[locale[:i]]; ok {
+			return format
+		}
+	}
+	return numberFormats["en"]
+}
+
+// integerRegexps match integers with or without grouping separators
+func (f numberFormat) integerRegexps() []*regexp.Regexp {
+	grouped := regexp.MustCompile(`-?\d{1,3}(?:` + regexp.QuoteMeta(string(f.groupingSeparator)) + `\d{3})+`)
+	return append([]*regexp.Regexp{grouped}, INTEGER_REGEXPS...)
+}
+
+// floatRegexps match decimal numbers with or without grouping separators
+func (f numberFormat) floatRegexps() []*regexp.Regexp {
+	decimal := regexp.QuoteMeta(string(f.decimalSeparator))
+	grouping := regexp.QuoteMeta(string(f.groupingSeparator))
+	return []*regexp.Regexp{
+		regexp.MustCompile(`[-+]?(?:\d{1,3}(?:` + grouping + `\d{3})+(?:` + decimal + `\d+)?|\d*` + decimal + `?\d+)`),
+	}
+}
+
+// normalize removes the grouping separators of a number and replaces its
+// decimal separator with a '.', for strconv
+func (f numberFormat) normalize(number string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case f.groupingSeparator:
+			return -1
+		case f.decimalSeparator:
+			return '.'
+		}
+		return r
+	}, number)
+}
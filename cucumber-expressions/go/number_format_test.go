@@ -0,0 +1,65 @@
+package cucumberexpressions
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumberFormat(t *testing.T) {
+	match := func(t *testing.T, registry *ParameterTypeRegistry, expr string, text string) []interface{} {
+		expression, err := NewCucumberExpression(expr, registry)
+		require.NoError(t, err)
+		args, err := expression.Match(text)
+		require.NoError(t, err)
+		if args == nil {
+			return nil
+		}
+		values := make([]interface{}, len(args))
+		for i, arg := range args {
+			values[i] = arg.GetValue()
+		}
+		return values
+	}
+
+	t.Run("matches numbers written like in German", func(t *testing.T) {
+		registry := NewLocalizedParameterTypeRegistry("de-DE")
+		require.Equal(t, []interface{}{1000.5}, match(t, registry, "{float} Euro", "1.000,5 Euro"))
+		require.Equal(t, []interface{}{-0.5}, match(t, registry, "{float} Euro", "-0,5 Euro"))
+		require.Equal(t, []interface{}{1234567}, match(t, registry, "{int} Gurken", "1.234.567 Gurken"))
+		require.Equal(t, []interface{}{42}, match(t, registry, "{int} Gurken", "42 Gurken"))
+		require.Nil(t, match(t, registry, "{float} Euro", "1,000.5 Euro"))
+		require.Equal(t, "1000.05", match(t, registry, "{bigdecimal} Euro", "1.000,05 Euro")[0].(*big.Float).Text('f', -1))
+		require.Equal(t, "1234567", match(t, registry, "{biginteger} Gurken", "1.234.567 Gurken")[0].(*big.Int).String())
+	})
+
+	t.Run("matches numbers written like in English", func(t *testing.T) {
+		registry := NewLocalizedParameterTypeRegistry("en-US")
+		require.Equal(t, []interface{}{1000.5}, match(t, registry, "{float} dollars", "1,000.5 dollars"))
+		require.Equal(t, []interface{}{.5}, match(t, registry, "{float} dollars", ".5 dollars"))
+		require.Equal(t, []interface{}{1000}, match(t, registry, "{int} cukes", "1,000 cukes"))
+		require.Nil(t, match(t, registry, "{int} cukes", "1,00 cukes"))
+	})
+
+	t.Run("falls back to the number format of the language and then English", func(t *testing.T) {
+		require.Equal(t, numberFormats["de"], numberFormatFor("de_AT"))
+		require.Equal(t, numberFormats["de-CH"], numberFormatFor("de-CH"))
+		require.Equal(t, numberFormats["en"], numberFormatFor("xx"))
+	})
+
+	t.Run("keeps the number format of clones and imported registries", func(t *testing.T) {
+		registry := NewLocalizedParameterTypeRegistry("de")
+		require.Equal(t, []interface{}{1000.5}, match(t, registry.Clone(), "{float}", "1.000,5"))
+
+		imported := NewLocalizedParameterTypeRegistry("de")
+		require.NoError(t, imported.UnmarshalJSON([]byte(`{"parameterTypes": []}`)))
+		require.Equal(t, []interface{}{1000.5}, match(t, imported, "{float}", "1.000,5"))
+	})
+
+	t.Run("does not change the numbers of the default registry", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		require.Nil(t, match(t, registry, "{int} cukes", "1,000 cukes"))
+		require.Equal(t, []interface{}{1.5}, match(t, registry, "{float}", "1.5"))
+	})
+}
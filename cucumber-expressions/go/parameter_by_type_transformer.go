@@ -1,6 +1,7 @@
 package cucumberexpressions
 
 import (
+	"encoding"
 	"errors"
 	"fmt"
 	"reflect"
@@ -24,12 +25,40 @@ func (s BuiltInParameterTransformer) Transform(fromValue string, toValueType int
 	}
 
 	if toValueTypeType, ok := toValueType.(reflect.Type); ok {
+		if value, ok, err := transformTextUnmarshaler(fromValue, toValueTypeType); ok {
+			return value, err
+		}
 		return transformKind(fromValue, toValueTypeType.Kind())
 	}
 
 	return nil, createError(fromValue, toValueType)
 }
 
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// transformTextUnmarshaler converts to types implementing
+// encoding.TextUnmarshaler, so domain types don't need a parameter type of
+// their own. ok is false when the type doesn't implement it.
+func transformTextUnmarshaler(fromValue string, toValueType reflect.Type) (value interface{}, ok bool, err error) {
+	if toValueType.Kind() == reflect.Ptr && toValueType.Implements(textUnmarshalerType) {
+		pointer := reflect.New(toValueType.Elem())
+		err = pointer.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(fromValue))
+		if err != nil {
+			return nil, true, err
+		}
+		return pointer.Interface(), true, nil
+	}
+	if reflect.PtrTo(toValueType).Implements(textUnmarshalerType) {
+		pointer := reflect.New(toValueType)
+		err = pointer.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(fromValue))
+		if err != nil {
+			return nil, true, err
+		}
+		return pointer.Elem().Interface(), true, nil
+	}
+	return nil, false, nil
+}
+
 func transformKind(fromValue string, toValueKind reflect.Kind) (interface{}, error) {
 	switch toValueKind {
 	case reflect.String:
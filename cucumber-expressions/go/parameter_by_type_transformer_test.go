@@ -1,11 +1,29 @@
 package cucumberexpressions
 
 import (
+	"fmt"
 	"github.com/stretchr/testify/require"
 	"reflect"
+	"strings"
 	"testing"
 )
 
+type semVer struct {
+	major, minor, patch int
+}
+
+func (s *semVer) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%d.%d.%d", &s.major, &s.minor, &s.patch)
+	return err
+}
+
+type userID string
+
+func (u *userID) UnmarshalText(text []byte) error {
+	*u = userID(strings.ToUpper(string(text)))
+	return nil
+}
+
 func TestConvert(t *testing.T) {
 	t.Run("converts to type string", func(t *testing.T) {
 		typeOfString := reflect.TypeOf("string")
@@ -45,6 +63,18 @@ func TestConvert(t *testing.T) {
 		assertTransforms(t, float64(4.2e+12), "4.2e12", reflect.Float64)
 	})
 
+	t.Run("converts to types implementing encoding.TextUnmarshaler", func(t *testing.T) {
+		assertTransforms(t, semVer{1, 2, 3}, "1.2.3", reflect.TypeOf(semVer{}))
+		assertTransforms(t, &semVer{1, 2, 3}, "1.2.3", reflect.TypeOf(&semVer{}))
+		assertTransforms(t, userID("ABC"), "abc", reflect.TypeOf(userID("")))
+	})
+
+	t.Run("returns errors from encoding.TextUnmarshaler", func(t *testing.T) {
+		transformer := BuiltInParameterTransformer{}
+		_, err := transformer.Transform("one.two", reflect.TypeOf(semVer{}))
+		require.Error(t, err)
+	})
+
 	t.Run("errors un supported kind", func(t *testing.T) {
 		transformer := BuiltInParameterTransformer{}
 		_, err := transformer.Transform("Barbara Liskov", reflect.Complex64)
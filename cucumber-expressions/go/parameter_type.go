@@ -1,15 +1,17 @@
 package cucumberexpressions
 
 import (
-	"errors"
-	"fmt"
 	"reflect"
 	"regexp"
 )
 
 var HAS_FLAG_REGEXP = regexp.MustCompile(`\(\?[imsU-]+(:.*)?\)`)
 var UNESCAPE_REGEXP = regexp.MustCompile(`(\\([\[$.|?*+\]]))`)
-var ILLEGAL_PARAMETER_NAME_REGEXP = regexp.MustCompile(`([\[\]()$.|?*+])`)
+
+// ILLEGAL_PARAMETER_NAME_REGEXP matches the characters parameter type names
+// can't have. A ':' separates the name of a parameter from its type, as in
+// {count:int}, so a parameter type named with one couldn't be used.
+var ILLEGAL_PARAMETER_NAME_REGEXP = regexp.MustCompile(`([\[\]()$.|?*+:])`)
 
 type ParameterType struct {
 	name                           string
@@ -19,13 +21,22 @@ type ParameterType struct {
 	useForSnippets                 bool
 	preferForRegexpMatch           bool
 	useRegexpMatchAsStrongTypeHint bool
+	// deAnonymized tells if the parameter type is an anonymous one that got
+	// the type of a type hint when it was matched
+	deAnonymized bool
+	// valueType is the Go type of the values of the transform, or nil when
+	// it isn't known, as the transform returns an interface{}
+	valueType reflect.Type
+	// decodesJSON tells if the text of arguments is JSON, which can be
+	// decoded to the type of a handler parameter
+	decodesJSON bool
 }
 
 func CheckParameterTypeName(typeName string) error {
 	unescapedTypeName := UNESCAPE_REGEXP.ReplaceAllString(typeName, "$2")
 	if ILLEGAL_PARAMETER_NAME_REGEXP.MatchString(typeName) {
 		c := ILLEGAL_PARAMETER_NAME_REGEXP.FindStringSubmatch(typeName)[0]
-		return fmt.Errorf("illegal character '%s' in parameter name {%s}", c, unescapedTypeName)
+		return newMessageError(IllegalParameterNameCharacterMessage, c, unescapedTypeName)
 	}
 	return nil
 }
@@ -38,7 +49,7 @@ func NewParameterType(name string, regexps []*regexp.Regexp, type1 string, trans
 	}
 	for _, r := range regexps {
 		if HAS_FLAG_REGEXP.MatchString(r.String()) {
-			return nil, errors.New("ParameterType Regexps can't use flags")
+			return nil, newMessageError(ParameterTypeRegexpFlagsMessage)
 		}
 	}
 	err := CheckParameterTypeName(name)
@@ -71,7 +82,7 @@ func createAnonymousParameterType(parameterTypeRegexp string) (*ParameterType, e
 }
 
 func (p *ParameterType) deAnonymize(type1 reflect.Type, transform func(args ...*string) interface{}) (*ParameterType, error) {
-	return NewParameterType(
+	parameterType, err := NewParameterType(
 		"anonymous",
 		p.regexps,
 		type1.Name(),
@@ -80,6 +91,11 @@ func (p *ParameterType) deAnonymize(type1 reflect.Type, transform func(args ...*
 		p.preferForRegexpMatch,
 		false,
 	)
+	if err != nil {
+		return nil, err
+	}
+	parameterType.deAnonymized = true
+	return parameterType, nil
 }
 
 func (p *ParameterType) Name() string {
@@ -129,3 +145,9 @@ func CompareParameterTypes(pt1, pt2 *ParameterType) int {
 func (p *ParameterType) isAnonymous() bool {
 	return len(p.name) == 0
 }
+
+// isDeAnonymized tells if the parameter type is an anonymous one that got
+// the type of a type hint when it was matched
+func (p *ParameterType) isDeAnonymized() bool {
+	return p.deAnonymized
+}
@@ -0,0 +1,82 @@
+package cucumberexpressions
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// LoadParameterTypes returns a registry with the built-in parameter types
+// and the ones declared in a JSON file, in the format of the JSON of a
+// ParameterTypeRegistry:
+//
+//	{"parameterTypes": [
+//		{"name": "color", "regularExpressions": ["red|blue"]},
+//		{"name": "port", "regularExpressions": ["\\d+"], "transform": "uint16"}
+//	]}
+//
+// This lets teams extend the parameter types of their expressions without
+// recompiling. The regexps must be within the DefaultRegexpBudget, or within
+// the budget of LoadParameterTypesWithin.
+func LoadParameterTypes(path string) (*ParameterTypeRegistry, error) {
+	return LoadParameterTypesWithin(path, DefaultRegexpBudget)
+}
+
+// LoadParameterTypesWithin loads parameter types like LoadParameterTypes,
+// with regexps within another budget
+func LoadParameterTypesWithin(path string, budget RegexpBudget) (*ParameterTypeRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	registry := &ParameterTypeRegistry{regexpBudget: &budget}
+	if err := json.Unmarshal(data, registry); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// WatchParameterTypes loads the parameter types of a file like
+// LoadParameterTypes, and loads them again whenever the file changes,
+// checking every interval until ctx is done. It calls reload with every
+// registry it loads, or with the error when the file can't be loaded, so
+// the caller can keep its registry:
+//
+//	go WatchParameterTypes(ctx, "parameter-types.json", time.Second, func(registry *ParameterTypeRegistry, err error) {
+//		if err == nil {
+//			err = set.Reload(sources, registry)
+//		}
+//		...
+//	})
+func WatchParameterTypes(ctx context.Context, path string, interval time.Duration, reload func(registry *ParameterTypeRegistry, err error)) {
+	// loaded is the file last loaded, and missing tells if the error that
+	// it is missing was reported
+	var loaded os.FileInfo
+	missing := false
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			if !missing {
+				loaded, missing = nil, true
+				reload(nil, err)
+			}
+		case loaded == nil || !sameFile(info, loaded):
+			loaded, missing = info, false
+			reload(LoadParameterTypes(path))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sameFile tells if a file was not changed since it was loaded
+func sameFile(info os.FileInfo, loaded os.FileInfo) bool {
+	return info.ModTime().Equal(loaded.ModTime()) && info.Size() == loaded.Size()
+}
@@ -0,0 +1,105 @@
+package cucumberexpressions
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameterTypeFile(t *testing.T) {
+	write := func(t *testing.T, path string, data string) {
+		require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+	}
+	match := func(t *testing.T, registry *ParameterTypeRegistry, expr string, text string) []*Argument {
+		expression, err := NewCucumberExpression(expr, registry)
+		require.NoError(t, err)
+		args, err := expression.Match(text)
+		require.NoError(t, err)
+		return args
+	}
+
+	t.Run("loads parameter types with built-in transforms", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "parameter-types.json")
+		write(t, path, `{"parameterTypes": [
+			{"name": "color", "regularExpressions": ["red|blue"]},
+			{"name": "port", "regularExpressions": ["\\d+"], "transform": "uint16"}
+		]}`)
+
+		registry, err := LoadParameterTypes(path)
+		require.NoError(t, err)
+		args := match(t, registry, "paint port {port} {color}", "paint port 8080 red")
+		require.Equal(t, uint16(8080), args[0].GetValue())
+		require.Equal(t, "red", args[1].GetValue())
+
+		args = match(t, registry, "paint port {port} {color}", "paint port 99999 red")
+		_, err = ArgumentValue[uint16](args[0])
+		require.EqualError(t, err, `Could not transform {port}: strconv.ParseUint: parsing "99999": value out of range`)
+	})
+
+	t.Run("does not load unknown transforms", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "parameter-types.json")
+		write(t, path, `{"parameterTypes": [{"name": "port", "regularExpressions": ["\\d+"], "transform": "port"}]}`)
+
+		_, err := LoadParameterTypes(path)
+		require.EqualError(t, err, "The parameter type {port} has an unknown transform port")
+	})
+
+	t.Run("does not load regexps over the budget", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "parameter-types.json")
+		write(t, path, `{"parameterTypes": [{"name": "slow", "regularExpressions": ["(\\w+ ){999}"]}]}`)
+
+		_, err := LoadParameterTypes(path)
+		require.EqualError(t, err, `parameter type {slow}: regexp /(\w+ ){999}/ compiles to 4997 instructions, more than 2000`)
+
+		registry, err := LoadParameterTypesWithin(path, RegexpBudget{})
+		require.NoError(t, err)
+		require.NotNil(t, registry.LookupByTypeName("slow"))
+	})
+
+	t.Run("unmarshals regexps within the budget of the registry", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		require.Equal(t, DefaultRegexpBudget, registry.RegexpBudget())
+		registry.SetRegexpBudget(RegexpBudget{MaxLength: 4})
+		err := registry.UnmarshalJSON([]byte(`{"parameterTypes": [{"name": "color", "regularExpressions": ["red|blue"]}]}`))
+		require.EqualError(t, err, "parameter type {color}: regexp /red|blue/ is longer than 4 characters")
+		require.Equal(t, RegexpBudget{MaxLength: 4}, registry.Clone().RegexpBudget())
+	})
+
+	t.Run("reloads parameter types when the file changes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "parameter-types.json")
+		write(t, path, `{"parameterTypes": [{"name": "color", "regularExpressions": ["red|blue"]}]}`)
+
+		type load struct {
+			registry *ParameterTypeRegistry
+			err      error
+		}
+		loads := make(chan load)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go WatchParameterTypes(ctx, path, time.Millisecond, func(registry *ParameterTypeRegistry, err error) {
+			loads <- load{registry, err}
+		})
+
+		loaded := <-loads
+		require.NoError(t, loaded.err)
+		require.NotNil(t, loaded.registry.LookupByTypeName("color"))
+
+		write(t, path, `{"parameterTypes": [{"name": "shape", "regularExpressions": ["circle|square"]}]}`)
+		loaded = <-loads
+		require.NoError(t, loaded.err)
+		require.Nil(t, loaded.registry.LookupByTypeName("color"))
+		require.NotNil(t, loaded.registry.LookupByTypeName("shape"))
+
+		write(t, path, `{"parameterTypes": [`)
+		loaded = <-loads
+		require.Error(t, loaded.err)
+
+		require.NoError(t, os.Remove(path))
+		loaded = <-loads
+		require.True(t, os.IsNotExist(loaded.err))
+	})
+}
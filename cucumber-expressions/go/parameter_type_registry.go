@@ -1,10 +1,15 @@
 package cucumberexpressions
 
 import (
-	"fmt"
+	"encoding/json"
+	"math/big"
 	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 var INTEGER_REGEXPS = []*regexp.Regexp{
@@ -20,28 +25,88 @@ var WORD_REGEXPS = []*regexp.Regexp{
 var STRING_REGEXPS = []*regexp.Regexp{
 	regexp.MustCompile(`"([^"\\]*(\\.[^"\\]*)*)"|'([^'\\]*(\\.[^'\\]*)*)'`),
 }
+var UUID_REGEXPS = []*regexp.Regexp{
+	regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`),
+}
+var DURATION_REGEXPS = []*regexp.Regexp{
+	regexp.MustCompile(`[-+]?(?:\d*\.?\d+(?:ns|us|µs|ms|s|m|h))+`),
+	regexp.MustCompile(`[-+]?\d*\.?\d+ (?:(?:nano|micro|milli)?seconds?|minutes?|hours?|days?)`),
+}
+var JSON_REGEXPS = []*regexp.Regexp{
+	regexp.MustCompile(`\{[\s\S]*\}`),
+	regexp.MustCompile(`\[[\s\S]*\]`),
+}
 var ANONYMOUS_REGEXPS = `.*`
 
+// ParameterTypeRegistry is safe for concurrent use, so parameter types can
+// be defined while expressions are compiled and matched.
 type ParameterTypeRegistry struct {
+	mutex                  sync.RWMutex
 	parameterTypeByName    map[string]*ParameterType
 	parameterTypesByRegexp map[string][]*ParameterType
 	defaultTransformer     ParameterByTypeTransformer
+	// numberFormat is the number format of {int} and {float}, or nil for
+	// numbers without grouping separators and with a decimal point
+	numberFormat *numberFormat
+	// timeLayouts are the layouts of {date} and {datetime}
+	timeLayouts map[string][]string
+	// boolWords are the words of {bool} and their values
+	boolWords map[string]bool
+	// boundaries are the characters besides whitespace that end
+	// alternatives and words
+	boundaries string
+	// foldWhiteSpace makes whitespace in expressions match any whitespace
+	foldWhiteSpace bool
+	// templateFunctions resolve templates such as ${env:BASE_URL} in
+	// arguments
+	templateFunctions map[string]TemplateFunction
+	// enabledSyntax are the experimental syntax features that are enabled
+	enabledSyntax map[SyntaxFeature]bool
+	// pooledCompilation makes compilations reuse their parse buffers
+	pooledCompilation bool
+	// regexpBudget limits the regexps of parameter types defined in JSON,
+	// or is nil for the DefaultRegexpBudget
+	regexpBudget *RegexpBudget
 }
 
 func NewParameterTypeRegistry() *ParameterTypeRegistry {
+	return newParameterTypeRegistry(nil)
+}
+
+// NewLocalizedParameterTypeRegistry returns a registry where {int} and
+// {float} match and transform numbers written like in a locale, such as
+// "1.000,5" in "de-DE" and "1,000.5" in "en-US". Locales of which the
+// number format isn't known use the one of their language, or the English
+// one.
+func NewLocalizedParameterTypeRegistry(locale string) *ParameterTypeRegistry {
+	format := numberFormatFor(locale)
+	return newParameterTypeRegistry(&format)
+}
+
+func newParameterTypeRegistry(format *numberFormat) *ParameterTypeRegistry {
 	transformer := BuiltInParameterTransformer{}
 
 	result := &ParameterTypeRegistry{
 		parameterTypeByName:    map[string]*ParameterType{},
 		parameterTypesByRegexp: map[string][]*ParameterType{},
 		defaultTransformer:     transformer,
+		numberFormat:           format,
+		timeLayouts:            map[string][]string{},
+	}
+	integerRegexps := INTEGER_REGEXPS
+	floatRegexps := FLOAT_REGEXPS
+	normalize := func(number string) string { return number }
+	if format != nil {
+		integerRegexps = format.integerRegexps()
+		floatRegexps = format.floatRegexps()
+		normalize = format.normalize
 	}
 	intParameterType, err := NewParameterType(
 		"int",
-		INTEGER_REGEXPS,
+		integerRegexps,
 		"int",
 		func(args ...*string) interface{} {
-			i, err := transformer.Transform(*args[0], reflect.Int)
+			i, err := transformer.Transform(normalize(*args[0]), reflect.Int)
 			if err != nil {
 				panic(err)
 			}
@@ -54,13 +119,14 @@ func NewParameterTypeRegistry() *ParameterTypeRegistry {
 	if err != nil {
 		panic(err)
 	}
+	intParameterType.valueType = reflect.TypeOf(0)
 	result.DefineParameterType(intParameterType)
 	floatParameterType, err := NewParameterType(
 		"float",
-		FLOAT_REGEXPS,
+		floatRegexps,
 		"float",
 		func(args ...*string) interface{} {
-			f, err := transformer.Transform(*args[0], reflect.Float64)
+			f, err := transformer.Transform(normalize(*args[0]), reflect.Float64)
 			if err != nil {
 				panic(err)
 			}
@@ -73,7 +139,66 @@ func NewParameterTypeRegistry() *ParameterTypeRegistry {
 	if err != nil {
 		panic(err)
 	}
+	floatParameterType.valueType = reflect.TypeOf(0.0)
 	result.DefineParameterType(floatParameterType)
+	bigIntegerParameterType, err := NewParameterType(
+		"biginteger",
+		integerRegexps,
+		"*big.Int",
+		func(args ...*string) interface{} {
+			i, ok := new(big.Int).SetString(normalize(*args[0]), 10)
+			if !ok {
+				panic(&transformError{"biginteger", &strconv.NumError{Func: "SetString", Num: *args[0], Err: strconv.ErrSyntax}})
+			}
+			return i
+		},
+		false,
+		false,
+		false,
+	)
+	if err != nil {
+		panic(err)
+	}
+	bigIntegerParameterType.valueType = reflect.TypeOf((*big.Int)(nil))
+	result.defineParameterType(bigIntegerParameterType, false)
+	bigDecimalParameterType, err := NewParameterType(
+		"bigdecimal",
+		floatRegexps,
+		"*big.Float",
+		func(args ...*string) interface{} {
+			number := normalize(*args[0])
+			// Four bits per digit keep every digit of the number
+			f, _, err := big.ParseFloat(number, 10, uint(4*len(number)+64), big.ToNearestEven)
+			if err != nil {
+				panic(&transformError{"bigdecimal", err})
+			}
+			return f
+		},
+		false,
+		false,
+		false,
+	)
+	if err != nil {
+		panic(err)
+	}
+	bigDecimalParameterType.valueType = reflect.TypeOf((*big.Float)(nil))
+	result.defineParameterType(bigDecimalParameterType, false)
+	result.boolWords = map[string]bool{}
+	for _, word := range DefaultTrueWords {
+		result.boolWords[word] = true
+	}
+	for _, word := range DefaultFalseWords {
+		result.boolWords[word] = false
+	}
+	result.defineParameterType(newBoolParameterType(result.boolWords), false)
+	for name, layouts := range map[string][]string{"date": DefaultDateLayouts, "datetime": DefaultDateTimeLayouts} {
+		timeParameterType, err := newTimeParameterType(name, layouts)
+		if err != nil {
+			panic(err)
+		}
+		result.defineParameterType(timeParameterType, false)
+		result.timeLayouts[name] = layouts
+	}
 	wordParameterType, err := NewParameterType(
 		"word",
 		WORD_REGEXPS,
@@ -92,6 +217,7 @@ func NewParameterTypeRegistry() *ParameterTypeRegistry {
 	if err != nil {
 		panic(err)
 	}
+	wordParameterType.valueType = reflect.TypeOf("")
 	result.DefineParameterType(wordParameterType)
 	stringParameterType, err := NewParameterType(
 		"string",
@@ -118,7 +244,45 @@ func NewParameterTypeRegistry() *ParameterTypeRegistry {
 	if err != nil {
 		panic(err)
 	}
+	stringParameterType.valueType = reflect.TypeOf("")
 	result.DefineParameterType(stringParameterType)
+	uuidParameterType, err := NewParameterType(
+		"uuid",
+		UUID_REGEXPS,
+		"string",
+		func(args ...*string) interface{} {
+			return strings.ToLower(*args[0])
+		},
+		true,
+		false,
+		false,
+	)
+	if err != nil {
+		panic(err)
+	}
+	uuidParameterType.valueType = reflect.TypeOf("")
+	result.DefineParameterType(uuidParameterType)
+	durationParameterType, err := NewParameterType(
+		"duration",
+		DURATION_REGEXPS,
+		"time.Duration",
+		func(args ...*string) interface{} {
+			d, err := parseDuration(*args[0])
+			if err != nil {
+				panic(&transformError{"duration", err})
+			}
+			return d
+		},
+		false,
+		false,
+		false,
+	)
+	if err != nil {
+		panic(err)
+	}
+	durationParameterType.valueType = reflect.TypeOf(time.Duration(0))
+	result.defineParameterType(durationParameterType, false)
+	result.defineParameterType(newJSONParameterType(), false)
 
 	anonymouseParameterType, err := createAnonymousParameterType(ANONYMOUS_REGEXPS)
 	if err != nil {
@@ -130,6 +294,8 @@ func NewParameterTypeRegistry() *ParameterTypeRegistry {
 }
 
 func (p *ParameterTypeRegistry) ParameterTypes() []*ParameterType {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
 	result := make([]*ParameterType, len(p.parameterTypeByName))
 	index := 0
 	for _, parameterType := range p.parameterTypeByName {
@@ -140,11 +306,15 @@ func (p *ParameterTypeRegistry) ParameterTypes() []*ParameterType {
 }
 
 func (p *ParameterTypeRegistry) LookupByTypeName(name string) *ParameterType {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
 	return p.parameterTypeByName[name]
 }
 
 func (p *ParameterTypeRegistry) LookupByRegexp(parameterTypeRegexp string, expressionRegexp string, text string) (*ParameterType, error) {
+	p.mutex.RLock()
 	parameterTypes, ok := p.parameterTypesByRegexp[parameterTypeRegexp]
+	p.mutex.RUnlock()
 	if !ok {
 		return nil, nil
 	}
@@ -155,23 +325,67 @@ func (p *ParameterTypeRegistry) LookupByRegexp(parameterTypeRegexp string, expre
 	return parameterTypes[0], nil
 }
 
+// Clone returns a copy of the registry, so parameter types defined in the
+// copy, such as in a test, are not defined in the registry. The parameter
+// types themselves are shared, as they don't change.
+func (p *ParameterTypeRegistry) Clone() *ParameterTypeRegistry {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	result := &ParameterTypeRegistry{
+		parameterTypeByName:    make(map[string]*ParameterType, len(p.parameterTypeByName)),
+		parameterTypesByRegexp: make(map[string][]*ParameterType, len(p.parameterTypesByRegexp)),
+		defaultTransformer:     p.defaultTransformer,
+		numberFormat:           p.numberFormat,
+		timeLayouts:            make(map[string][]string, len(p.timeLayouts)),
+		boolWords:              p.boolWords,
+		boundaries:             p.boundaries,
+		foldWhiteSpace:         p.foldWhiteSpace,
+		templateFunctions:      p.templateFunctions,
+		enabledSyntax:          p.enabledSyntax,
+		pooledCompilation:      p.pooledCompilation,
+		regexpBudget:           p.regexpBudget,
+	}
+	for name, layouts := range p.timeLayouts {
+		result.timeLayouts[name] = layouts
+	}
+	for name, parameterType := range p.parameterTypeByName {
+		result.parameterTypeByName[name] = parameterType
+	}
+	for parameterTypeRegexp, parameterTypes := range p.parameterTypesByRegexp {
+		result.parameterTypesByRegexp[parameterTypeRegexp] = append([]*ParameterType(nil), parameterTypes...)
+	}
+	return result
+}
+
 func (p *ParameterTypeRegistry) DefineParameterType(parameterType *ParameterType) error {
+	return p.defineParameterType(parameterType, true)
+}
+
+// defineParameterType defines a parameter type, which regular expressions
+// only use for their capture groups when byRegexp is true. {biginteger} and
+// {bigdecimal} aren't, as their regexps are the ones of {int} and {float},
+// and neither are {date} and {datetime}, which can be redefined, or
+// {duration}, whose regexps would take the numbers of snippets.
+func (p *ParameterTypeRegistry) defineParameterType(parameterType *ParameterType, byRegexp bool) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
 	if _, ok := p.parameterTypeByName[parameterType.Name()]; ok {
-		if len(parameterType.Name()) == 0 {
-			return fmt.Errorf("The anonymous parameter type has already been defined")
-		}
-		return fmt.Errorf("There is already a parameter type with name %s", parameterType.Name())
+		return &ParameterTypeAlreadyDefinedError{parameterType.Name()}
 	}
 	p.parameterTypeByName[parameterType.Name()] = parameterType
+	if !byRegexp {
+		return nil
+	}
 	for _, parameterTypeRegexp := range parameterType.Regexps() {
 		if _, ok := p.parameterTypesByRegexp[parameterTypeRegexp.String()]; !ok {
 			p.parameterTypesByRegexp[parameterTypeRegexp.String()] = []*ParameterType{}
 		}
 		parameterTypes := p.parameterTypesByRegexp[parameterTypeRegexp.String()]
 		if len(parameterTypes) > 0 && parameterTypes[0].PreferForRegexpMatch() && parameterType.PreferForRegexpMatch() {
-			return fmt.Errorf("There can only be one preferential parameter type per regexp. The regexp /%s/ is used for two preferential parameter types, {%s} and {%s}", parameterTypeRegexp.String(), parameterTypes[0].Name(), parameterType.Name())
+			return newMessageError(PreferentialParameterTypeConflictMessage, parameterTypeRegexp.String(), parameterTypes[0].Name(), parameterType.Name())
 		}
-		parameterTypes = append(parameterTypes, parameterType)
+		// The slice is copied, as LookupByRegexp returns the current one
+		parameterTypes = append(append([]*ParameterType(nil), parameterTypes...), parameterType)
 		sort.Slice(parameterTypes, func(i int, j int) bool {
 			return CompareParameterTypes(parameterTypes[i], parameterTypes[j]) <= 0
 		})
@@ -179,3 +393,228 @@ func (p *ParameterTypeRegistry) DefineParameterType(parameterType *ParameterType
 	}
 	return nil
 }
+
+// jsonParameterTypeRegistry is the JSON format of a registry, for tools
+// such as editors to discover the parameter types of a project. The field
+// names of the parameter types follow the ParameterType message.
+type jsonParameterTypeRegistry struct {
+	ParameterTypes []jsonParameterType `json:"parameterTypes"`
+}
+
+type jsonParameterType struct {
+	Name                                      string   `json:"name"`
+	RegularExpressions                        []string `json:"regularExpressions"`
+	Type                                      string   `json:"type"`
+	UseForSnippets                            bool     `json:"useForSnippets"`
+	PreferForRegularExpressionMatch           bool     `json:"preferForRegularExpressionMatch"`
+	UseRegularExpressionMatchAsStrongTypeHint bool     `json:"useRegularExpressionMatchAsStrongTypeHint"`
+	// Transform is the kind, such as int or float64, the text of the first
+	// capture group is transformed to. It is only read, as the transforms
+	// of parameter types are functions.
+	Transform string `json:"transform,omitempty"`
+}
+
+// MarshalJSON returns the parameter types of the registry, sorted by name,
+// without their transforms and without the anonymous parameter type. The
+// keys of the parameter types are in the order of the fields of
+// jsonParameterType, and values are never null: regularExpressions is a
+// list, and transform is left out.
+func (p *ParameterTypeRegistry) MarshalJSON() ([]byte, error) {
+	result := jsonParameterTypeRegistry{ParameterTypes: []jsonParameterType{}}
+	for _, parameterType := range p.ParameterTypes() {
+		if parameterType.isAnonymous() {
+			continue
+		}
+		regularExpressions := make([]string, len(parameterType.Regexps()))
+		for i, parameterTypeRegexp := range parameterType.Regexps() {
+			regularExpressions[i] = parameterTypeRegexp.String()
+		}
+		result.ParameterTypes = append(result.ParameterTypes, jsonParameterType{
+			Name:                            parameterType.Name(),
+			RegularExpressions:              regularExpressions,
+			Type:                            parameterType.Type(),
+			UseForSnippets:                  parameterType.UseForSnippets(),
+			PreferForRegularExpressionMatch: parameterType.PreferForRegexpMatch(),
+			UseRegularExpressionMatchAsStrongTypeHint: parameterType.UseRegexpMatchAsStrongTypeHint(),
+		})
+	}
+	sort.Slice(result.ParameterTypes, func(i int, j int) bool {
+		return result.ParameterTypes[i].Name < result.ParameterTypes[j].Name
+	})
+	return json.Marshal(result)
+}
+
+// UnmarshalJSON replaces the registry with a new one with the number
+// format, the time layouts, the bool words, the boundaries, the whitespace
+// folding, the template functions and the regexp budget of the registry and
+// the parameter types of the JSON. As the JSON has no transforms, the
+// built-in parameter types keep their own, and the others transform the
+// text of their first capture group to the kind in their "transform" field,
+// or keep the text. The regexps of the parameter types must be within the
+// regexp budget, as JSON usually comes from configuration files.
+func (p *ParameterTypeRegistry) UnmarshalJSON(data []byte) error {
+	var result jsonParameterTypeRegistry
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	p.mutex.RLock()
+	registry := newParameterTypeRegistry(p.numberFormat)
+	for name, layouts := range p.timeLayouts {
+		registry.timeLayouts[name] = layouts
+	}
+	if p.boolWords != nil {
+		registry.boolWords = p.boolWords
+	}
+	boundaries := p.boundaries
+	budget := p.budget()
+	p.mutex.RUnlock()
+	registry.parameterTypeByName["bool"] = newBoolParameterType(registry.boolWords)
+	registry.setBoundaries(boundaries)
+	for name, layouts := range registry.timeLayouts {
+		parameterType, err := newTimeParameterType(name, layouts)
+		if err != nil {
+			return err
+		}
+		registry.parameterTypeByName[name] = parameterType
+	}
+	for _, definition := range result.ParameterTypes {
+		if registry.LookupByTypeName(definition.Name) != nil {
+			continue
+		}
+		parameterType, err := definition.parameterType()
+		if err != nil {
+			return err
+		}
+		if err := budget.CheckParameterType(parameterType); err != nil {
+			return err
+		}
+		if err := registry.DefineParameterType(parameterType); err != nil {
+			return err
+		}
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.parameterTypeByName = registry.parameterTypeByName
+	p.parameterTypesByRegexp = registry.parameterTypesByRegexp
+	p.timeLayouts = registry.timeLayouts
+	p.boolWords = registry.boolWords
+	p.boundaries = registry.boundaries
+	if p.defaultTransformer == nil {
+		// The registry was the zero value, so it isn't in use
+		p.defaultTransformer = registry.defaultTransformer
+	}
+	return nil
+}
+
+// transformKinds are the kinds a parameter type defined in JSON can
+// transform to with the BuiltInParameterTransformer
+var transformKinds = map[string]reflect.Kind{
+	"bool":    reflect.Bool,
+	"int":     reflect.Int,
+	"int8":    reflect.Int8,
+	"int16":   reflect.Int16,
+	"int32":   reflect.Int32,
+	"int64":   reflect.Int64,
+	"uint":    reflect.Uint,
+	"uint8":   reflect.Uint8,
+	"uint16":  reflect.Uint16,
+	"uint32":  reflect.Uint32,
+	"uint64":  reflect.Uint64,
+	"float32": reflect.Float32,
+	"float64": reflect.Float64,
+	"string":  reflect.String,
+}
+
+func (d jsonParameterType) parameterType() (*ParameterType, error) {
+	regexps := make([]*regexp.Regexp, len(d.RegularExpressions))
+	for i, regularExpression := range d.RegularExpressions {
+		compiled, err := regexp.Compile(regularExpression)
+		if err != nil {
+			return nil, err
+		}
+		regexps[i] = compiled
+	}
+	var transform func(args ...*string) interface{}
+	if d.Transform != "" {
+		kind, ok := transformKinds[d.Transform]
+		if !ok {
+			return nil, newMessageError(UnknownTransformMessage, d.Name, d.Transform)
+		}
+		transformer := BuiltInParameterTransformer{}
+		transform = func(args ...*string) interface{} {
+			value, err := transformer.Transform(*args[0], kind)
+			if err != nil {
+				panic(&transformError{d.Name, err})
+			}
+			return value
+		}
+	}
+	return NewParameterType(
+		d.Name,
+		regexps,
+		d.Type,
+		transform,
+		d.UseForSnippets,
+		d.PreferForRegularExpressionMatch,
+		d.UseRegularExpressionMatchAsStrongTypeHint,
+	)
+}
+
+// closestParameterTypeNames returns the names of the parameter types that
+// are closest to name, when they are a typo or two away from it
+func (p *ParameterTypeRegistry) closestParameterTypeNames(name string) []string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	closest := []string{}
+	closestDistance := maxSuggestionDistance + 1
+	for candidate := range p.parameterTypeByName {
+		if candidate == "" {
+			continue
+		}
+		distance := levenshteinDistance(name, candidate)
+		if distance >= len([]rune(name)) || distance > closestDistance {
+			continue
+		}
+		if distance < closestDistance {
+			closest = closest[:0]
+			closestDistance = distance
+		}
+		closest = append(closest, candidate)
+	}
+	sort.Strings(closest)
+	return closest
+}
+
+// maxSuggestionDistance is the number of typos in the name of a parameter
+// type for which a similar name is suggested
+const maxSuggestionDistance = 2
+
+// levenshteinDistance is the number of runes to insert, delete or
+// substitute to change a into b
+func levenshteinDistance(a string, b string) int {
+	ar := []rune(a)
+	br := []rune(b)
+	previous := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			substitution := previous[j-1]
+			if ar[i-1] != br[j-1] {
+				substitution++
+			}
+			current[j] = substitution
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(br)]
+}
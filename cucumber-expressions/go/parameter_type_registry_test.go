@@ -1,6 +1,7 @@
 package cucumberexpressions
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"testing"
@@ -66,6 +67,7 @@ func TestParameterTypeRegistry(t *testing.T) {
 		)
 		err = parameterTypeRegistry.DefineParameterType(anonymousParameter)
 		require.EqualError(t, err, fmt.Sprintf("The anonymous parameter type has already been defined"))
+		require.Equal(t, AnonymousParameterTypeAlreadyDefinedCode, ErrorCodeOf(err))
 	})
 
 	t.Run("looks up preferential parameter type by regexp", func(t *testing.T) {
@@ -176,4 +178,100 @@ func TestParameterTypeRegistry(t *testing.T) {
 				"\n",
 		)
 	})
+
+	t.Run("clones the registry", func(t *testing.T) {
+		parameterTypeRegistry := NewParameterTypeRegistry()
+		clone := parameterTypeRegistry.Clone()
+		nameParameterType, err := NewParameterType(
+			"name",
+			CAPITALISED_WORD_REGEXPS,
+			"name",
+			nil,
+			true,
+			true,
+			false,
+		)
+		require.NoError(t, err)
+		require.NoError(t, clone.DefineParameterType(nameParameterType))
+
+		require.Equal(t, nameParameterType, clone.LookupByTypeName("name"))
+		require.Nil(t, parameterTypeRegistry.LookupByTypeName("name"))
+		require.Equal(t, parameterTypeRegistry.LookupByTypeName("int"), clone.LookupByTypeName("int"))
+		require.Len(t, clone.ParameterTypes(), len(parameterTypeRegistry.ParameterTypes())+1)
+
+		parameterType, err := parameterTypeRegistry.LookupByRegexp(CAPITALISED_WORD_REGEXPS[0].String(), "", "")
+		require.NoError(t, err)
+		require.Nil(t, parameterType)
+	})
+
+	t.Run("exports parameter types as JSON", func(t *testing.T) {
+		parameterTypeRegistry := NewParameterTypeRegistry()
+		colorParameterType, err := NewParameterType(
+			"color",
+			[]*regexp.Regexp{regexp.MustCompile("red|blue")},
+			"Color",
+			nil,
+			true,
+			false,
+			false,
+		)
+		require.NoError(t, err)
+		require.NoError(t, parameterTypeRegistry.DefineParameterType(colorParameterType))
+
+		data, err := json.Marshal(parameterTypeRegistry)
+		require.NoError(t, err)
+		var exported struct {
+			ParameterTypes []map[string]interface{} `json:"parameterTypes"`
+		}
+		require.NoError(t, json.Unmarshal(data, &exported))
+		var names []string
+		for _, parameterType := range exported.ParameterTypes {
+			names = append(names, parameterType["name"].(string))
+		}
+		require.Equal(t, []string{"bigdecimal", "biginteger", "bool", "color", "date", "datetime", "duration", "float", "int", "json", "string", "uuid", "word"}, names)
+		require.Equal(t, map[string]interface{}{
+			"name":                            "color",
+			"regularExpressions":              []interface{}{"red|blue"},
+			"type":                            "Color",
+			"useForSnippets":                  true,
+			"preferForRegularExpressionMatch": false,
+			"useRegularExpressionMatchAsStrongTypeHint": false,
+		}, exported.ParameterTypes[3])
+		require.Contains(t, string(data), `{"name":"color","regularExpressions":["red|blue"],"type":"Color","useForSnippets":true,"preferForRegularExpressionMatch":false,"useRegularExpressionMatchAsStrongTypeHint":false}`)
+		require.NotContains(t, string(data), "null")
+
+		again, err := json.Marshal(parameterTypeRegistry.Clone())
+		require.NoError(t, err)
+		require.Equal(t, string(data), string(again))
+	})
+
+	t.Run("imports parameter types from JSON", func(t *testing.T) {
+		data := []byte(`{"parameterTypes": [
+			{"name": "int", "regularExpressions": ["\\d+"], "type": "int"},
+			{"name": "color", "regularExpressions": ["red|blue"], "type": "Color", "useForSnippets": true}
+		]}`)
+		parameterTypeRegistry := &ParameterTypeRegistry{}
+		require.NoError(t, json.Unmarshal(data, parameterTypeRegistry))
+
+		expression, err := NewCucumberExpression("{int} {color} balls", parameterTypeRegistry)
+		require.NoError(t, err)
+		args, err := expression.Match("3 red balls")
+		require.NoError(t, err)
+		require.Equal(t, 3, args[0].GetValue())
+		require.Equal(t, "red", args[1].GetValue())
+		require.Equal(t, "Color", parameterTypeRegistry.LookupByTypeName("color").Type())
+
+		exported, err := json.Marshal(parameterTypeRegistry)
+		require.NoError(t, err)
+		reimported := &ParameterTypeRegistry{}
+		require.NoError(t, json.Unmarshal(exported, reimported))
+		roundTripped, err := json.Marshal(reimported)
+		require.NoError(t, err)
+		require.JSONEq(t, string(exported), string(roundTripped))
+	})
+
+	t.Run("does not import invalid regular expressions", func(t *testing.T) {
+		err := json.Unmarshal([]byte(`{"parameterTypes": [{"name": "broken", "regularExpressions": ["("]}]}`), &ParameterTypeRegistry{})
+		require.Error(t, err)
+	})
 }
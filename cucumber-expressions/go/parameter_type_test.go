@@ -20,4 +20,17 @@ func TestParameterType(t *testing.T) {
 		)
 		require.EqualError(t, err, "ParameterType Regexps can't use flags")
 	})
+
+	t.Run("does not allow names that would name a parameter", func(t *testing.T) {
+		_, err := NewParameterType(
+			"count:int",
+			[]*regexp.Regexp{regexp.MustCompile(`\d+`)},
+			"int",
+			nil,
+			true,
+			true,
+			false,
+		)
+		require.EqualError(t, err, "illegal character ':' in parameter name {count:int}")
+	})
 }
@@ -0,0 +1,92 @@
+package cucumberexpressions
+
+import (
+	"sync"
+)
+
+// parseBuffers hold the runes, tokens and nodes of a parse. A tree is only
+// valid until its buffers are reset, so only the buffers of parses whose
+// tree is dropped, such as the ones of compilations, can be reused.
+type parseBuffers struct {
+	runes  []rune
+	tokens []Token
+	// stack holds the nodes of the parsers in progress
+	stack []Node
+	// arena holds the nodes of the tree, in chunks
+	arena []Node
+}
+
+// minArenaChunk is the number of nodes of the first chunk of an arena
+const minArenaChunk = 32
+
+// parseBuffersPool are the buffers of registries with pooled compilation
+var parseBuffersPool = sync.Pool{
+	New: func() interface{} {
+		return &parseBuffers{}
+	},
+}
+
+func (b *parseBuffers) runesOf(expression string) []rune {
+	b.runes = b.runes[:0]
+	for _, r := range expression {
+		b.runes = append(b.runes, r)
+	}
+	return b.runes
+}
+
+func (b *parseBuffers) tokenize(expression string, boundaries string) ([]Token, error) {
+	b.tokens = b.tokens[:0]
+	err := scanTokens(expression, boundaries, func(token Token) bool {
+		b.tokens = append(b.tokens, token)
+		return true
+	})
+	return b.tokens, err
+}
+
+// nodes returns a slice of n nodes of the arena, which can't be appended
+// to over the nodes after it
+func (b *parseBuffers) nodes(n int) []Node {
+	if b.arena == nil || cap(b.arena)-len(b.arena) < n {
+		// The nodes of the old chunk may be in the tree, so it is left as
+		// it is
+		size := 2 * cap(b.arena)
+		if size < minArenaChunk {
+			size = minArenaChunk
+		}
+		if size < n {
+			size = n
+		}
+		b.arena = make([]Node, 0, size)
+	}
+	start := len(b.arena)
+	b.arena = b.arena[:start+n]
+	return b.arena[start : start+n : start+n]
+}
+
+// reset drops the tree, so its nodes can be reused
+func (b *parseBuffers) reset() {
+	for i := range b.arena {
+		b.arena[i] = Node{}
+	}
+	b.arena = b.arena[:0]
+}
+
+// SetPooledCompilation makes the expressions compiled with the registry
+// reuse the buffers of the tokens and nodes of their syntax tree between
+// compilations when pooled is true, which reduces the pressure on the
+// garbage collector of services that compile many expressions, such as per
+// request. The buffers are only kept while they aren't needed by the
+// garbage collector, so they don't grow the memory of idle services.
+func (p *ParameterTypeRegistry) SetPooledCompilation(pooled bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.pooledCompilation = pooled
+}
+
+// PooledCompilation returns whether the expressions compiled with the
+// registry reuse their buffers
+func (p *ParameterTypeRegistry) PooledCompilation() bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.pooledCompilation
+}
@@ -0,0 +1,54 @@
+package cucumberexpressions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPooledCompilation(t *testing.T) {
+	sources := []string{
+		"",
+		"I have {int} cuke(s)",
+		"I have {int} cucumber(s) in my belly/stomach/tummy",
+		"{int}/{float}",
+		"three (blind) mice/rats (and a/the cat)",
+		`\\(escaped) \\{int} a\\/b`,
+		"(unclosed",
+		"{x}",
+	}
+
+	t.Run("compiles expressions like without pooling", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		pooledRegistry := NewParameterTypeRegistry()
+		pooledRegistry.SetPooledCompilation(true)
+		require.True(t, pooledRegistry.PooledCompilation())
+		require.True(t, pooledRegistry.Clone().PooledCompilation())
+
+		for _, source := range sources {
+			expected, expectedErr := NewCucumberExpression(source, registry)
+			// The second compilation reuses the buffers of the first
+			for i := 0; i < 2; i++ {
+				actual, err := NewCucumberExpression(source, pooledRegistry)
+				if expectedErr != nil {
+					require.EqualError(t, err, expectedErr.Error())
+					continue
+				}
+				require.NoError(t, err)
+				require.Equal(t, expected.Regexp().String(), actual.Regexp().String())
+			}
+		}
+	})
+
+	t.Run("allocates less than without pooling", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		compile := func() {
+			_, err := NewCucumberExpression(sources[2], registry)
+			require.NoError(t, err)
+		}
+		allocs := testing.AllocsPerRun(100, compile)
+		registry.SetPooledCompilation(true)
+		pooledAllocs := testing.AllocsPerRun(100, compile)
+		require.Less(t, pooledAllocs, allocs)
+	})
+}
@@ -0,0 +1,101 @@
+package cucumberexpressions
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+)
+
+// ExpressionSample is a random, valid Cucumber Expression using the built-in
+// parameter types, a text it matches, the values of the arguments matched
+// in that text, and a near-miss text it doesn't match.
+//
+// It implements quick.Generator, so it can be used as an argument of
+// functions passed to quick.Check:
+//
+//	quick.Check(func(sample ExpressionSample) bool { ... }, nil)
+type ExpressionSample struct {
+	Expression string
+	Text       string
+	Values     []interface{}
+	NearMiss   string
+}
+
+func (ExpressionSample) Generate(rand *rand.Rand, size int) reflect.Value {
+	partCount := 1 + rand.Intn(size+1)
+	literalIndex := rand.Intn(partCount)
+
+	var expression, text, nearMiss []string
+	var values []interface{}
+	for i := 0; i < partCount; i++ {
+		kind := rand.Intn(7)
+		if i == literalIndex {
+			// Every sample has at least one literal word to spoil in the near miss
+			kind = 0
+		}
+		switch kind {
+		case 0:
+			word := randomWord(rand)
+			expression = append(expression, word)
+			text = append(text, word)
+			if i == literalIndex {
+				nearMiss = append(nearMiss, "x"+word)
+			} else {
+				nearMiss = append(nearMiss, word)
+			}
+			continue
+		case 1:
+			i := rand.Intn(2000) - 1000
+			expression = append(expression, "{int}")
+			text = append(text, fmt.Sprint(i))
+			values = append(values, i)
+		case 2:
+			whole, fraction := rand.Intn(100), 1+rand.Intn(99)
+			f := fmt.Sprintf("%d.%d", whole, fraction)
+			var value float64
+			fmt.Sscan(f, &value)
+			expression = append(expression, "{float}")
+			text = append(text, f)
+			values = append(values, value)
+		case 3:
+			word := randomWord(rand)
+			expression = append(expression, "{word}")
+			text = append(text, word)
+			values = append(values, word)
+		case 4:
+			content := randomWord(rand) + " " + randomWord(rand)
+			expression = append(expression, "{string}")
+			text = append(text, `"`+content+`"`)
+			values = append(values, content)
+		case 5:
+			word := randomWord(rand)
+			expression = append(expression, word+"(s)")
+			if rand.Intn(2) == 0 {
+				word += "s"
+			}
+			text = append(text, word)
+		case 6:
+			alternatives := []string{randomWord(rand), randomWord(rand), randomWord(rand)}
+			expression = append(expression, strings.Join(alternatives, "/"))
+			text = append(text, alternatives[rand.Intn(len(alternatives))])
+		}
+		nearMiss = append(nearMiss, text[len(text)-1])
+	}
+
+	return reflect.ValueOf(ExpressionSample{
+		Expression: strings.Join(expression, " "),
+		Text:       strings.Join(text, " "),
+		Values:     values,
+		NearMiss:   strings.Join(nearMiss, " "),
+	})
+}
+
+func randomWord(rand *rand.Rand) string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	word := make([]byte, 1+rand.Intn(8))
+	for i := range word {
+		word[i] = letters[rand.Intn(len(letters))]
+	}
+	return string(word)
+}
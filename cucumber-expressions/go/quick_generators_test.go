@@ -0,0 +1,42 @@
+package cucumberexpressions
+
+import (
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressionSample(t *testing.T) {
+	t.Run("matches the generated text", func(t *testing.T) {
+		err := quick.Check(func(sample ExpressionSample) bool {
+			expression, err := NewCucumberExpression(sample.Expression, NewParameterTypeRegistry())
+			require.NoError(t, err)
+			args, err := expression.Match(sample.Text)
+			require.NoError(t, err)
+			require.NotNil(t, args, "%s should match %s", sample.Expression, sample.Text)
+
+			values := make([]interface{}, len(args))
+			for i, arg := range args {
+				values[i] = arg.GetValue()
+			}
+			if len(sample.Values) == 0 {
+				return len(values) == 0
+			}
+			require.Equal(t, sample.Values, values)
+			return true
+		}, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("does not match the near miss", func(t *testing.T) {
+		err := quick.Check(func(sample ExpressionSample) bool {
+			expression, err := NewCucumberExpression(sample.Expression, NewParameterTypeRegistry())
+			require.NoError(t, err)
+			args, err := expression.Match(sample.NearMiss)
+			require.NoError(t, err)
+			return args == nil
+		}, nil)
+		require.NoError(t, err)
+	})
+}
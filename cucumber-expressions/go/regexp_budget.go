@@ -0,0 +1,91 @@
+package cucumberexpressions
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+)
+
+// RegexpBudget limits the complexity of regular expressions from untrusted
+// sources, such as parameter types defined in configuration files.
+//
+// Go regular expressions are RE2 expressions: they have no backreferences
+// or lookarounds, and match in time linear to the length of the text. The
+// time also grows with the size of the compiled expression though, which
+// nested repetitions like (a{1000}){1000} make huge. Zero means unlimited.
+type RegexpBudget struct {
+	// MaxLength is the maximum length of the source
+	MaxLength int
+	// MaxInstructions is the maximum size of the compiled expression
+	MaxInstructions int
+	// MaxCaptureGroups is the maximum number of capture groups
+	MaxCaptureGroups int
+}
+
+var DefaultRegexpBudget = RegexpBudget{MaxLength: 256, MaxInstructions: 2000, MaxCaptureGroups: 10}
+
+// Check returns an error if source isn't a valid regular expression, or
+// exceeds the budget
+func (b RegexpBudget) Check(source string) error {
+	if b.MaxLength > 0 && len(source) > b.MaxLength {
+		return fmt.Errorf("regexp /%s/ is longer than %d characters", source, b.MaxLength)
+	}
+	re, err := syntax.Parse(source, syntax.Perl)
+	if err != nil {
+		return err
+	}
+	if b.MaxCaptureGroups > 0 && re.MaxCap() > b.MaxCaptureGroups {
+		return fmt.Errorf("regexp /%s/ has %d capture groups, more than %d", source, re.MaxCap(), b.MaxCaptureGroups)
+	}
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		return err
+	}
+	if b.MaxInstructions > 0 && len(prog.Inst) > b.MaxInstructions {
+		return fmt.Errorf("regexp /%s/ compiles to %d instructions, more than %d", source, len(prog.Inst), b.MaxInstructions)
+	}
+	return nil
+}
+
+// Compile compiles source if it is within the budget
+func (b RegexpBudget) Compile(source string) (*regexp.Regexp, error) {
+	if err := b.Check(source); err != nil {
+		return nil, err
+	}
+	return regexp.Compile(source)
+}
+
+// SetRegexpBudget sets the budget of the regexps of parameter types defined
+// in JSON, with UnmarshalJSON. The budget is DefaultRegexpBudget until it is
+// set, and the zero RegexpBudget is unlimited.
+func (p *ParameterTypeRegistry) SetRegexpBudget(budget RegexpBudget) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.regexpBudget = &budget
+}
+
+// RegexpBudget returns the budget of the regexps of parameter types defined
+// in JSON
+func (p *ParameterTypeRegistry) RegexpBudget() RegexpBudget {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.budget()
+}
+
+// budget returns the regexp budget, which must be locked
+func (p *ParameterTypeRegistry) budget() RegexpBudget {
+	if p.regexpBudget == nil {
+		return DefaultRegexpBudget
+	}
+	return *p.regexpBudget
+}
+
+// CheckParameterType checks all regexps of a parameter type
+func (b RegexpBudget) CheckParameterType(parameterType *ParameterType) error {
+	for _, r := range parameterType.Regexps() {
+		if err := b.Check(r.String()); err != nil {
+			return fmt.Errorf("parameter type {%s}: %s", parameterType.Name(), err)
+		}
+	}
+	return nil
+}
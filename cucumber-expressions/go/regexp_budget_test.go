@@ -0,0 +1,45 @@
+package cucumberexpressions
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegexpBudget(t *testing.T) {
+	t.Run("accepts the built-in parameter types", func(t *testing.T) {
+		for _, parameterType := range NewParameterTypeRegistry().ParameterTypes() {
+			require.NoError(t, DefaultRegexpBudget.CheckParameterType(parameterType))
+		}
+	})
+
+	t.Run("rejects long regexps", func(t *testing.T) {
+		err := RegexpBudget{MaxLength: 5}.Check("abcdef")
+		require.EqualError(t, err, "regexp /abcdef/ is longer than 5 characters")
+	})
+
+	t.Run("rejects regexps with many capture groups", func(t *testing.T) {
+		err := RegexpBudget{MaxCaptureGroups: 2}.Check("(a)(b)(c)")
+		require.EqualError(t, err, "regexp /(a)(b)(c)/ has 3 capture groups, more than 2")
+	})
+
+	t.Run("rejects nested repetitions", func(t *testing.T) {
+		err := DefaultRegexpBudget.Check("((ab|cd|ef){30}){30}")
+		require.Error(t, err)
+		require.True(t, strings.HasPrefix(err.Error(), "regexp /((ab|cd|ef){30}){30}/ compiles to "), err.Error())
+	})
+
+	t.Run("reports invalid regexps", func(t *testing.T) {
+		_, err := DefaultRegexpBudget.Compile("(?=a)")
+		require.EqualError(t, err, "error parsing regexp: invalid or unsupported Perl syntax: `(?=`")
+	})
+
+	t.Run("names the parameter type", func(t *testing.T) {
+		parameterType, err := NewParameterType("color", []*regexp.Regexp{regexp.MustCompile("red|blue|yellow")}, "color", nil, false, false, false)
+		require.NoError(t, err)
+		err = RegexpBudget{MaxLength: 8}.CheckParameterType(parameterType)
+		require.EqualError(t, err, "parameter type {color}: regexp /red|blue|yellow/ is longer than 8 characters")
+	})
+}
@@ -56,7 +56,27 @@ func (r *RegularExpression) Match(text string, typeHints ...reflect.Type) ([]*Ar
 		}
 		parameterTypes = append(parameterTypes, parameterType)
 	}
-	return BuildArguments(r.treeRegexp, text, parameterTypes), nil
+	arguments := BuildArguments(r.treeRegexp, text, parameterTypes)
+	if err := resolveTemplates(r.parameterTypeRegistry, text, arguments); err != nil {
+		return nil, err
+	}
+	return arguments, nil
+}
+
+// ParameterNames returns a name for each capture group, suitable for the
+// parameters of a step definition snippet. Named groups keep their name.
+func (r *RegularExpression) ParameterNames() []string {
+	usageByName := map[string]int{}
+	groupBuilders := r.treeRegexp.GroupBuilder().Children()
+	result := make([]string, len(groupBuilders))
+	for i, groupBuilder := range groupBuilders {
+		name := groupBuilder.Name()
+		if name == "" {
+			name = "arg"
+		}
+		result[i] = getParameterName(name, usageByName)
+	}
+	return result
 }
 
 func (r *RegularExpression) Regexp() *regexp.Regexp {
@@ -22,6 +22,33 @@ func TestRegularExpression(t *testing.T) {
 		/// [capture-match-arguments]
 	})
 
+	t.Run("propagates named groups to argument names", func(t *testing.T) {
+		expr := regexp.MustCompile(`I have (?P<count>\d+) cukes? in my (\w+)`)
+		expression := NewRegularExpression(expr, NewParameterTypeRegistry())
+		args, err := expression.Match("I have 7 cukes in my belly")
+		require.NoError(t, err)
+		require.Equal(t, "count", args[0].Name())
+		require.Equal(t, "", args[1].Name())
+	})
+
+	t.Run("uses the parameter type of the regexp inside named groups", func(t *testing.T) {
+		require.Equal(t, Match(t, `(?P<count>-?\d+)`, "22")[0], 22)
+	})
+
+	t.Run("generates parameter names from named groups", func(t *testing.T) {
+		expr := regexp.MustCompile(`(?P<user>\w+) sends (\d+) to (?P<user>\w+) and (\w+)`)
+		expression := NewRegularExpression(expr, NewParameterTypeRegistry()).(*RegularExpression)
+		require.Equal(t, []string{"user", "arg", "user2", "arg2"}, expression.ParameterNames())
+	})
+
+	t.Run("names repeated named groups of arguments like parameters", func(t *testing.T) {
+		expr := regexp.MustCompile(`(?P<user>\w+) pays (?P<user>\w+)`)
+		expression := NewRegularExpression(expr, NewParameterTypeRegistry()).(*RegularExpression)
+		args, err := expression.Match("alice pays bob")
+		require.NoError(t, err)
+		require.Equal(t, expression.ParameterNames(), argumentNames(args))
+	})
+
 	t.Run("does no transform by default", func(t *testing.T) {
 		require.Equal(t, Match(t, `(\d\d)`, "22")[0], "22")
 	})
@@ -30,6 +57,10 @@ func TestRegularExpression(t *testing.T) {
 		require.Equal(t, Match(t, `(\d\d)`, "22", reflect.TypeOf(int(0)))[0], 22)
 	})
 
+	t.Run("uses encoding.TextUnmarshaler of type hint for transform", func(t *testing.T) {
+		require.Equal(t, Match(t, `version (\S+)`, "version 1.2.3", reflect.TypeOf(semVer{}))[0], semVer{1, 2, 3})
+	})
+
 	t.Run("uses type hint for anonymous parameter type", func(t *testing.T) {
 		require.Equal(t, Match(t, `(.*)`, "22", reflect.TypeOf(int(0)))[0], 22)
 	})
@@ -100,6 +131,10 @@ func TestRegularExpression(t *testing.T) {
 		require.Equal(t, Match(t, `(-?\d+)`, "22", reflect.TypeOf(""))[0], "22")
 	})
 
+	t.Run("transforms float", func(t *testing.T) {
+		require.Equal(t, Match(t, `([-+]?\d*\.?\d+)`, "1.5")[0], 1.5)
+	})
+
 	t.Run("returns nil when there is no match", func(t *testing.T) {
 		require.Nil(t, Match(t, "hello", "world"))
 	})
@@ -0,0 +1,511 @@
+package cucumberexpressions
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// Router dispatches text, such as the commands of a chat bot or a CLI, to
+// the handler of the expression that matches it. Handlers are functions
+// with a parameter per parameter of their expression, optionally preceded
+// by a context.Context, and they return nothing or an error:
+//
+//	router.Add("deploy {word} to {int} servers", func(ctx context.Context, app string, servers int) error {
+//		...
+//	})
+//
+// The arguments are converted to the types of the parameters of the
+// handler, so the anonymous parameter type {} works too, and so does an
+// int64 for {int}. Add returns an error when a parameter type's values
+// can't be converted to the type of their handler parameter.
+//
+// The JSON of {json} is decoded to the type of its handler parameter, such
+// as a struct with json tags.
+//
+// Instead of a parameter per argument, a handler can take a struct with a
+// field per argument, tagged with the name of the argument:
+//
+//	type deployArgs struct {
+//		App     string `cucumber:"word"`
+//		Servers int    `cucumber:"int"`
+//	}
+//
+//	router.Add("deploy {word} to {int} servers", func(ctx context.Context, args deployArgs) error {
+//		...
+//	})
+//
+// Arguments are named after their capture group or parameter type, with 2,
+// 3 and so on appended when a name is used more than once, such as "int2"
+// for the second {int} of an expression, as in snippets. Fields of {} are named "anonymous".
+type Router struct {
+	mutex                 sync.RWMutex
+	parameterTypeRegistry *ParameterTypeRegistry
+	ambiguityPolicy       AmbiguityPolicy
+	routes                []*route
+	middleware            []Middleware
+	// recoverPanics makes Dispatch return the panics of handlers and
+	// middleware as a PanicError
+	recoverPanics bool
+}
+
+// AmbiguityPolicy decides which route handles text that the expressions of
+// several routes match
+type AmbiguityPolicy int
+
+const (
+	// FirstRoute dispatches to the route that was added first
+	FirstRoute AmbiguityPolicy = iota
+	// MostSpecificRoute dispatches to the route with the least text in its
+	// arguments, and the one added first of those
+	MostSpecificRoute
+	// RejectAmbiguousRoutes returns an AmbiguousRouteError
+	RejectAmbiguousRoutes
+)
+
+// RouteMatch is text a route matched, with its arguments
+type RouteMatch struct {
+	Text       string
+	Expression Expression
+	Arguments  []*Argument
+}
+
+// RouteHandler handles a route match
+type RouteHandler func(ctx context.Context, match *RouteMatch) error
+
+// Middleware wraps the handlers of routes, for logging, authorization or
+// recovering from panics
+type Middleware func(next RouteHandler) RouteHandler
+
+type route struct {
+	expression Expression
+	handler    reflect.Value
+	typeHints  []reflect.Type
+	// withContext is true when the handler takes a context.Context first
+	withContext bool
+	// arguments is the struct the handler takes the arguments in, if any
+	arguments reflect.Type
+}
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// NewRouter returns a router that recovers the panics of handlers, see
+// SetRecoverPanics.
+func NewRouter(parameterTypeRegistry *ParameterTypeRegistry, ambiguityPolicy AmbiguityPolicy) *Router {
+	return &Router{parameterTypeRegistry: parameterTypeRegistry, ambiguityPolicy: ambiguityPolicy, recoverPanics: true}
+}
+
+// SetRecoverPanics makes Dispatch return a PanicError when the handler of
+// a route, the transform of one of its arguments or middleware panics,
+// rather than crashing the program, when enabled is true, which it is by
+// default. Set it to false to debug a panic where it happens.
+func (r *Router) SetRecoverPanics(enabled bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.recoverPanics = enabled
+}
+
+// RecoversPanics returns whether Dispatch recovers panics
+func (r *Router) RecoversPanics() bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.recoverPanics
+}
+
+// Add adds a route from a Cucumber Expression to a handler.
+func (r *Router) Add(expression string, handler interface{}) error {
+	compiled, err := NewCucumberExpression(expression, r.parameterTypeRegistry)
+	if err != nil {
+		return err
+	}
+	return r.AddExpression(compiled, handler)
+}
+
+// AddExpression adds a route from an expression, such as a
+// RegularExpression, to a handler.
+func (r *Router) AddExpression(expression Expression, handler interface{}) error {
+	route, err := newRoute(expression, handler)
+	if err != nil {
+		return err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.routes = append(r.routes, route)
+	return nil
+}
+
+// Use adds middleware around the handlers of all routes. The middleware
+// added first is the outermost one.
+func (r *Router) Use(middleware ...Middleware) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.middleware = append(r.middleware, middleware...)
+}
+
+// Dispatch calls the handler of the route that matches text, and returns
+// its error. It returns a NoRouteError when no route matches.
+func (r *Router) Dispatch(ctx context.Context, text string) error {
+	r.mutex.RLock()
+	routes := r.routes
+	middleware := r.middleware
+	recoverPanics := r.recoverPanics
+	r.mutex.RUnlock()
+
+	route, match, err := r.route(routes, text)
+	if err != nil {
+		return err
+	}
+	handler := route.handle
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	if recoverPanics {
+		handler = RecoverPanics(handler)
+	}
+	return handler(ctx, match)
+}
+
+func (r *Router) route(routes []*route, text string) (*route, *RouteMatch, error) {
+	var matchedRoutes []*route
+	var matches []*RouteMatch
+	for _, route := range routes {
+		args, err := route.expression.Match(text, route.typeHints...)
+		if err != nil {
+			return nil, nil, err
+		}
+		if args == nil {
+			continue
+		}
+		matchedRoutes = append(matchedRoutes, route)
+		matches = append(matches, &RouteMatch{Text: text, Expression: route.expression, Arguments: args})
+		if r.ambiguityPolicy == FirstRoute {
+			break
+		}
+	}
+	if len(matches) == 0 {
+		return nil, nil, &NoRouteError{Text: text}
+	}
+
+	best := 0
+	switch r.ambiguityPolicy {
+	case MostSpecificRoute:
+		for i, match := range matches {
+			if argumentLength(match.Arguments) < argumentLength(matches[best].Arguments) {
+				best = i
+			}
+		}
+	case RejectAmbiguousRoutes:
+		if len(matches) > 1 {
+			sources := make([]string, len(matches))
+			for i, match := range matches {
+				sources[i] = match.Expression.Source()
+			}
+			return nil, nil, &AmbiguousRouteError{Text: text, Expressions: sources}
+		}
+	}
+	return matchedRoutes[best], matches[best], nil
+}
+
+// RecoverPanics is middleware that returns a PanicError when the handler
+// of a route, or the transform of one of its arguments, panics, rather
+// than crashing the program. Routers recover panics around all of their
+// middleware unless SetRecoverPanics turns it off, so it is only needed to
+// recover inside of other middleware.
+func RecoverPanics(next RouteHandler) RouteHandler {
+	return func(ctx context.Context, match *RouteMatch) (err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = &PanicError{Expression: match.Expression.Source(), Value: recovered, Stack: debug.Stack()}
+			}
+		}()
+		return next(ctx, match)
+	}
+}
+
+// argumentLength is the number of bytes of text matched by arguments
+func argumentLength(arguments []*Argument) int {
+	length := 0
+	for _, argument := range arguments {
+		if argument.Group().Value() != nil {
+			length += argument.Group().End() - argument.Group().Start()
+		}
+	}
+	return length
+}
+
+func newRoute(expression Expression, handler interface{}) (*route, error) {
+	handlerValue := reflect.ValueOf(handler)
+	if handlerValue.Kind() != reflect.Func {
+		return nil, newMessageError(InvalidHandlerMessage, expression.Source(), fmt.Sprintf("%T", handler))
+	}
+	handlerType := handlerValue.Type()
+	if handlerType.IsVariadic() || handlerType.NumOut() > 1 || (handlerType.NumOut() == 1 && handlerType.Out(0) != errorType) {
+		return nil, newMessageError(InvalidHandlerMessage, expression.Source(), handlerType.String())
+	}
+
+	result := &route{expression: expression, handler: handlerValue}
+	first := 0
+	if handlerType.NumIn() > 0 && handlerType.In(0) == contextType {
+		result.withContext = true
+		first = 1
+	}
+	if handlerType.NumIn() == first+1 && isArgumentStruct(handlerType.In(first)) {
+		result.arguments = handlerType.In(first)
+		return result, checkArgumentStruct(expression, result.arguments)
+	}
+	for i := first; i < handlerType.NumIn(); i++ {
+		result.typeHints = append(result.typeHints, handlerType.In(i))
+	}
+	cucumberExpression, ok := expression.(*CucumberExpression)
+	if !ok {
+		return result, nil
+	}
+	if len(cucumberExpression.parameterTypes) != len(result.typeHints) {
+		return nil, newMessageError(HandlerArgumentCountMessage, expression.Source(), len(result.typeHints), len(cucumberExpression.parameterTypes))
+	}
+	for i, parameterType := range cucumberExpression.parameterTypes {
+		if err := checkValueType(expression, parameterType, result.typeHints[i]); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// handle calls the handler with the arguments of the match, converted to
+// the types of its parameters
+func (r *route) handle(ctx context.Context, match *RouteMatch) error {
+	if r.arguments != nil {
+		return r.handleStruct(ctx, match)
+	}
+	if len(match.Arguments) != len(r.typeHints) {
+		return newMessageError(HandlerArgumentCountMessage, r.expression.Source(), len(r.typeHints), len(match.Arguments))
+	}
+	var in []reflect.Value
+	if r.withContext {
+		in = append(in, reflect.ValueOf(&ctx).Elem())
+	}
+	for i, argument := range match.Arguments {
+		value, err := argumentValueOf(argument, r.typeHints[i])
+		if err != nil {
+			return err
+		}
+		in = append(in, value)
+	}
+
+	return r.call(in)
+}
+
+// handleStruct calls the handler with a struct with the arguments of the
+// match in its tagged fields
+func (r *route) handleStruct(ctx context.Context, match *RouteMatch) error {
+	arguments := map[string]*Argument{}
+	for i, name := range argumentNames(match.Arguments) {
+		arguments[name] = match.Arguments[i]
+	}
+	value := reflect.New(r.arguments).Elem()
+	for i := 0; i < r.arguments.NumField(); i++ {
+		field := r.arguments.Field(i)
+		name, ok := field.Tag.Lookup(argumentTag)
+		if !ok {
+			continue
+		}
+		argument, ok := arguments[name]
+		if !ok {
+			return newMessageError(UnknownArgumentFieldMessage, r.expression.Source(), field.Name, name)
+		}
+		fieldValue, err := argumentValueOf(argument, field.Type)
+		if err != nil {
+			return err
+		}
+		value.Field(i).Set(fieldValue)
+	}
+
+	var in []reflect.Value
+	if r.withContext {
+		in = append(in, reflect.ValueOf(&ctx).Elem())
+	}
+	return r.call(append(in, value))
+}
+
+func (r *route) call(in []reflect.Value) error {
+	out := r.handler.Call(in)
+	if len(out) == 1 && !out[0].IsNil() {
+		return out[0].Interface().(error)
+	}
+	return nil
+}
+
+// argumentTag is the tag of the fields of the struct a handler takes its
+// arguments in
+const argumentTag = "cucumber"
+
+// isArgumentStruct tells if a parameter of a handler is a struct with a
+// field per argument, rather than the value of an argument
+func isArgumentStruct(parameterType reflect.Type) bool {
+	if parameterType.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < parameterType.NumField(); i++ {
+		if _, ok := parameterType.Field(i).Tag.Lookup(argumentTag); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkArgumentStruct checks that the fields of an argument struct can be
+// set, and that the arguments they are for are in the expression and have
+// values the fields can hold
+func checkArgumentStruct(expression Expression, arguments reflect.Type) error {
+	var names map[string]*ParameterType
+	if cucumberExpression, ok := expression.(*CucumberExpression); ok {
+		var parameterNames []string
+		for i, parameterType := range cucumberExpression.parameterTypes {
+			if name := cucumberExpression.parameterNames[i]; name != "" {
+				parameterNames = append(parameterNames, name)
+			} else if parameterType.isAnonymous() {
+				parameterNames = append(parameterNames, "anonymous")
+			} else {
+				parameterNames = append(parameterNames, parameterType.Name())
+			}
+		}
+		names = map[string]*ParameterType{}
+		for i, name := range numberNames(parameterNames) {
+			names[name] = cucumberExpression.parameterTypes[i]
+		}
+	}
+	for i := 0; i < arguments.NumField(); i++ {
+		field := arguments.Field(i)
+		name, ok := field.Tag.Lookup(argumentTag)
+		if !ok {
+			continue
+		}
+		if field.PkgPath != "" {
+			return newMessageError(UnexportedArgumentFieldMessage, expression.Source(), field.Name)
+		}
+		if names == nil {
+			continue
+		}
+		parameterType, ok := names[name]
+		if !ok {
+			return newMessageError(UnknownArgumentFieldMessage, expression.Source(), field.Name, name)
+		}
+		if err := checkValueType(expression, parameterType, field.Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkValueType checks that the values of a parameter type can be
+// converted to a valueType. Parameter types whose transform returns an
+// interface{} are checked when their arguments are.
+func checkValueType(expression Expression, parameterType *ParameterType, valueType reflect.Type) error {
+	if parameterType.isAnonymous() || parameterType.valueType == nil || canConvert(parameterType.valueType, valueType) {
+		return nil
+	}
+	return newMessageError(HandlerParameterTypeMessage, expression.Source(), valueType.String(), parameterType.Name(), parameterType.valueType.String())
+}
+
+// canConvert tells if values of a type can be assigned or converted to
+// another type. Only numbers are converted to other kinds, so an int isn't
+// converted to the string of the rune it is the code point of.
+func canConvert(from reflect.Type, to reflect.Type) bool {
+	if from.AssignableTo(to) {
+		return true
+	}
+	return from.ConvertibleTo(to) && (from.Kind() == to.Kind() || isNumber(from.Kind()) && isNumber(to.Kind()))
+}
+
+func isNumber(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+// argumentValueOf returns the value of an argument as a valueType
+func argumentValueOf(argument *Argument, valueType reflect.Type) (reflect.Value, error) {
+	if argument.ParameterType().isDeAnonymized() {
+		return anonymousValue(argument, valueType)
+	}
+	if argument.ParameterType().decodesJSON {
+		return jsonValue(argument, valueType)
+	}
+	untyped, err := transformedValue(argument)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if untyped == nil {
+		return reflect.Zero(valueType), nil
+	}
+	value := reflect.ValueOf(untyped)
+	if value.Type().AssignableTo(valueType) {
+		return value, nil
+	}
+	if !canConvert(value.Type(), valueType) {
+		return value, newMessageError(ArgumentTypeMismatchMessage, argument.ParameterType().Name(), value.Type().String(), valueType.String())
+	}
+	converted := value.Convert(valueType)
+	// Integers that don't fit would wrap around, and fractions would be cut
+	if isInteger(valueType.Kind()) && converted.Convert(value.Type()).Interface() != value.Interface() {
+		return value, newMessageError(ArgumentOutOfRangeMessage, untyped, argument.ParameterType().Name(), valueType.String())
+	}
+	return converted, nil
+}
+
+func isInteger(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Uintptr
+}
+
+// NoRouteError is returned by Dispatch when no route matches the text
+type NoRouteError struct {
+	Text string
+}
+
+func (e *NoRouteError) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e *NoRouteError) localize(messages Messages) string {
+	return messages.format(NoRouteMessage, e.Text)
+}
+
+// AmbiguousRouteError is returned by Dispatch with RejectAmbiguousRoutes
+// when several routes match the text
+type AmbiguousRouteError struct {
+	Text        string
+	Expressions []string
+}
+
+func (e *AmbiguousRouteError) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e *AmbiguousRouteError) localize(messages Messages) string {
+	return messages.format(AmbiguousRouteMessage, e.Text, strings.Join(e.Expressions, "\n   "))
+}
+
+// PanicError is returned by Dispatch and the RecoverPanics middleware when
+// a handler panics
+type PanicError struct {
+	Expression string
+	// Value is the value the handler panicked with
+	Value interface{}
+	// Stack is the stack trace of the panic
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e *PanicError) localize(messages Messages) string {
+	return messages.format(HandlerPanicMessage, e.Expression, e.Value)
+}
+
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
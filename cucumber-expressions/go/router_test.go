@@ -0,0 +1,303 @@
+package cucumberexpressions
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter(t *testing.T) {
+	t.Run("dispatches to the handler with typed arguments", func(t *testing.T) {
+		router := NewRouter(NewParameterTypeRegistry(), FirstRoute)
+		var app string
+		var servers int
+		require.NoError(t, router.Add("deploy {word} to {int} servers", func(a string, s int) {
+			app, servers = a, s
+		}))
+
+		require.NoError(t, router.Dispatch(context.Background(), "deploy api to 3 servers"))
+		require.Equal(t, "api", app)
+		require.Equal(t, 3, servers)
+	})
+
+	t.Run("passes the context and returns the error of the handler", func(t *testing.T) {
+		router := NewRouter(NewParameterTypeRegistry(), FirstRoute)
+		type key struct{}
+		require.NoError(t, router.Add("fail", func(ctx context.Context) error {
+			return errors.New(ctx.Value(key{}).(string))
+		}))
+
+		err := router.Dispatch(context.WithValue(context.Background(), key{}, "failed"), "fail")
+		require.EqualError(t, err, "failed")
+	})
+
+	t.Run("converts anonymous arguments to the types of the handler", func(t *testing.T) {
+		router := NewRouter(NewParameterTypeRegistry(), FirstRoute)
+		var seconds float64
+		require.NoError(t, router.Add("wait {} seconds", func(s float64) {
+			seconds = s
+		}))
+
+		require.NoError(t, router.Dispatch(context.Background(), "wait 1.5 seconds"))
+		require.Equal(t, 1.5, seconds)
+
+		err := router.Dispatch(context.Background(), "wait long seconds")
+		require.Error(t, err)
+	})
+
+	t.Run("routes regular expressions", func(t *testing.T) {
+		router := NewRouter(NewParameterTypeRegistry(), FirstRoute)
+		var name string
+		require.NoError(t, router.AddExpression(NewRegularExpression(regexp.MustCompile(`^hello (\w+)$`), NewParameterTypeRegistry()), func(n string) {
+			name = n
+		}))
+
+		require.NoError(t, router.Dispatch(context.Background(), "hello world"))
+		require.Equal(t, "world", name)
+	})
+
+	t.Run("returns an error when no route matches", func(t *testing.T) {
+		router := NewRouter(NewParameterTypeRegistry(), FirstRoute)
+		require.NoError(t, router.Add("status", func() {}))
+
+		err := router.Dispatch(context.Background(), "restart")
+		var noRouteError *NoRouteError
+		require.True(t, errors.As(err, &noRouteError))
+		require.EqualError(t, err, "No route matches 'restart'")
+	})
+
+	ambiguousRouter := func(t *testing.T, policy AmbiguityPolicy, dispatched *string) *Router {
+		router := NewRouter(NewParameterTypeRegistry(), policy)
+		require.NoError(t, router.Add("restart {}", func(string) { *dispatched = "restart {}" }))
+		require.NoError(t, router.Add("restart {word} now", func(string) { *dispatched = "restart {word} now" }))
+		return router
+	}
+
+	t.Run("dispatches to the first route", func(t *testing.T) {
+		var dispatched string
+		router := ambiguousRouter(t, FirstRoute, &dispatched)
+		require.NoError(t, router.Dispatch(context.Background(), "restart api now"))
+		require.Equal(t, "restart {}", dispatched)
+	})
+
+	t.Run("dispatches to the most specific route", func(t *testing.T) {
+		var dispatched string
+		router := ambiguousRouter(t, MostSpecificRoute, &dispatched)
+		require.NoError(t, router.Dispatch(context.Background(), "restart api now"))
+		require.Equal(t, "restart {word} now", dispatched)
+		require.NoError(t, router.Dispatch(context.Background(), "restart api"))
+		require.Equal(t, "restart {}", dispatched)
+	})
+
+	t.Run("rejects ambiguous routes", func(t *testing.T) {
+		var dispatched string
+		router := ambiguousRouter(t, RejectAmbiguousRoutes, &dispatched)
+		err := router.Dispatch(context.Background(), "restart api now")
+		var ambiguousRouteError *AmbiguousRouteError
+		require.True(t, errors.As(err, &ambiguousRouteError))
+		require.Equal(t, []string{"restart {}", "restart {word} now"}, ambiguousRouteError.Expressions)
+		require.Equal(t, "", dispatched)
+
+		require.NoError(t, router.Dispatch(context.Background(), "restart api"))
+		require.Equal(t, "restart {}", dispatched)
+	})
+
+	t.Run("wraps handlers in middleware", func(t *testing.T) {
+		router := NewRouter(NewParameterTypeRegistry(), FirstRoute)
+		var calls []string
+		trace := func(name string) Middleware {
+			return func(next RouteHandler) RouteHandler {
+				return func(ctx context.Context, match *RouteMatch) error {
+					calls = append(calls, name+" "+match.Expression.Source())
+					return next(ctx, match)
+				}
+			}
+		}
+		router.Use(trace("outer"), trace("inner"))
+		require.NoError(t, router.Add("status", func() { calls = append(calls, "handler") }))
+
+		require.NoError(t, router.Dispatch(context.Background(), "status"))
+		require.Equal(t, []string{"outer status", "inner status", "handler"}, calls)
+	})
+
+	t.Run("recovers panics of handlers and transforms", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		require.NoError(t, DefineTypedParameterType(registry, "color", "red|blue", func(args ...string) (color, error) {
+			panic("out of paint")
+		}))
+		router := NewRouter(registry, FirstRoute)
+		require.True(t, router.RecoversPanics())
+		failure := errors.New("failed")
+		require.NoError(t, router.Add("fail", func() { panic(failure) }))
+		require.NoError(t, router.Add("paint it {color}", func(color) {}))
+
+		err := router.Dispatch(context.Background(), "fail")
+		var panicError *PanicError
+		require.True(t, errors.As(err, &panicError))
+		require.Equal(t, failure, panicError.Value)
+		require.Contains(t, string(panicError.Stack), "router_test.go")
+		require.True(t, errors.Is(err, failure))
+		require.EqualError(t, err, "The handler of fail panicked: failed")
+
+		err = router.Dispatch(context.Background(), "paint it red")
+		require.EqualError(t, err, "The handler of paint it {color} panicked: out of paint")
+	})
+
+	t.Run("recovers panics of middleware", func(t *testing.T) {
+		router := NewRouter(NewParameterTypeRegistry(), FirstRoute)
+		require.NoError(t, router.Add("fail", func() {}))
+		router.Use(func(next RouteHandler) RouteHandler {
+			return func(ctx context.Context, match *RouteMatch) error {
+				panic("unauthorized")
+			}
+		})
+		require.EqualError(t, router.Dispatch(context.Background(), "fail"), "The handler of fail panicked: unauthorized")
+	})
+
+	t.Run("recovers panics inside of middleware with the middleware", func(t *testing.T) {
+		router := NewRouter(NewParameterTypeRegistry(), FirstRoute)
+		router.SetRecoverPanics(false)
+		require.NoError(t, router.Add("fail", func() { panic("failed") }))
+		var recovered error
+		router.Use(func(next RouteHandler) RouteHandler {
+			return func(ctx context.Context, match *RouteMatch) error {
+				recovered = next(ctx, match)
+				return nil
+			}
+		}, RecoverPanics)
+		require.NoError(t, router.Dispatch(context.Background(), "fail"))
+		require.EqualError(t, recovered, "The handler of fail panicked: failed")
+	})
+
+	t.Run("does not recover panics when recovering is off", func(t *testing.T) {
+		router := NewRouter(NewParameterTypeRegistry(), FirstRoute)
+		router.SetRecoverPanics(false)
+		require.False(t, router.RecoversPanics())
+		require.NoError(t, router.Add("fail", func() { panic("failed") }))
+		require.PanicsWithValue(t, "failed", func() { _ = router.Dispatch(context.Background(), "fail") })
+	})
+
+	t.Run("binds arguments to the tagged fields of a struct", func(t *testing.T) {
+		type moveArgs struct {
+			From    int     `cucumber:"int"`
+			To      int     `cucumber:"int2"`
+			Seconds float64 `cucumber:"anonymous"`
+			Note    string
+		}
+		registry := NewParameterTypeRegistry()
+		require.NoError(t, registry.SetSyntaxEnabled(NamedParameterSyntax, true))
+		router := NewRouter(registry, FirstRoute)
+		var args moveArgs
+		require.NoError(t, router.Add("move from {int} to {int} in {} seconds", func(ctx context.Context, a moveArgs) error {
+			args = a
+			return nil
+		}))
+
+		require.NoError(t, router.Dispatch(context.Background(), "move from 3 to 7 in 1.5 seconds"))
+		require.Equal(t, moveArgs{From: 3, To: 7, Seconds: 1.5}, args)
+	})
+
+	t.Run("binds named capture groups of regular expressions", func(t *testing.T) {
+		type greeting struct {
+			Name string `cucumber:"name"`
+		}
+		router := NewRouter(NewParameterTypeRegistry(), FirstRoute)
+		var args greeting
+		require.NoError(t, router.AddExpression(NewRegularExpression(regexp.MustCompile(`^hello (?P<name>\w+)$`), NewParameterTypeRegistry()), func(a greeting) {
+			args = a
+		}))
+
+		require.NoError(t, router.Dispatch(context.Background(), "hello world"))
+		require.Equal(t, greeting{Name: "world"}, args)
+	})
+
+	t.Run("binds named parameters of cucumber expressions", func(t *testing.T) {
+		type moveArgs struct {
+			From int `cucumber:"from"`
+			To   int `cucumber:"to"`
+		}
+		registry := NewParameterTypeRegistry()
+		require.NoError(t, registry.SetSyntaxEnabled(NamedParameterSyntax, true))
+		router := NewRouter(registry, FirstRoute)
+		var args moveArgs
+		require.NoError(t, router.Add("move from {from:int} to {to:int}", func(a moveArgs) {
+			args = a
+		}))
+
+		require.NoError(t, router.Dispatch(context.Background(), "move from 3 to 7"))
+		require.Equal(t, moveArgs{From: 3, To: 7}, args)
+	})
+
+	t.Run("does not bind fields of arguments the expression doesn't have", func(t *testing.T) {
+		router := NewRouter(NewParameterTypeRegistry(), FirstRoute)
+		err := router.Add("deploy {word}", func(struct {
+			App     string `cucumber:"word"`
+			Servers int    `cucumber:"int"`
+		}) {
+		})
+		require.EqualError(t, err, "The handler of deploy {word} has a field Servers for the argument int, which the expression doesn't have")
+
+		err = router.Add("deploy {word}", func(struct {
+			app string `cucumber:"word"`
+		}) {
+		})
+		require.EqualError(t, err, "The handler of deploy {word} has a field app for an argument, which must be exported")
+	})
+
+	t.Run("does not add invalid handlers", func(t *testing.T) {
+		router := NewRouter(NewParameterTypeRegistry(), FirstRoute)
+		require.EqualError(t, router.Add("status", "status"), "The handler of status must be a function that returns nothing or an error, not a string")
+		require.EqualError(t, router.Add("status", func() int { return 0 }), "The handler of status must be a function that returns nothing or an error, not a func() int")
+		require.EqualError(t, router.Add("deploy {word}", func() {}), "The handler of deploy {word} has 0 parameters, but the expression has 1")
+		require.EqualError(t, router.Add("{unknown}", func(string) {}), "Undefined parameter type {unknown}")
+	})
+
+	t.Run("converts arguments to other number types", func(t *testing.T) {
+		router := NewRouter(NewParameterTypeRegistry(), FirstRoute)
+		var servers int64
+		var load float32
+		require.NoError(t, router.Add("deploy to {int} servers at {float}", func(s int64, l float32) {
+			servers, load = s, l
+		}))
+		require.NoError(t, router.Dispatch(context.Background(), "deploy to 3 servers at 0.5"))
+		require.Equal(t, int64(3), servers)
+		require.Equal(t, float32(0.5), load)
+	})
+
+	t.Run("does not add handlers for arguments of other types", func(t *testing.T) {
+		router := NewRouter(NewParameterTypeRegistry(), FirstRoute)
+		err := router.Add("deploy {int}", func(string) {})
+		require.EqualError(t, err, "The handler of deploy {int} takes string for {int}, whose values are of type int")
+		require.Equal(t, HandlerParameterTypeCode, ErrorCodeOf(err))
+
+		err = router.Add("deploy {word}", func(struct {
+			App int `cucumber:"word"`
+		}) {
+		})
+		require.EqualError(t, err, "The handler of deploy {word} takes int for {word}, whose values are of type string")
+	})
+
+	t.Run("does not convert arguments of parameter types without a Go type", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		parameterType, err := NewParameterType("servers", []*regexp.Regexp{regexp.MustCompile(`\d+`)}, "int", func(args ...*string) interface{} {
+			return len(*args[0])
+		}, false, false, false)
+		require.NoError(t, err)
+		require.NoError(t, registry.DefineParameterType(parameterType))
+		router := NewRouter(registry, FirstRoute)
+		require.NoError(t, router.Add("deploy to {servers}", func(string) {}))
+		err = router.Dispatch(context.Background(), "deploy to 300")
+		require.EqualError(t, err, "The value of {servers} is of type int, not string")
+	})
+
+	t.Run("does not convert arguments that don't fit", func(t *testing.T) {
+		router := NewRouter(NewParameterTypeRegistry(), FirstRoute)
+		require.NoError(t, router.Add("deploy to {int} servers", func(int8) {}))
+		err := router.Dispatch(context.Background(), "deploy to 300 servers")
+		require.EqualError(t, err, "The value 300 of {int} is out of the range of int8")
+		require.Equal(t, ArgumentOutOfRangeCode, ErrorCodeOf(err))
+	})
+}
@@ -0,0 +1,42 @@
+#!/usr/bin/env node
+// Reference implementation adapter for differential_test.go.
+//
+// Reads NDJSON requests from STDIN and writes one NDJSON result per request
+// to STDOUT, using the JavaScript implementation (build it first with
+// `npm run build` in ../javascript):
+//
+//   {"expression": "...", "text": "..."} => {"args": [...] | null} or {"error": "..."}
+//   {"text": "..."}                       => {"generated": ["...", ...]}
+const readline = require('readline')
+const {
+  CucumberExpression,
+  RegularExpression,
+  CucumberExpressionGenerator,
+  ParameterTypeRegistry,
+} = require('../../javascript/dist/src')
+
+function handle(request) {
+  const registry = new ParameterTypeRegistry()
+  if (request.expression === undefined) {
+    const generated = new CucumberExpressionGenerator(registry).generateExpressions(request.text)
+    return { generated: generated.map((g) => g.source) }
+  }
+  try {
+    const m = /^\/(.*)\/$/.exec(request.expression)
+    const expression = m
+      ? new RegularExpression(new RegExp(m[1]), registry)
+      : new CucumberExpression(request.expression, registry)
+    const args = expression.match(request.text)
+    return { args: args ? args.map((arg) => arg.getValue(null)) : null }
+  } catch (err) {
+    return { error: err.message }
+  }
+}
+
+readline
+  .createInterface({ input: process.stdin })
+  .on('line', (line) => {
+    if (line.trim() !== '') {
+      process.stdout.write(JSON.stringify(handle(JSON.parse(line))) + '\n')
+    }
+  })
@@ -0,0 +1,76 @@
+package cucumberexpressions
+
+// switchableSyntax are the syntax features that are off until a registry
+// enables them, as they are experimental. The others are always enabled.
+var switchableSyntax = map[SyntaxFeature]bool{
+	NamedParameterSyntax:   true,
+	TemplateFunctionSyntax: true,
+}
+
+// SetSyntaxEnabled enables or disables an experimental syntax feature for
+// the expressions compiled with the registry, such as named parameters:
+//
+//	registry.SetSyntaxEnabled(NamedParameterSyntax, true)
+//
+// Expressions and templates that use a feature that is not enabled return
+// a SyntaxNotEnabledError. Only NamedParameterSyntax and
+// TemplateFunctionSyntax can be switched, the other features are always
+// enabled.
+func (p *ParameterTypeRegistry) SetSyntaxEnabled(feature SyntaxFeature, enabled bool) error {
+	if !switchableSyntax[feature] {
+		return newMessageError(SyntaxNotSwitchableMessage, feature)
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	// The features are copied, as clones share them
+	features := make(map[SyntaxFeature]bool, len(p.enabledSyntax)+1)
+	for f, e := range p.enabledSyntax {
+		features[f] = e
+	}
+	features[feature] = enabled
+	p.enabledSyntax = features
+	return nil
+}
+
+// SyntaxEnabled returns whether expressions compiled with the registry may
+// use a syntax feature
+func (p *ParameterTypeRegistry) SyntaxEnabled(feature SyntaxFeature) bool {
+	if !switchableSyntax[feature] {
+		return true
+	}
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.enabledSyntax[feature]
+}
+
+// SyntaxNotEnabledError is the use of a syntax feature that is not enabled
+// in the registry. Construct is the text that uses it, such as
+// {count:int}. When it is in an expression, Start and End are the offsets
+// in runes of it in Expression.
+type SyntaxNotEnabledError struct {
+	Feature    SyntaxFeature
+	Construct  string
+	Expression string
+	Start      int
+	End        int
+}
+
+func (e *SyntaxNotEnabledError) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e *SyntaxNotEnabledError) localize(messages Messages) string {
+	problem, hint := e.describe(messages)
+	if e.Expression == "" {
+		return problem + ". " + hint
+	}
+	return messages.problem(e.Expression, e.Start, e.End, problem, hint)
+}
+
+func (e *SyntaxNotEnabledError) span() (int, int) {
+	return e.Start, e.End
+}
+
+func (e *SyntaxNotEnabledError) describe(messages Messages) (string, string) {
+	return messages.format(SyntaxNotEnabledMessage, e.Construct, e.Feature), messages.format(SyntaxNotEnabledHintMessage, e.Feature)
+}
@@ -0,0 +1,139 @@
+package cucumberexpressions
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// TemplateFunction resolves the argument of a template in step text, such
+// as BASE_URL in ${env:BASE_URL}
+type TemplateFunction func(argument string) (string, error)
+
+// TEMPLATE_REGEXP matches templates. They start with a $, so they can't be
+// confused with named parameters such as {count:int}.
+var TEMPLATE_REGEXP = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*):([^{}]*)\}`)
+
+// DefineTemplateFunction makes templates such as ${env:BASE_URL} in the text
+// of arguments resolve with a function, such as:
+//
+//	registry.DefineTemplateFunction("env", func(name string) (string, error) {
+//		value, ok := os.LookupEnv(name)
+//		if !ok {
+//			return "", fmt.Errorf("%s is not set", name)
+//		}
+//		return value, nil
+//	})
+//
+// so the argument of {string} in `I open "${env:BASE_URL}/login"` is the
+// URL. Templates are resolved when an expression matches, before the
+// arguments are transformed, and text outside of arguments is kept.
+// Templates are experimental, so TemplateFunctionSyntax has to be enabled
+// first.
+func (p *ParameterTypeRegistry) DefineTemplateFunction(name string, function TemplateFunction) error {
+	if !p.SyntaxEnabled(TemplateFunctionSyntax) {
+		return &SyntaxNotEnabledError{Feature: TemplateFunctionSyntax, Construct: "${" + name + ":...}"}
+	}
+	if !PARAMETER_NAME_REGEXP.MatchString(name) {
+		return newMessageError(InvalidTemplateFunctionNameMessage, name)
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if _, ok := p.templateFunctions[name]; ok {
+		return newMessageError(TemplateFunctionAlreadyDefinedMessage, name)
+	}
+	// The functions are copied, as clones share them
+	functions := make(map[string]TemplateFunction, len(p.templateFunctions)+1)
+	for n, f := range p.templateFunctions {
+		functions[n] = f
+	}
+	functions[name] = function
+	p.templateFunctions = functions
+	return nil
+}
+
+// TemplateError is a template in step text that could not be resolved.
+// Start and End are the offsets in runes of the template in the text.
+type TemplateError struct {
+	Template string
+	Start    int
+	End      int
+	Err      error
+}
+
+func (e *TemplateError) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e *TemplateError) localize(messages Messages) string {
+	return messages.format(TemplateFailedMessage, e.Template, e.Start+1, e.Err)
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// resolveTemplates resolves the templates in the text of arguments with
+// the template functions of the registry
+func resolveTemplates(parameterTypeRegistry *ParameterTypeRegistry, text string, arguments []*Argument) error {
+	parameterTypeRegistry.mutex.RLock()
+	functions := parameterTypeRegistry.templateFunctions
+	enabled := parameterTypeRegistry.enabledSyntax[TemplateFunctionSyntax]
+	parameterTypeRegistry.mutex.RUnlock()
+	if !enabled || len(functions) == 0 {
+		return nil
+	}
+	for _, argument := range arguments {
+		if argument.group.Value() == nil {
+			continue
+		}
+		// The values are the ones of Group.Values
+		groups := argument.group.Children()
+		if len(groups) == 0 {
+			groups = []*Group{argument.group}
+		}
+		values := make([]*string, len(groups))
+		for i, group := range groups {
+			if group.Value() == nil {
+				continue
+			}
+			value, err := resolveGroupTemplates(functions, text, group)
+			if err != nil {
+				return err
+			}
+			values[i] = &value
+		}
+		argument.resolved = values
+	}
+	return nil
+}
+
+// resolveGroupTemplates returns the value of a group with its templates
+// resolved. Templates of functions that aren't defined are kept.
+func resolveGroupTemplates(functions map[string]TemplateFunction, text string, group *Group) (string, error) {
+	value := *group.Value()
+	var result strings.Builder
+	end := 0
+	for _, loc := range TEMPLATE_REGEXP.FindAllStringSubmatchIndex(value, -1) {
+		function, ok := functions[value[loc[2]:loc[3]]]
+		if !ok {
+			continue
+		}
+		resolved, err := function(value[loc[4]:loc[5]])
+		if err != nil {
+			// Group offsets are in bytes
+			start := group.Start() + loc[0]
+			return "", &TemplateError{
+				Template: value[loc[0]:loc[1]],
+				Start:    utf8.RuneCountInString(text[:start]),
+				End:      utf8.RuneCountInString(text[:start+loc[1]-loc[0]]),
+				Err:      err,
+			}
+		}
+		result.WriteString(value[end:loc[0]])
+		result.WriteString(resolved)
+		end = loc[1]
+	}
+	result.WriteString(value[end:])
+	return result.String(), nil
+}
@@ -0,0 +1,102 @@
+package cucumberexpressions
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateFunctions(t *testing.T) {
+	env := func(name string) (string, error) {
+		if name == "BASE_URL" {
+			return "https://example.com", nil
+		}
+		return "", errors.New(name + " is not set")
+	}
+	newRegistry := func() *ParameterTypeRegistry {
+		registry := NewParameterTypeRegistry()
+		require.NoError(t, registry.SetSyntaxEnabled(TemplateFunctionSyntax, true))
+		return registry
+	}
+
+	t.Run("resolves templates in arguments", func(t *testing.T) {
+		registry := newRegistry()
+		require.NoError(t, registry.DefineTemplateFunction("env", env))
+		expression, err := NewCucumberExpression("I open {string} as {word}", registry)
+		require.NoError(t, err)
+		args, err := expression.Match(`I open "${env:BASE_URL}/login" as ${env:BASE_URL}`)
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com/login", args[0].GetValue())
+		require.Equal(t, "https://example.com", args[1].GetValue())
+	})
+
+	t.Run("resolves templates in arguments of regular expressions", func(t *testing.T) {
+		registry := newRegistry()
+		require.NoError(t, registry.DefineTemplateFunction("env", env))
+		args, err := NewRegularExpression(regexp.MustCompile(`^I open (\S+)$`), registry).Match("I open ${env:BASE_URL}/login")
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com/login", args[0].GetValue())
+	})
+
+	t.Run("keeps templates of functions that aren't defined", func(t *testing.T) {
+		registry := newRegistry()
+		require.NoError(t, registry.DefineTemplateFunction("env", env))
+		expression, err := NewCucumberExpression("I open {word}", registry)
+		require.NoError(t, err)
+		args, err := expression.Match("I open ${secret:KEY}")
+		require.NoError(t, err)
+		require.Equal(t, "${secret:KEY}", args[0].GetValue())
+	})
+
+	t.Run("keeps text that looks like a named parameter", func(t *testing.T) {
+		registry := newRegistry()
+		require.NoError(t, registry.DefineTemplateFunction("env", env))
+		expression, err := NewCucumberExpression("I open {word}", registry)
+		require.NoError(t, err)
+		args, err := expression.Match("I open {env:BASE_URL}")
+		require.NoError(t, err)
+		require.Equal(t, "{env:BASE_URL}", args[0].GetValue())
+	})
+
+	t.Run("reports templates that can't be resolved with their position", func(t *testing.T) {
+		registry := newRegistry()
+		require.NoError(t, registry.DefineTemplateFunction("env", env))
+		expression, err := NewCucumberExpression("I open {string}", registry)
+		require.NoError(t, err)
+		_, err = expression.Match(`I open "café/${env:HOST}"`)
+		require.EqualError(t, err, "Could not resolve ${env:HOST} at column 14: HOST is not set")
+		require.Equal(t, TemplateFailedCode, ErrorCodeOf(err))
+		var templateError *TemplateError
+		require.True(t, errors.As(err, &templateError))
+		require.Equal(t, 13, templateError.Start)
+		require.Equal(t, 24, templateError.End)
+	})
+
+	t.Run("does not define invalid or duplicate template functions", func(t *testing.T) {
+		registry := newRegistry()
+		err := registry.DefineTemplateFunction("my env", env)
+		require.Equal(t, InvalidTemplateFunctionNameCode, ErrorCodeOf(err))
+		require.NoError(t, registry.DefineTemplateFunction("env", env))
+		require.EqualError(t, registry.DefineTemplateFunction("env", env), "There is already a template function with name env")
+
+		clone := registry.Clone()
+		require.NoError(t, clone.DefineTemplateFunction("secret", env))
+		require.NoError(t, registry.DefineTemplateFunction("secret", env))
+	})
+
+	t.Run("requires enabling templates", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		err := registry.DefineTemplateFunction("env", env)
+		require.EqualError(t, err, `${env:...} requires enabling the template-function syntax. It can be enabled with ParameterTypeRegistry.SetSyntaxEnabled("template-function", true)`)
+		require.Equal(t, SyntaxNotEnabledCode, ErrorCodeOf(err))
+
+		registry = newRegistry()
+		require.NoError(t, registry.DefineTemplateFunction("env", env))
+		require.NoError(t, registry.SetSyntaxEnabled(TemplateFunctionSyntax, false))
+		args, err := NewRegularExpression(regexp.MustCompile(`^I open (\S+)$`), registry).Match("I open ${env:BASE_URL}")
+		require.NoError(t, err)
+		require.Equal(t, "${env:BASE_URL}", args[0].GetValue())
+	})
+}
@@ -0,0 +1,169 @@
+package cucumberexpressions
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultDateLayouts are the layouts of the dates {date} matches: ISO 8601
+// ones, such as 2006-01-02
+var DefaultDateLayouts = []string{"2006-01-02"}
+
+// DefaultDateTimeLayouts are the layouts of the date and times {datetime}
+// matches: ISO 8601 ones, such as 2006-01-02T15:04:05Z07:00. Seconds may
+// have a fraction, and times without a time zone are in UTC.
+var DefaultDateTimeLayouts = []string{
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+}
+
+// AddDateLayouts makes {date} also match and transform dates written in
+// layouts of the time package, such as "02.01.2006". Expressions compiled
+// before keep matching the layouts {date} had then.
+func (p *ParameterTypeRegistry) AddDateLayouts(layouts ...string) error {
+	return p.addTimeLayouts("date", layouts)
+}
+
+// AddDateTimeLayouts makes {datetime} also match and transform date and
+// times written in layouts of the time package, such as
+// "02.01.2006 15:04". Expressions compiled before keep matching the layouts
+// {datetime} had then.
+func (p *ParameterTypeRegistry) AddDateTimeLayouts(layouts ...string) error {
+	return p.addTimeLayouts("datetime", layouts)
+}
+
+func (p *ParameterTypeRegistry) addTimeLayouts(name string, layouts []string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	layouts = append(append([]string(nil), p.timeLayouts[name]...), layouts...)
+	parameterType, err := newTimeParameterType(name, layouts)
+	if err != nil {
+		return err
+	}
+	// {date} and {datetime} aren't used by regular expressions, so only
+	// their name is redefined
+	p.parameterTypeByName[name] = parameterType
+	p.timeLayouts[name] = layouts
+	return nil
+}
+
+// newTimeParameterType returns {date} or {datetime}, which transform the
+// text they match with the first of their layouts that parses it
+func newTimeParameterType(name string, layouts []string) (*ParameterType, error) {
+	regexps := make([]*regexp.Regexp, len(layouts))
+	for i, layout := range layouts {
+		layoutRegexp, err := timeLayoutRegexp(name, layout)
+		if err != nil {
+			return nil, err
+		}
+		regexps[i] = layoutRegexp
+	}
+	parameterType, err := NewParameterType(
+		name,
+		regexps,
+		"time.Time",
+		func(args ...*string) interface{} {
+			var err error
+			for _, layout := range layouts {
+				var t time.Time
+				if t, err = time.Parse(layout, *args[0]); err == nil {
+					return t
+				}
+			}
+			panic(&transformError{name, err})
+		},
+		false,
+		false,
+		false,
+	)
+	if err != nil {
+		return nil, err
+	}
+	parameterType.valueType = reflect.TypeOf(time.Time{})
+	return parameterType, nil
+}
+
+// referenceTime is the time of the layouts of the time package
+var referenceTime = time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+// timeLayoutRegexp returns a regexp matching the times written in a
+// layout of the time package, without capture groups
+func timeLayoutRegexp(name string, layout string) (*regexp.Regexp, error) {
+	var source strings.Builder
+	for rest := layout; rest != ""; {
+		element, pattern := nextTimeLayoutElement(rest)
+		source.WriteString(pattern)
+		rest = rest[len(element):]
+	}
+	// The layout is supported when its regexp matches the reference time
+	anchored := regexp.MustCompile(`^(?:` + source.String() + `)$`)
+	if layout == "" || !anchored.MatchString(referenceTime.Format(layout)) {
+		return nil, newMessageError(UnsupportedTimeLayoutMessage, layout, name)
+	}
+	return regexp.MustCompile(source.String()), nil
+}
+
+// timeLayoutElements are the elements of layouts of the time package and
+// the patterns of the text they stand for, longest first. Seconds may have
+// a fraction, which time.Parse accepts without a layout element for it.
+var timeLayoutElements = []struct {
+	element string
+	pattern string
+}{
+	{"January", `[A-Z][a-z]+`},
+	{"Monday", `[A-Z][a-z]+`},
+	{"Z07:00:00", `(?:Z|[+-]\d{2}:\d{2}:\d{2})`},
+	{"-07:00:00", `[+-]\d{2}:\d{2}:\d{2}`},
+	{"Z07:00", `(?:Z|[+-]\d{2}:\d{2})`},
+	{"-07:00", `[+-]\d{2}:\d{2}`},
+	{"Z0700", `(?:Z|[+-]\d{4})`},
+	{"-0700", `[+-]\d{4}`},
+	{"2006", `\d{4}`},
+	{"Z07", `(?:Z|[+-]\d{2})`},
+	{"-07", `[+-]\d{2}`},
+	{"Jan", `[A-Z][a-z]{2}`},
+	{"Mon", `[A-Z][a-z]{2}`},
+	{"MST", `[A-Z]{3,5}`},
+	{"002", `\d{3}`},
+	{"__2", ` {0,2}\d{1,3}`},
+	{"_2", ` ?\d{1,2}`},
+	{"PM", `[AP]M`},
+	{"pm", `[ap]m`},
+	{"01", `\d{2}`},
+	{"02", `\d{2}`},
+	{"03", `\d{2}`},
+	{"04", `\d{2}`},
+	{"05", `\d{2}(?:[.,]\d+)?`},
+	{"06", `\d{2}`},
+	{"15", `\d{2}`},
+	{"1", `\d{1,2}`},
+	{"2", `\d{1,2}`},
+	{"3", `\d{1,2}`},
+	{"4", `\d{1,2}`},
+	{"5", `\d{1,2}(?:[.,]\d+)?`},
+}
+
+var fractionalSecondsElement = regexp.MustCompile(`^[.,](?:0+|9+)`)
+
+func nextTimeLayoutElement(layout string) (element string, pattern string) {
+	if fraction := fractionalSecondsElement.FindString(layout); fraction != "" {
+		if next := layout[len(fraction):]; next == "" || next[0] < '0' || next[0] > '9' {
+			if fraction[1] == '9' {
+				return fraction, `(?:[.,]\d+)?`
+			}
+			return fraction, `[.,]\d{` + strconv.Itoa(len(fraction)-1) + `}`
+		}
+	}
+	for _, e := range timeLayoutElements {
+		if strings.HasPrefix(layout, e.element) {
+			return e.element, e.pattern
+		}
+	}
+	_, size := utf8.DecodeRuneInString(layout)
+	return layout[:size], regexp.QuoteMeta(layout[:size])
+}
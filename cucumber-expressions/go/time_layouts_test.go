@@ -0,0 +1,83 @@
+package cucumberexpressions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeLayouts(t *testing.T) {
+	match := func(t *testing.T, registry *ParameterTypeRegistry, expr string, text string) []interface{} {
+		expression, err := NewCucumberExpression(expr, registry)
+		require.NoError(t, err)
+		args, err := expression.Match(text)
+		require.NoError(t, err)
+		if args == nil {
+			return nil
+		}
+		values := make([]interface{}, len(args))
+		for i, arg := range args {
+			values[i] = arg.GetValue()
+		}
+		return values
+	}
+
+	t.Run("matches ISO 8601 dates and date times", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		require.Equal(t, []interface{}{time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)}, match(t, registry, "due on {date}", "due on 2021-03-04"))
+		require.Nil(t, match(t, registry, "due on {date}", "due on 04.03.2021"))
+
+		require.Equal(t, []interface{}{time.Date(2021, 3, 4, 5, 6, 0, 0, time.UTC)}, match(t, registry, "at {datetime}", "at 2021-03-04T05:06"))
+		require.Equal(t, []interface{}{time.Date(2021, 3, 4, 5, 6, 7, 500000000, time.UTC)}, match(t, registry, "at {datetime}", "at 2021-03-04T05:06:07.5"))
+		values := match(t, registry, "at {datetime}", "at 2021-03-04T05:06:07+02:00")
+		require.True(t, time.Date(2021, 3, 4, 3, 6, 7, 0, time.UTC).Equal(values[0].(time.Time)))
+	})
+
+	t.Run("matches dates and date times in added layouts", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		require.NoError(t, registry.AddDateLayouts("02.01.2006", "Jan _2, 2006"))
+		require.NoError(t, registry.AddDateTimeLayouts("02.01.2006 15:04", "3:04pm on 2 January 2006"))
+
+		require.Equal(t, []interface{}{time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)}, match(t, registry, "due on {date}", "due on 04.03.2021"))
+		require.Equal(t, []interface{}{time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)}, match(t, registry, "due on {date}", "due on Mar  4, 2021"))
+		require.Equal(t, []interface{}{time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)}, match(t, registry, "due on {date}", "due on 2021-03-04"))
+		require.Equal(t, []interface{}{time.Date(2021, 3, 4, 17, 6, 0, 0, time.UTC)}, match(t, registry, "at {datetime}", "at 04.03.2021 17:06"))
+		require.Equal(t, []interface{}{time.Date(2021, 3, 4, 17, 6, 0, 0, time.UTC)}, match(t, registry, "at {datetime}", "at 5:06pm on 4 March 2021"))
+	})
+
+	t.Run("keeps the layouts of expressions compiled before", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		expression, err := NewCucumberExpression("due on {date}", registry)
+		require.NoError(t, err)
+		require.NoError(t, registry.AddDateLayouts("02.01.2006"))
+		args, err := expression.Match("due on 04.03.2021")
+		require.NoError(t, err)
+		require.Nil(t, args)
+	})
+
+	t.Run("keeps the layouts of clones and imported registries", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		require.NoError(t, registry.AddDateLayouts("02.01.2006"))
+		require.Equal(t, []interface{}{time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)}, match(t, registry.Clone(), "{date}", "04.03.2021"))
+
+		require.NoError(t, registry.UnmarshalJSON([]byte(`{"parameterTypes": []}`)))
+		require.Equal(t, []interface{}{time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)}, match(t, registry, "{date}", "04.03.2021"))
+	})
+
+	t.Run("does not add layouts it can't match", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		err := registry.AddDateLayouts("")
+		require.EqualError(t, err, `The layout "" of {date} is not supported`)
+		require.Equal(t, UnsupportedTimeLayoutCode, ErrorCodeOf(err))
+	})
+
+	t.Run("reports dates that don't exist", func(t *testing.T) {
+		expression, err := NewCucumberExpression("due on {date}", NewParameterTypeRegistry())
+		require.NoError(t, err)
+		args, err := expression.Match("due on 2021-02-30")
+		require.NoError(t, err)
+		_, err = ArgumentValue[time.Time](args[0])
+		require.EqualError(t, err, `Could not transform {date}: parsing time "2021-02-30": day out of range`)
+	})
+}
@@ -2,6 +2,7 @@ package cucumberexpressions
 
 import (
 	"regexp"
+	"strings"
 )
 
 type TreeRegexp struct {
@@ -34,13 +35,19 @@ func createGroupBuilder(regexp *regexp.Regexp) *GroupBuilder {
 			nonCapturing := isNonCapturing(source, i)
 			if nonCapturing {
 				groupBuilder.SetNonCapturing()
+			} else {
+				groupBuilder.SetName(groupName(source, i))
 			}
 			stack.Push(groupBuilder)
 		} else if c == ')' && !escaping && !charClass {
 			gb := stack.Pop()
 			groupStart := groupStartStack.Pop()
 			if gb.Capturing() {
-				gb.SetSource(source[groupStart+1 : i])
+				sourceStart := groupStart + 1
+				if gb.Name() != "" {
+					sourceStart += strings.Index(source[sourceStart:], ">") + 1
+				}
+				gb.SetSource(source[sourceStart:i])
 				stack.Peek().Add(gb)
 			} else {
 				gb.MoveChildrenTo(stack.Peek())
@@ -65,10 +72,26 @@ func isNonCapturing(source string, i int) bool {
 			return false
 		}
 	}
+	if source[i+2] == '<' {
+		// (?<name>X)
+		return false
+	}
 	// (?...)
 	return true
 }
 
+func groupName(source string, i int) string {
+	// Regex is valid. Bounds check not required.
+	if source[i+1] != '?' {
+		return ""
+	}
+	nameStart := i + 3
+	if source[i+2] == 'P' {
+		nameStart++
+	}
+	return source[nameStart : nameStart+strings.Index(source[nameStart:], ">")]
+}
+
 func (t *TreeRegexp) Regexp() *regexp.Regexp {
 	return t.regexp
 }
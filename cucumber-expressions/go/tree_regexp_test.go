@@ -40,6 +40,15 @@ func TestTreeRegexp(t *testing.T) {
 		require.Equal(t, *group.Children()[0].Value(), "b")
 	})
 
+	t.Run("exposes the name of named capturing groups", func(t *testing.T) {
+		tr := NewTreeRegexp(regexp.MustCompile("a(?P<first>b)(?<second>c)(d)"))
+		group := tr.Match("abcd")
+		require.Equal(t, "first", group.Children()[0].Name())
+		require.Equal(t, "second", group.Children()[1].Name())
+		require.Equal(t, "", group.Children()[2].Name())
+		require.Equal(t, "b", tr.GroupBuilder().Children()[0].Source())
+	})
+
 	t.Run("matches optional group", func(t *testing.T) {
 		tr := NewTreeRegexp(regexp.MustCompile("^Something( with an optional argument)?"))
 		group := tr.Match("Something")
@@ -0,0 +1,167 @@
+package cucumberexpressions
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefineTypedParameterType defines a parameter type whose transform returns
+// a T, such as:
+//
+//	DefineTypedParameterType(registry, "color", "red|blue", func(args ...string) (Color, error) {
+//		return ParseColor(args[0])
+//	})
+//
+// ArgumentValue returns the values of its arguments as a T, without a type
+// assertion. The transform gets the capture groups of the regexp, or the
+// whole match when it has none.
+func DefineTypedParameterType[T any](parameterTypeRegistry *ParameterTypeRegistry, name string, parameterTypeRegexp string, transform func(...string) (T, error)) error {
+	compiled, err := regexp.Compile(parameterTypeRegexp)
+	if err != nil {
+		return err
+	}
+	parameterType, err := NewParameterType(
+		name,
+		[]*regexp.Regexp{compiled},
+		typeName[T](),
+		func(args ...*string) interface{} {
+			values := make([]string, len(args))
+			for i, arg := range args {
+				if arg != nil {
+					values[i] = *arg
+				}
+			}
+			value, err := transform(values...)
+			if err != nil {
+				panic(&transformError{name, err})
+			}
+			return value
+		},
+		true,
+		false,
+		false,
+	)
+	if err != nil {
+		return err
+	}
+	parameterType.valueType = reflectType[T]()
+	return parameterTypeRegistry.DefineParameterType(parameterType)
+}
+
+// ParameterTypeFromEnum returns a parameter type matching the keys of
+// values and transforming them to their value, such as:
+//
+//	ParameterTypeFromEnum("color", map[string]Color{"red": Red, "green": Green, "blue": Blue})
+//
+// which matches "red", "green" and "blue".
+func ParameterTypeFromEnum[T any](name string, values map[string]T) (*ParameterType, error) {
+	if len(values) == 0 {
+		return nil, newMessageError(EmptyEnumMessage, name)
+	}
+	// The values are copied, so changing the map doesn't change the
+	// parameter type
+	copied := make(map[string]T, len(values))
+	for word, value := range values {
+		copied[word] = value
+	}
+	parameterType, err := NewParameterType(
+		name,
+		[]*regexp.Regexp{wordsRegexp(copied)},
+		typeName[T](),
+		func(args ...*string) interface{} {
+			return copied[*args[0]]
+		},
+		true,
+		false,
+		false,
+	)
+	if err != nil {
+		return nil, err
+	}
+	parameterType.valueType = reflectType[T]()
+	return parameterType, nil
+}
+
+// wordsRegexp returns a regexp matching the keys of words. Longer keys come
+// first, so "no" doesn't match the start of "not".
+func wordsRegexp[T any](words map[string]T) *regexp.Regexp {
+	alternatives := make([]string, 0, len(words))
+	for word := range words {
+		alternatives = append(alternatives, regexp.QuoteMeta(word))
+	}
+	sort.Slice(alternatives, func(i int, j int) bool {
+		if len(alternatives[i]) != len(alternatives[j]) {
+			return len(alternatives[i]) > len(alternatives[j])
+		}
+		return alternatives[i] < alternatives[j]
+	})
+	return regexp.MustCompile(strings.Join(alternatives, "|"))
+}
+
+// ArgumentValue returns the value of an argument as a T. It returns the
+// error of the transform of a parameter type defined with
+// DefineTypedParameterType instead of panicking like GetValue does, and the
+// zero T when the argument didn't match.
+//
+// The anonymous parameter type {} matches anything, and ArgumentValue
+// converts its text to T like a type hint to Match would.
+func ArgumentValue[T any](argument *Argument) (value T, err error) {
+	if argument.ParameterType().isDeAnonymized() {
+		return anonymousArgumentValue[T](argument)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			transformErr, ok := r.(*transformError)
+			if !ok {
+				panic(r)
+			}
+			err = transformErr
+		}
+	}()
+
+	untyped := argument.GetValue()
+	if untyped == nil {
+		return value, nil
+	}
+	value, ok := untyped.(T)
+	if !ok {
+		return value, newMessageError(ArgumentTypeMismatchMessage, argument.ParameterType().Name(), reflect.TypeOf(untyped).String(), typeName[T]())
+	}
+	return value, nil
+}
+
+func anonymousArgumentValue[T any](argument *Argument) (value T, err error) {
+	converted, err := anonymousValue(argument, reflectType[T]())
+	if err != nil {
+		return value, err
+	}
+	return converted.Interface().(T), nil
+}
+
+func typeName[T any]() string {
+	return reflectType[T]().String()
+}
+
+func reflectType[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// transformError is an error of the transform of a typed parameter type
+type transformError struct {
+	name string
+	err  error
+}
+
+func (e *transformError) Error() string {
+	return e.localize(englishMessages)
+}
+
+func (e *transformError) localize(messages Messages) string {
+	return messages.format(TransformFailedMessage, e.name, e.err)
+}
+
+func (e *transformError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,168 @@
+package cucumberexpressions
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type color struct {
+	name string
+}
+
+type coordinate struct {
+	x, y int
+}
+
+func TestTypedParameterType(t *testing.T) {
+	match := func(t *testing.T, registry *ParameterTypeRegistry, expr string, text string) []*Argument {
+		expression, err := NewCucumberExpression(expr, registry)
+		require.NoError(t, err)
+		args, err := expression.Match(text)
+		require.NoError(t, err)
+		return args
+	}
+
+	t.Run("returns values with their type", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		require.NoError(t, DefineTypedParameterType(registry, "color", "red|blue", func(args ...string) (color, error) {
+			return color{args[0]}, nil
+		}))
+
+		args := match(t, registry, "I have a {color} ball", "I have a red ball")
+		value, err := ArgumentValue[color](args[0])
+		require.NoError(t, err)
+		require.Equal(t, color{"red"}, value)
+		require.Equal(t, "cucumberexpressions.color", registry.LookupByTypeName("color").Type())
+	})
+
+	t.Run("transforms capture groups", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		require.NoError(t, DefineTypedParameterType(registry, "coordinate", `(\d+),(\d+)`, func(args ...string) (coordinate, error) {
+			var c coordinate
+			_, err := fmt.Sscan(args[0], &c.x)
+			if err == nil {
+				_, err = fmt.Sscan(args[1], &c.y)
+			}
+			return c, err
+		}))
+
+		args := match(t, registry, "go to {coordinate}", "go to 3,4")
+		value, err := ArgumentValue[coordinate](args[0])
+		require.NoError(t, err)
+		require.Equal(t, coordinate{3, 4}, value)
+	})
+
+	t.Run("returns the error of the transform", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		require.NoError(t, DefineTypedParameterType(registry, "color", "red|blue", func(args ...string) (color, error) {
+			return color{}, errors.New("out of paint")
+		}))
+
+		args := match(t, registry, "{color}", "blue")
+		_, err := ArgumentValue[color](args[0])
+		require.EqualError(t, err, "Could not transform {color}: out of paint")
+		require.Panics(t, func() { args[0].GetValue() })
+	})
+
+	t.Run("returns values of the built-in parameter types", func(t *testing.T) {
+		args := match(t, NewParameterTypeRegistry(), "{int} {word}", "42 cukes")
+		number, err := ArgumentValue[int](args[0])
+		require.NoError(t, err)
+		require.Equal(t, 42, number)
+
+		_, err = ArgumentValue[float64](args[1])
+		require.EqualError(t, err, "The value of {word} is of type string, not float64")
+	})
+
+	t.Run("converts anonymous arguments to the type of the caller", func(t *testing.T) {
+		args := match(t, NewParameterTypeRegistry(), "I have {} cukes in {}", "I have 42 cukes in my belly")
+		require.Equal(t, "42", args[0].GetValue())
+
+		number, err := ArgumentValue[int](args[0])
+		require.NoError(t, err)
+		require.Equal(t, 42, number)
+
+		type count uint8
+		small, err := ArgumentValue[count](args[0])
+		require.NoError(t, err)
+		require.Equal(t, count(42), small)
+
+		place, err := ArgumentValue[string](args[1])
+		require.NoError(t, err)
+		require.Equal(t, "my belly", place)
+
+		_, err = ArgumentValue[float64](args[1])
+		require.Error(t, err)
+	})
+
+	t.Run("converts anonymous arguments to text unmarshalers", func(t *testing.T) {
+		args := match(t, NewParameterTypeRegistry(), "at {}", "at 127.0.0.1")
+		ip, err := ArgumentValue[net.IP](args[0])
+		require.NoError(t, err)
+		require.Equal(t, "127.0.0.1", ip.String())
+	})
+
+	t.Run("transforms parameter types named anonymous", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		require.NoError(t, DefineTypedParameterType(registry, "anonymous", "red|blue", func(args ...string) (color, error) {
+			return color{args[0]}, nil
+		}))
+
+		value, err := ArgumentValue[color](match(t, registry, "I have a {anonymous} ball", "I have a red ball")[0])
+		require.NoError(t, err)
+		require.Equal(t, color{"red"}, value)
+	})
+
+	t.Run("matches empty anonymous arguments", func(t *testing.T) {
+		args := match(t, NewParameterTypeRegistry(), "say {}", "say ")
+		text, err := ArgumentValue[string](args[0])
+		require.NoError(t, err)
+		require.Equal(t, "", text)
+	})
+
+	t.Run("does not define an invalid regexp", func(t *testing.T) {
+		err := DefineTypedParameterType(NewParameterTypeRegistry(), "broken", "(", func(args ...string) (string, error) {
+			return args[0], nil
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("does not define a parameter type twice", func(t *testing.T) {
+		err := DefineTypedParameterType(NewParameterTypeRegistry(), "int", `\d+`, func(args ...string) (int, error) {
+			return 0, nil
+		})
+		require.EqualError(t, err, "There is already a parameter type with name int")
+		var alreadyDefinedError *ParameterTypeAlreadyDefinedError
+		require.True(t, errors.As(err, &alreadyDefinedError))
+		require.Equal(t, "int", alreadyDefinedError.Name)
+		require.Equal(t, ParameterTypeAlreadyDefinedCode, ErrorCodeOf(err))
+	})
+
+	t.Run("defines parameter types from enums", func(t *testing.T) {
+		values := map[string]color{"red": {"#f00"}, "green": {"#0f0"}, "greenish": {"#1e1"}}
+		parameterType, err := ParameterTypeFromEnum("color", values)
+		require.NoError(t, err)
+		values["blue"] = color{"#00f"}
+		registry := NewParameterTypeRegistry()
+		require.NoError(t, registry.DefineParameterType(parameterType))
+
+		value, err := ArgumentValue[color](match(t, registry, "a {color} ball", "a greenish ball")[0])
+		require.NoError(t, err)
+		require.Equal(t, color{"#1e1"}, value)
+		value, err = ArgumentValue[color](match(t, registry, "a {color} ball", "a red ball")[0])
+		require.NoError(t, err)
+		require.Equal(t, color{"#f00"}, value)
+		require.Nil(t, match(t, registry, "a {color} ball", "a blue ball"))
+		require.Equal(t, "cucumberexpressions.color", parameterType.Type())
+	})
+
+	t.Run("does not define parameter types from empty enums", func(t *testing.T) {
+		_, err := ParameterTypeFromEnum("color", map[string]color{})
+		require.EqualError(t, err, "The enum {color} has no values")
+		require.Equal(t, EmptyEnumCode, ErrorCodeOf(err))
+	})
+}
@@ -0,0 +1,58 @@
+package cucumberexpressions
+
+import "sort"
+
+// Updated by `make pre-release`
+const version = "10.3.0"
+
+type SyntaxFeature string
+
+const (
+	ParametersSyntax         SyntaxFeature = "parameters"
+	AnonymousParameterSyntax SyntaxFeature = "anonymous-parameter"
+	OptionalTextSyntax       SyntaxFeature = "optional-text"
+	AlternativeTextSyntax    SyntaxFeature = "alternative-text"
+	EscapingSyntax           SyntaxFeature = "escaping"
+	OptionalParameterSyntax  SyntaxFeature = "optional-parameter"
+	WhiteSpaceFoldingSyntax  SyntaxFeature = "white-space-folding"
+	NamedParameterSyntax     SyntaxFeature = "named-parameter"
+	TemplateFunctionSyntax   SyntaxFeature = "template-function"
+)
+
+// LibraryCapabilities describes what this version of the library supports,
+// for tools that have to work with several versions at runtime.
+type LibraryCapabilities struct {
+	Version               string
+	SyntaxFeatures        []SyntaxFeature
+	BuiltInParameterTypes []string
+}
+
+func Version() string {
+	return version
+}
+
+func Capabilities() LibraryCapabilities {
+	var builtInParameterTypes []string
+	for _, parameterType := range NewParameterTypeRegistry().ParameterTypes() {
+		if !parameterType.isAnonymous() {
+			builtInParameterTypes = append(builtInParameterTypes, parameterType.Name())
+		}
+	}
+	sort.Strings(builtInParameterTypes)
+
+	return LibraryCapabilities{
+		Version: version,
+		SyntaxFeatures: []SyntaxFeature{
+			ParametersSyntax,
+			AnonymousParameterSyntax,
+			OptionalTextSyntax,
+			AlternativeTextSyntax,
+			EscapingSyntax,
+			OptionalParameterSyntax,
+			WhiteSpaceFoldingSyntax,
+			NamedParameterSyntax,
+			TemplateFunctionSyntax,
+		},
+		BuiltInParameterTypes: builtInParameterTypes,
+	}
+}
@@ -0,0 +1,28 @@
+package cucumberexpressions
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilities(t *testing.T) {
+	t.Run("reports a semantic version", func(t *testing.T) {
+		require.Regexp(t, regexp.MustCompile(`^\d+\.\d+\.\d+$`), Version())
+		require.Equal(t, Version(), Capabilities().Version)
+	})
+
+	t.Run("lists the built-in parameter types", func(t *testing.T) {
+		require.Equal(t, []string{"bigdecimal", "biginteger", "bool", "date", "datetime", "duration", "float", "int", "json", "string", "uuid", "word"}, Capabilities().BuiltInParameterTypes)
+	})
+
+	t.Run("lists the supported syntax", func(t *testing.T) {
+		require.Contains(t, Capabilities().SyntaxFeatures, OptionalTextSyntax)
+		require.Contains(t, Capabilities().SyntaxFeatures, AnonymousParameterSyntax)
+		require.Contains(t, Capabilities().SyntaxFeatures, OptionalParameterSyntax)
+		require.Contains(t, Capabilities().SyntaxFeatures, WhiteSpaceFoldingSyntax)
+		require.Contains(t, Capabilities().SyntaxFeatures, NamedParameterSyntax)
+		require.Contains(t, Capabilities().SyntaxFeatures, TemplateFunctionSyntax)
+	})
+}
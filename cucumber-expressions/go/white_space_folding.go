@@ -0,0 +1,36 @@
+package cucumberexpressions
+
+import (
+	"regexp"
+)
+
+// FOLDED_WHITE_SPACE_REGEXP matches whitespace and escaped newlines in the
+// quoted text of an expression, which FOLDED_WHITE_SPACE replaces when the
+// registry folds whitespace
+var FOLDED_WHITE_SPACE_REGEXP = regexp.MustCompile(`(?:\\\\\n|[` + WHITE_SPACE_CLASS + `])+`)
+var FOLDED_WHITE_SPACE = `(?:\\\n|[` + WHITE_SPACE_CLASS + `])+`
+
+// SetFoldWhiteSpace makes whitespace in expressions match any whitespace
+// in step texts when fold is true, including newlines and escaped
+// newlines, so long steps can be wrapped:
+//
+//	Given a step that is \
+//	  wrapped over two lines
+//
+// matches the expression "a step that is wrapped over two lines" when
+// Gherkin parses it with ParseOptions.LineContinuation, and expressions
+// can be wrapped the same way. Expressions compiled before
+// keep matching the whitespace they did then.
+func (p *ParameterTypeRegistry) SetFoldWhiteSpace(fold bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.foldWhiteSpace = fold
+}
+
+// FoldWhiteSpace returns whether whitespace in expressions matches any
+// whitespace in step texts
+func (p *ParameterTypeRegistry) FoldWhiteSpace() bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.foldWhiteSpace
+}
@@ -0,0 +1,66 @@
+package cucumberexpressions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhiteSpaceFolding(t *testing.T) {
+	match := func(t *testing.T, registry *ParameterTypeRegistry, expr string, text string) []interface{} {
+		expression, err := NewCucumberExpression(expr, registry)
+		require.NoError(t, err)
+		args, err := expression.Match(text)
+		require.NoError(t, err)
+		if args == nil {
+			return nil
+		}
+		values := make([]interface{}, len(args))
+		for i, arg := range args {
+			values[i] = arg.GetValue()
+		}
+		return values
+	}
+
+	t.Run("does not fold whitespace by default", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		require.False(t, registry.FoldWhiteSpace())
+		require.Nil(t, match(t, registry, "I have {int} cukes", "I have 3\n  cukes"))
+	})
+
+	t.Run("matches wrapped step texts", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		registry.SetFoldWhiteSpace(true)
+		require.Equal(t, []interface{}{3}, match(t, registry, "I have {int} cukes in my belly", "I have 3\n  cukes in my belly"))
+		require.Equal(t, []interface{}{3}, match(t, registry, "I have {int} cukes in my belly", "I have 3 \\\n  cukes  in\tmy belly"))
+		require.Equal(t, []interface{}{3}, match(t, registry, "I have {int} cuke(s) in my belly/stomach", "I have 3 cukes\n in my stomach"))
+		require.Nil(t, match(t, registry, "I have {int} cukes", "I have 3cukes"))
+	})
+
+	t.Run("matches wrapped expressions", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		registry.SetFoldWhiteSpace(true)
+		require.Equal(t, []interface{}{3}, match(t, registry, "I have {int} \\\n    cukes", "I have 3 cukes"))
+		require.Equal(t, []interface{}{3}, match(t, registry, "I have {int}\n    cukes", "I have 3 cukes"))
+	})
+
+	t.Run("keeps whitespace in parameters", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		registry.SetFoldWhiteSpace(true)
+		require.Equal(t, []interface{}{"a  b"}, match(t, registry, "I say {string}", "I say\n\"a  b\""))
+	})
+
+	t.Run("keeps the folding of expressions compiled before, clones and imported registries", func(t *testing.T) {
+		registry := NewParameterTypeRegistry()
+		expression, err := NewCucumberExpression("I have {int} cukes", registry)
+		require.NoError(t, err)
+		registry.SetFoldWhiteSpace(true)
+		args, err := expression.Match("I have 3\ncukes")
+		require.NoError(t, err)
+		require.Nil(t, args)
+
+		require.True(t, registry.Clone().FoldWhiteSpace())
+		require.NoError(t, registry.UnmarshalJSON([]byte(`{"parameterTypes": []}`)))
+		require.True(t, registry.FoldWhiteSpace())
+	})
+}
@@ -0,0 +1,313 @@
+package gherkin
+
+import (
+	"context"
+	"fmt"
+	"github.com/cucumber/messages-go/v13"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Diagnostic is a problem in a document that parses, but probably doesn't
+// do what its author meant
+type Diagnostic struct {
+	Message  string
+	Location *messages.Location
+	Code     ErrorCode
+	Severity Severity
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("(%d:%d): %s", d.Location.Line, d.Location.Column, d.Message)
+}
+
+var PLACEHOLDER_REGEXP = regexp.MustCompile(`<([^<>\s](?:[^<>]*[^<>\s])?)>`)
+
+type placeholder struct {
+	name     string
+	location *messages.Location
+}
+
+// AnalyzePlaceholders reports placeholders of scenario outlines that are
+// not a column of their Examples, and Examples columns not used as a
+// placeholder
+func AnalyzePlaceholders(gherkinDocument *messages.GherkinDocument) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, scenario := range documentScenarios(gherkinDocument) {
+		placeholders := scenarioPlaceholders(scenario)
+		used := map[string]bool{}
+		for _, p := range placeholders {
+			used[p.name] = true
+		}
+		for _, examples := range scenario.Examples {
+			if examples.TableHeader == nil {
+				continue
+			}
+			columns := map[string]bool{}
+			for _, cell := range examples.TableHeader.Cells {
+				columns[cell.Value] = true
+				if !used[cell.Value] {
+					diagnostics = append(diagnostics, Diagnostic{
+						Message:  fmt.Sprintf("column %q of the examples is not used in the scenario outline", cell.Value),
+						Location: cell.Location,
+						Code:     UnusedExamplesColumnCode,
+						Severity: SeverityWarning,
+					})
+				}
+			}
+			for _, p := range placeholders {
+				if !columns[p.name] {
+					diagnostics = append(diagnostics, Diagnostic{
+						Message:  fmt.Sprintf("<%s> is not a column of the examples at line %d", p.name, examples.Location.Line),
+						Location: p.location,
+						Code:     UndefinedPlaceholderCode,
+						Severity: SeverityWarning,
+					})
+				}
+			}
+		}
+	}
+	sortDiagnostics(diagnostics)
+	return diagnostics
+}
+
+func documentScenarios(gherkinDocument *messages.GherkinDocument) []*messages.GherkinDocument_Feature_Scenario {
+	var scenarios []*messages.GherkinDocument_Feature_Scenario
+	if gherkinDocument.Feature == nil {
+		return scenarios
+	}
+	for _, child := range gherkinDocument.Feature.Children {
+		if scenario := child.GetScenario(); scenario != nil {
+			scenarios = append(scenarios, scenario)
+		}
+		if rule := child.GetRule(); rule != nil {
+			for _, ruleChild := range rule.Children {
+				if scenario := ruleChild.GetScenario(); scenario != nil {
+					scenarios = append(scenarios, scenario)
+				}
+			}
+		}
+	}
+	return scenarios
+}
+
+// scenarioPlaceholders finds the placeholders everywhere the pickle
+// compiler replaces them: in the name, in steps and in their arguments
+func scenarioPlaceholders(scenario *messages.GherkinDocument_Feature_Scenario) []placeholder {
+	// The location of the name isn't known exactly, so use the scenario's
+	placeholders := findPlaceholders(scenario.Name, scenario.Location.Line, scenario.Location.Column, false)
+	for _, step := range scenario.Steps {
+		column := step.Location.Column + uint32(utf8.RuneCountInString(step.Keyword))
+		placeholders = append(placeholders, findPlaceholders(step.Text, step.Location.Line, column, true)...)
+		if dataTable := step.GetDataTable(); dataTable != nil {
+			for _, row := range dataTable.Rows {
+				for _, cell := range row.Cells {
+					placeholders = append(placeholders, findPlaceholders(cell.Value, cell.Location.Line, cell.Location.Column, true)...)
+				}
+			}
+		}
+		if docString := step.GetDocString(); docString != nil {
+			placeholders = append(placeholders, findPlaceholders(docString.MediaType, docString.Location.Line, docString.Location.Column+3, true)...)
+			for i, line := range strings.Split(docString.Content, "\n") {
+				placeholders = append(placeholders, findPlaceholders(line, docString.Location.Line+1+uint32(i), docString.Location.Column, true)...)
+			}
+		}
+	}
+	return placeholders
+}
+
+func findPlaceholders(text string, line uint32, column uint32, exact bool) []placeholder {
+	var placeholders []placeholder
+	for _, match := range PLACEHOLDER_REGEXP.FindAllStringSubmatchIndex(text, -1) {
+		location := &messages.Location{Line: line, Column: column}
+		if exact {
+			location.Column += uint32(utf8.RuneCountInString(text[:match[0]]))
+		}
+		placeholders = append(placeholders, placeholder{text[match[2]:match[3]], location})
+	}
+	return placeholders
+}
+
+type ValueType string
+
+const (
+	ValueTypeEmpty   ValueType = "empty"
+	ValueTypeInteger ValueType = "integer"
+	ValueTypeDecimal ValueType = "decimal"
+	ValueTypeBoolean ValueType = "boolean"
+	ValueTypeText    ValueType = "text"
+)
+
+var INTEGER_REGEXP = regexp.MustCompile(`^[-+]?\d+$`)
+var DECIMAL_REGEXP = regexp.MustCompile(`^[-+]?(\d+\.\d*|\.\d+)([eE][-+]?\d+)?$`)
+
+// InferValueType returns the type of an Examples value
+func InferValueType(value string) ValueType {
+	switch {
+	case value == "":
+		return ValueTypeEmpty
+	case INTEGER_REGEXP.MatchString(value):
+		return ValueTypeInteger
+	case DECIMAL_REGEXP.MatchString(value):
+		return ValueTypeDecimal
+	case strings.EqualFold(value, "true") || strings.EqualFold(value, "false"):
+		return ValueTypeBoolean
+	}
+	return ValueTypeText
+}
+
+// ColumnTypes returns the type of the values in every column of examples.
+// Empty values are ignored, a column of integers and decimals is decimal,
+// and a column mixing other types is text.
+func ColumnTypes(examples *messages.GherkinDocument_Feature_Scenario_Examples) []ValueType {
+	if examples.TableHeader == nil {
+		return nil
+	}
+	types := make([]ValueType, len(examples.TableHeader.Cells))
+	for i := range types {
+		types[i] = ValueTypeEmpty
+		for _, row := range examples.TableBody {
+			types[i] = commonValueType(types[i], InferValueType(row.Cells[i].Value))
+		}
+	}
+	return types
+}
+
+func commonValueType(a ValueType, b ValueType) ValueType {
+	switch {
+	case a == b || b == ValueTypeEmpty:
+		return a
+	case a == ValueTypeEmpty:
+		return b
+	case isNumber(a) && isNumber(b):
+		return ValueTypeDecimal
+	}
+	return ValueTypeText
+}
+
+func isNumber(t ValueType) bool {
+	return t == ValueTypeInteger || t == ValueTypeDecimal
+}
+
+// StepMatcher tells whether a step text matches a step definition, e.g.
+// a Cucumber Expression
+type StepMatcher func(text string) bool
+
+// AnalyzeExamples reports values of Examples columns that have a different
+// type than most values of the column. With a StepMatcher, it also reports
+// rows whose values make an outline step that matches with other rows not
+// match.
+func AnalyzeExamples(gherkinDocument *messages.GherkinDocument, matchStep StepMatcher) []Diagnostic {
+	diagnostics, _ := AnalyzeExamplesContext(context.Background(), gherkinDocument, matchStep)
+	return diagnostics
+}
+
+// AnalyzeExamplesContext analyzes examples like AnalyzeExamples, and stops
+// with the error of ctx when it is done, such as when a language server
+// request is cancelled.
+func AnalyzeExamplesContext(ctx context.Context, gherkinDocument *messages.GherkinDocument, matchStep StepMatcher) ([]Diagnostic, error) {
+	var diagnostics []Diagnostic
+	for _, scenario := range documentScenarios(gherkinDocument) {
+		for _, examples := range scenario.Examples {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if examples.TableHeader == nil {
+				continue
+			}
+			diagnostics = append(diagnostics, mixedTypeDiagnostics(examples)...)
+			if matchStep != nil {
+				stepMatches, err := stepMatchDiagnostics(ctx, scenario, examples, matchStep)
+				if err != nil {
+					return nil, err
+				}
+				diagnostics = append(diagnostics, stepMatches...)
+			}
+		}
+	}
+	sortDiagnostics(diagnostics)
+	return diagnostics, nil
+}
+
+func mixedTypeDiagnostics(examples *messages.GherkinDocument_Feature_Scenario_Examples) []Diagnostic {
+	var diagnostics []Diagnostic
+	for i, header := range examples.TableHeader.Cells {
+		// Integers and decimals are counted as decimals, as they mix well
+		counts := map[ValueType]int{}
+		var majority ValueType
+		for _, row := range examples.TableBody {
+			t := numbersAsDecimal(InferValueType(row.Cells[i].Value))
+			if t == ValueTypeEmpty {
+				continue
+			}
+			counts[t]++
+			if counts[t] > counts[majority] {
+				majority = t
+			}
+		}
+		if len(counts) < 2 {
+			continue
+		}
+		for _, row := range examples.TableBody {
+			cell := row.Cells[i]
+			t := numbersAsDecimal(InferValueType(cell.Value))
+			if t != ValueTypeEmpty && t != majority {
+				diagnostics = append(diagnostics, Diagnostic{
+					Message:  fmt.Sprintf("%q in column %q is %s, most values are %s", cell.Value, header.Value, t, majority),
+					Location: cell.Location,
+					Code:     MixedValueTypesCode,
+					Severity: SeverityWarning,
+				})
+			}
+		}
+	}
+	return diagnostics
+}
+
+func numbersAsDecimal(t ValueType) ValueType {
+	if t == ValueTypeInteger {
+		return ValueTypeDecimal
+	}
+	return t
+}
+
+func stepMatchDiagnostics(ctx context.Context, scenario *messages.GherkinDocument_Feature_Scenario, examples *messages.GherkinDocument_Feature_Scenario_Examples, matchStep StepMatcher) ([]Diagnostic, error) {
+	var diagnostics []Diagnostic
+	for _, step := range scenario.Steps {
+		if !PLACEHOLDER_REGEXP.MatchString(step.Text) {
+			continue
+		}
+		var mismatches []Diagnostic
+		matchedAny := false
+		for _, row := range examples.TableBody {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			text := interpolate(step.Text, examples.TableHeader.Cells, row.Cells)
+			if matchStep(text) {
+				matchedAny = true
+				continue
+			}
+			mismatches = append(mismatches, Diagnostic{
+				Message:  fmt.Sprintf("step %q at line %d does not match a step definition with the values of this row", text, step.Location.Line),
+				Location: row.Location,
+				Code:     UnmatchedExamplesRowCode,
+				Severity: SeverityWarning,
+			})
+		}
+		// Steps without any match are undefined, which is reported when running them
+		if matchedAny {
+			diagnostics = append(diagnostics, mismatches...)
+		}
+	}
+	return diagnostics, nil
+}
+
+func sortDiagnostics(diagnostics []Diagnostic) {
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i].Location, diagnostics[j].Location
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+}
@@ -0,0 +1,166 @@
+package gherkin
+
+import (
+	"context"
+	"github.com/cucumber/messages-go/v13"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzePlaceholders(t *testing.T) {
+	analyze := func(text string) []string {
+		doc, err := ParseGherkinDocument(strings.NewReader(text), (&messages.Incrementing{}).NewId)
+		require.NoError(t, err)
+		diagnostics := []string{}
+		for _, diagnostic := range AnalyzePlaceholders(doc) {
+			diagnostics = append(diagnostics, diagnostic.String())
+		}
+		return diagnostics
+	}
+
+	t.Run("accepts placeholders matching the columns", func(t *testing.T) {
+		require.Empty(t, analyze(`Feature: Placeholders
+  Scenario Outline: <name>
+    Given <count> cukes
+      | <color> |
+    And a doc string
+      """<type>
+      a <size> cuke
+      """
+
+    Examples:
+      | name | count | color | type | size |
+      | a    | 1     | red   | text | big  |
+`))
+	})
+
+	t.Run("reports placeholders without columns and unused columns", func(t *testing.T) {
+		require.Equal(t, []string{
+			`(3:11): <count> is not a column of the examples at line 9`,
+			`(5:9): <size> is not a column of the examples at line 9`,
+			`(5:9): <size> is not a column of the examples at line 13`,
+			`(10:9): column "cont" of the examples is not used in the scenario outline`,
+			`(10:16): column "colour" of the examples is not used in the scenario outline`,
+		}, analyze(`Feature: Placeholders
+  Scenario Outline: cukes
+    Given <count> cukes
+      """
+      a <size> cuke
+      """
+
+    @one
+    Examples:
+      | cont | colour |
+      | 1    | red    |
+
+    Examples:
+      | count |
+      | 2     |
+`))
+	})
+
+	t.Run("ignores comparisons and scenarios without examples", func(t *testing.T) {
+		require.Empty(t, analyze(`Feature: Placeholders
+  Scenario: plain
+    Given <not a placeholder>
+
+  Scenario Outline: comparison
+    Given 1 < 2 > 0 for <a>
+
+    Examples:
+      | a |
+      | 1 |
+`))
+	})
+}
+
+func TestAnalyzeExamples(t *testing.T) {
+	doc, err := ParseGherkinDocument(strings.NewReader(`Feature: Examples
+  Scenario Outline: cukes
+    Given <count> <color> cukes
+
+    Examples:
+      | count | color | ripe  |
+      | 1     | red   | true  |
+      | 2.5   | green |       |
+      | many  | 3     | false |
+      | 4     | blue  | true  |
+`), (&messages.Incrementing{}).NewId)
+	require.NoError(t, err)
+	examples := doc.Feature.Children[0].GetScenario().Examples[0]
+
+	t.Run("infers the types of values", func(t *testing.T) {
+		require.Equal(t, ValueTypeInteger, InferValueType("-42"))
+		require.Equal(t, ValueTypeDecimal, InferValueType("1.5e3"))
+		require.Equal(t, ValueTypeBoolean, InferValueType("False"))
+		require.Equal(t, ValueTypeText, InferValueType("NaN"))
+		require.Equal(t, ValueTypeEmpty, InferValueType(""))
+	})
+
+	t.Run("infers the types of columns", func(t *testing.T) {
+		require.Equal(t, []ValueType{ValueTypeText, ValueTypeText, ValueTypeBoolean}, ColumnTypes(examples))
+		numbers := &messages.GherkinDocument_Feature_Scenario_Examples{
+			TableHeader: examples.TableHeader,
+			TableBody:   examples.TableBody[:2],
+		}
+		require.Equal(t, []ValueType{ValueTypeDecimal, ValueTypeText, ValueTypeBoolean}, ColumnTypes(numbers))
+	})
+}
+
+func TestAnalyzeExamplesDiagnostics(t *testing.T) {
+	doc, err := ParseGherkinDocument(strings.NewReader(`Feature: Examples
+  Scenario Outline: cukes
+    Given <count> <color> cukes
+
+    Examples:
+      | count | color |
+      | 1     | red   |
+      | 2.5   | green |
+      | many  | 3     |
+      | 4     | blue  |
+`), (&messages.Incrementing{}).NewId)
+	require.NoError(t, err)
+	diagnostics := func(matchStep StepMatcher) []string {
+		diagnostics := []string{}
+		for _, diagnostic := range AnalyzeExamples(doc, matchStep) {
+			diagnostics = append(diagnostics, diagnostic.String())
+		}
+		return diagnostics
+	}
+
+	t.Run("reports values with another type than most of the column", func(t *testing.T) {
+		require.Equal(t, []string{
+			`(9:9): "many" in column "count" is text, most values are decimal`,
+			`(9:17): "3" in column "color" is decimal, most values are text`,
+		}, diagnostics(nil))
+	})
+
+	t.Run("reports rows making steps not match", func(t *testing.T) {
+		cukes := regexp.MustCompile(`^(-?\d+(?:\.\d+)?) (\w+) cukes$`)
+		require.Equal(t, []string{
+			`(9:7): step "many 3 cukes" at line 3 does not match a step definition with the values of this row`,
+			`(9:9): "many" in column "count" is text, most values are decimal`,
+			`(9:17): "3" in column "color" is decimal, most values are text`,
+		}, diagnostics(cukes.MatchString))
+	})
+
+	t.Run("leaves undefined steps alone", func(t *testing.T) {
+		require.Len(t, diagnostics(func(string) bool { return false }), 2)
+	})
+
+	t.Run("stops matching steps when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		matched := 0
+		diagnostics, err := AnalyzeExamplesContext(ctx, doc, func(string) bool {
+			matched++
+			cancel()
+			return true
+		})
+		require.Equal(t, context.Canceled, err)
+		require.Nil(t, diagnostics)
+		require.Equal(t, 1, matched)
+	})
+}
@@ -386,7 +386,7 @@ func ensureCellCount(rows []*messages.GherkinDocument_Feature_TableRow) error {
 			return &parseError{"inconsistent cell count within the table", &Location{
 				Line:   int(rows[i].Location.Line),
 				Column: int(rows[i].Location.Column),
-			}}
+			}, InconsistentCellCountCode}
 		}
 	}
 	return nil
@@ -0,0 +1,121 @@
+package gherkin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/cucumber/messages-go/v13"
+	gio "github.com/gogo/protobuf/io"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// DocumentCache keeps parsed documents and their pickles in a directory,
+// so that tools parsing the same files over and over can skip the files
+// that haven't changed. Entries are keyed by a hash of the source, and are
+// kept in a subdirectory for every version of the parser, which can be
+// deleted when the version isn't used any more.
+//
+// Documents and pickles loaded from the cache keep the ids they were
+// created with, so the ids must be unique across runs, like those of
+// messages.UUID.
+type DocumentCache struct {
+	dir string
+}
+
+func NewDocumentCache(dir string) *DocumentCache {
+	return &DocumentCache{dir: filepath.Join(dir, version)}
+}
+
+// Parse parses a document and compiles its pickles, or loads both from the
+// cache. Documents with parse errors aren't cached. Failing to read or
+// write the cache only makes it parse again.
+func (c *DocumentCache) Parse(data []byte, uri string, language string, newId func() string) (*messages.GherkinDocument, []*messages.Pickle, error) {
+	path := filepath.Join(c.dir, c.key(data, uri, language))
+	if doc, pickles, err := c.read(path); err == nil {
+		return doc, pickles, nil
+	}
+
+	doc, err := ParseGherkinDocumentForLanguage(bytes.NewReader(data), language, newId)
+	if err != nil {
+		return doc, nil, err
+	}
+	doc.Uri = uri
+	pickles := Pickles(*doc, uri, newId)
+	c.write(path, doc, pickles)
+	return doc, pickles, nil
+}
+
+func (c *DocumentCache) key(data []byte, uri string, language string) string {
+	hash := sha256.New()
+	hash.Write([]byte(uri))
+	hash.Write([]byte{0})
+	hash.Write([]byte(language))
+	hash.Write([]byte{0})
+	hash.Write(data)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (c *DocumentCache) read(path string) (*messages.GherkinDocument, []*messages.Pickle, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	reader := gio.NewDelimitedReader(file, math.MaxInt32)
+	var doc *messages.GherkinDocument
+	pickles := make([]*messages.Pickle, 0)
+	for {
+		envelope := &messages.Envelope{}
+		err := reader.ReadMsg(envelope)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if envelope.GetGherkinDocument() != nil {
+			doc = envelope.GetGherkinDocument()
+		}
+		if envelope.GetPickle() != nil {
+			pickles = append(pickles, envelope.GetPickle())
+		}
+	}
+	if doc == nil {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	return doc, pickles, nil
+}
+
+// write writes to a temporary file first, so that concurrent readers
+// never see half written entries
+func (c *DocumentCache) write(path string, doc *messages.GherkinDocument, pickles []*messages.Pickle) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile(c.dir, "tmp-")
+	if err != nil {
+		return err
+	}
+	writer := gio.NewDelimitedWriter(file)
+	err = writer.WriteMsg(&messages.Envelope{Message: &messages.Envelope_GherkinDocument{GherkinDocument: doc}})
+	for _, pickle := range pickles {
+		if err == nil {
+			err = writer.WriteMsg(&messages.Envelope{Message: &messages.Envelope_Pickle{Pickle: pickle}})
+		}
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package gherkin
+
+import (
+	"github.com/cucumber/messages-go/v13"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDocumentCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gherkin-cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ids := 0
+	newId := func() string {
+		ids++
+		return messages.UUID{}.NewId()
+	}
+	data := []byte("Feature: Cached\n  Scenario: a\n    Given a\n")
+
+	t.Run("loads unchanged documents from the cache", func(t *testing.T) {
+		cache := NewDocumentCache(dir)
+		doc, pickles, err := cache.Parse(data, "cached.feature", DEFAULT_DIALECT, newId)
+		require.NoError(t, err)
+		require.Equal(t, "cached.feature", doc.Uri)
+		require.Len(t, pickles, 1)
+
+		ids = 0
+		cachedDoc, cachedPickles, err := cache.Parse(data, "cached.feature", DEFAULT_DIALECT, newId)
+		require.NoError(t, err)
+		require.Equal(t, 0, ids)
+		// Empty slices come back as nil, so compare the text representations
+		require.Equal(t, doc.String(), cachedDoc.String())
+		require.Len(t, cachedPickles, 1)
+		require.Equal(t, pickles[0].String(), cachedPickles[0].String())
+	})
+
+	t.Run("parses changed documents", func(t *testing.T) {
+		ids = 0
+		doc, _, err := NewDocumentCache(dir).Parse(append(data, "    And b\n"...), "cached.feature", DEFAULT_DIALECT, newId)
+		require.NoError(t, err)
+		require.NotEqual(t, 0, ids)
+		require.Len(t, doc.Feature.Children[0].GetScenario().Steps, 2)
+	})
+
+	t.Run("does not cache parse errors", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			_, _, err := NewDocumentCache(dir).Parse([]byte("Nonsense\n"), "bad.feature", DEFAULT_DIALECT, newId)
+			require.Error(t, err)
+		}
+	})
+
+	t.Run("parses again when the cache is corrupt", func(t *testing.T) {
+		paths, err := filepath.Glob(filepath.Join(dir, version, "*"))
+		require.NoError(t, err)
+		require.Len(t, paths, 2)
+		for _, path := range paths {
+			require.NoError(t, ioutil.WriteFile(path, []byte("garbage"), 0644))
+		}
+
+		ids = 0
+		doc, _, err := NewDocumentCache(dir).Parse(data, "cached.feature", DEFAULT_DIALECT, newId)
+		require.NoError(t, err)
+		require.NotEqual(t, 0, ids)
+		require.Equal(t, "Cached", doc.Feature.Name)
+	})
+}
@@ -0,0 +1,18 @@
+package gherkin
+
+import (
+	"context"
+)
+
+// contextScanner fails once its context is done, which stops the parser
+type contextScanner struct {
+	ctx     context.Context
+	scanner Scanner
+}
+
+func (s *contextScanner) Scan() (line *Line, atEof bool, err error) {
+	if err := s.ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	return s.scanner.Scan()
+}
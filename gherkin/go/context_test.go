@@ -0,0 +1,38 @@
+package gherkin
+
+import (
+	"context"
+	"github.com/cucumber/messages-go/v13"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestParseOptionsContext(t *testing.T) {
+	text := "Feature: a\n  Scenario: b\n    Given b\n"
+
+	t.Run("parses a document", func(t *testing.T) {
+		doc, err := ParseGherkinDocumentWithOptions(strings.NewReader(text), (&messages.Incrementing{}).NewId, ParseOptions{Context: context.Background()})
+		require.NoError(t, err)
+		require.Equal(t, "a", doc.Feature.Name)
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		reader := &cancellingReader{reader: strings.NewReader(text), cancel: cancel}
+		doc, err := ParseGherkinDocumentWithOptions(reader, (&messages.Incrementing{}).NewId, ParseOptions{Context: ctx})
+		require.Equal(t, context.Canceled, err)
+		require.Nil(t, doc)
+	})
+}
+
+// cancellingReader cancels a context once it has been read from
+type cancellingReader struct {
+	reader *strings.Reader
+	cancel context.CancelFunc
+}
+
+func (r *cancellingReader) Read(p []byte) (int, error) {
+	defer r.cancel()
+	return r.reader.Read(p)
+}
@@ -0,0 +1,72 @@
+package gherkin
+
+import (
+	"strings"
+)
+
+// continuationScanner joins lines ending with a backslash with the next
+// line, so long steps can be wrapped:
+//
+//	Given a step that is \
+//	  wrapped over two lines
+//
+// is the step "a step that is \\\n  wrapped over two lines", which Cucumber
+// Expressions that fold whitespace match like a step on one line. Lines of
+// doc strings, tables and comments are not joined.
+type continuationScanner struct {
+	scanner Scanner
+	// docStringSeparator is the separator of the doc string the lines are
+	// in, if any
+	docStringSeparator string
+	// pending is the end of the file, when it came after a line ending
+	// with a backslash
+	pending *Line
+}
+
+func (s *continuationScanner) Scan() (line *Line, atEof bool, err error) {
+	if s.pending != nil {
+		line, s.pending = s.pending, nil
+		return line, true, nil
+	}
+	line, atEof, err = s.scanner.Scan()
+	if err != nil || atEof {
+		return line, atEof, err
+	}
+	s.trackDocString(line)
+	for s.docStringSeparator == "" && continues(line) {
+		next, nextAtEof, err := s.scanner.Scan()
+		if err != nil {
+			return nil, false, err
+		}
+		if nextAtEof {
+			s.pending = next
+			break
+		}
+		text := line.LineText + "\n" + next.LineText
+		line = &Line{text, line.LineNumber, strings.TrimLeft(text, " \t"), false}
+	}
+	return line, false, nil
+}
+
+// trackDocString tracks the doc string separators like the matcher, as
+// lines in doc strings are kept as they are
+func (s *continuationScanner) trackDocString(line *Line) {
+	switch {
+	case s.docStringSeparator != "":
+		if line.StartsWith(s.docStringSeparator) {
+			s.docStringSeparator = ""
+		}
+	case line.StartsWith(DOCSTRING_SEPARATOR):
+		s.docStringSeparator = DOCSTRING_SEPARATOR
+	case line.StartsWith(DOCSTRING_ALTERNATIVE_SEPARATOR):
+		s.docStringSeparator = DOCSTRING_ALTERNATIVE_SEPARATOR
+	}
+}
+
+// continues tells if a line continues on the next line
+func continues(line *Line) bool {
+	if line.StartsWith(COMMENT_PREFIX) || line.StartsWith(string(TABLE_CELL_SEPARATOR)) {
+		return false
+	}
+	return strings.HasSuffix(strings.TrimRight(line.TrimmedLineText, " \t"), string(ESCAPE_CHAR))
+}
@@ -0,0 +1,43 @@
+package gherkin
+
+import (
+	"github.com/cucumber/messages-go/v13"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestParseOptionsLineContinuation(t *testing.T) {
+	parse := func(text string, lineContinuation bool) (*messages.GherkinDocument, error) {
+		return ParseGherkinDocumentWithOptions(strings.NewReader(text), (&messages.Incrementing{}).NewId, ParseOptions{LineContinuation: lineContinuation})
+	}
+
+	t.Run("joins wrapped steps", func(t *testing.T) {
+		text := "Feature: a\n  Scenario: b\n    Given a step that is \\\n      wrapped over two lines\n    Then it is one step\n"
+		doc, err := parse(text, true)
+		require.NoError(t, err)
+		steps := doc.Feature.Children[0].GetScenario().Steps
+		require.Len(t, steps, 2)
+		require.Equal(t, "a step that is \\\n      wrapped over two lines", steps[0].Text)
+		require.Equal(t, uint32(3), steps[0].Location.Line)
+		require.Equal(t, uint32(5), steps[1].Location.Line)
+
+		_, err = parse(text, false)
+		require.Error(t, err)
+	})
+
+	t.Run("keeps doc strings and tables", func(t *testing.T) {
+		text := "Feature: a\n  Scenario: b\n    Given a doc string\n      \"\"\"\n      C:\\\n      \"\"\"\n    And a table\n      | \\ |\n      | a |\n"
+		doc, err := parse(text, true)
+		require.NoError(t, err)
+		steps := doc.Feature.Children[0].GetScenario().Steps
+		require.Equal(t, "C:\\", steps[0].GetDocString().Content)
+		require.Len(t, steps[1].GetDataTable().Rows, 2)
+	})
+
+	t.Run("keeps a backslash at the end of the file", func(t *testing.T) {
+		doc, err := parse("Feature: a\n  Scenario: b\n    Given c \\", true)
+		require.NoError(t, err)
+		require.Equal(t, "c \\", doc.Feature.Children[0].GetScenario().Steps[0].Text)
+	})
+}
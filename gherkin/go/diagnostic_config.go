@@ -0,0 +1,84 @@
+package gherkin
+
+import (
+	"github.com/cucumber/messages-go/v13"
+	"regexp"
+	"strings"
+)
+
+// Severity is how serious a diagnostic is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+	// SeverityOff suppresses diagnostics
+	SeverityOff Severity = "off"
+)
+
+// DiagnosticConfig changes the severity of diagnostics by code, such as
+// {"severities": {"GH103": "info", "GH101": "off"}} in JSON. Cucumber
+// Expressions read the same JSON, so a project can have one config for
+// both.
+type DiagnosticConfig struct {
+	Severities map[ErrorCode]Severity `json:"severities"`
+}
+
+var DISABLE_COMMENT_REGEXP = regexp.MustCompile(`^\s*#\s*cucumber-lint:\s*disable=(\S+)\s*$`)
+
+// Apply returns the diagnostics of a document with the severities of the
+// config, without the ones it turns off and the ones disabled by comments
+// of the document. A comment such as
+//
+//	# cucumber-lint: disable=GH101,GH103
+//
+// disables diagnostics with these codes on the first line below it that
+// isn't a comment, or in the whole document when it is above the feature.
+func (c DiagnosticConfig) Apply(gherkinDocument *messages.GherkinDocument, diagnostics []Diagnostic) []Diagnostic {
+	disabled := disabledCodes(gherkinDocument)
+	var result []Diagnostic
+	for _, diagnostic := range diagnostics {
+		if disabled[0][diagnostic.Code] || disabled[diagnostic.Location.Line][diagnostic.Code] {
+			continue
+		}
+		if severity, ok := c.Severities[diagnostic.Code]; ok {
+			diagnostic.Severity = severity
+		}
+		if diagnostic.Severity == SeverityOff {
+			continue
+		}
+		result = append(result, diagnostic)
+	}
+	return result
+}
+
+// disabledCodes returns the codes disabled by the comments of a document by
+// line, where line 0 is the whole document
+func disabledCodes(gherkinDocument *messages.GherkinDocument) map[uint32]map[ErrorCode]bool {
+	commentLines := map[uint32]bool{}
+	for _, comment := range gherkinDocument.Comments {
+		commentLines[comment.Location.Line] = true
+	}
+	disabled := map[uint32]map[ErrorCode]bool{}
+	for _, comment := range gherkinDocument.Comments {
+		match := DISABLE_COMMENT_REGEXP.FindStringSubmatch(comment.Text)
+		if match == nil {
+			continue
+		}
+		line := comment.Location.Line + 1
+		for commentLines[line] {
+			line++
+		}
+		if gherkinDocument.Feature == nil || comment.Location.Line < gherkinDocument.Feature.Location.Line {
+			line = 0
+		}
+		if disabled[line] == nil {
+			disabled[line] = map[ErrorCode]bool{}
+		}
+		for _, code := range strings.Split(match[1], ",") {
+			disabled[line][ErrorCode(code)] = true
+		}
+	}
+	return disabled
+}
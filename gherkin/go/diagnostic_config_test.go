@@ -0,0 +1,87 @@
+package gherkin
+
+import (
+	"encoding/json"
+	"github.com/cucumber/messages-go/v13"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestDiagnosticConfig(t *testing.T) {
+	analyze := func(config DiagnosticConfig, text string) []string {
+		doc, err := ParseGherkinDocument(strings.NewReader(text), (&messages.Incrementing{}).NewId)
+		require.NoError(t, err)
+		diagnostics := append(AnalyzePlaceholders(doc), AnalyzeExamples(doc, nil)...)
+		result := []string{}
+		for _, diagnostic := range config.Apply(doc, diagnostics) {
+			result = append(result, string(diagnostic.Severity)+" "+string(diagnostic.Code)+" "+diagnostic.String())
+		}
+		return result
+	}
+
+	t.Run("keeps the diagnostics without a config", func(t *testing.T) {
+		require.Equal(t, []string{
+			`warning GH101 (5:17): column "color" of the examples is not used in the scenario outline`,
+			`warning GH103 (7:9): "one" in column "count" is text, most values are decimal`,
+		}, analyze(DiagnosticConfig{}, `Feature: Config
+  Scenario Outline: a
+    Given <count> cukes
+    Examples:
+      | count | color |
+      | 1     | red   |
+      | one   | blue  |
+      | 2     | green |
+`))
+	})
+
+	t.Run("changes severities and turns diagnostics off", func(t *testing.T) {
+		var config DiagnosticConfig
+		require.NoError(t, json.Unmarshal([]byte(`{"severities": {"GH101": "off", "GH103": "error"}}`), &config))
+		require.Equal(t, []string{
+			`error GH103 (7:9): "one" in column "count" is text, most values are decimal`,
+		}, analyze(config, `Feature: Config
+  Scenario Outline: a
+    Given <count> cukes
+    Examples:
+      | count | color |
+      | 1     | red   |
+      | one   | blue  |
+      | 2     | green |
+`))
+	})
+
+	t.Run("disables diagnostics on the line below a comment", func(t *testing.T) {
+		require.Equal(t, []string{
+			`warning GH103 (11:9): "two" in column "count" is text, most values are decimal`,
+		}, analyze(DiagnosticConfig{}, `Feature: Config
+  Scenario Outline: a
+    Given <count> cukes
+    Examples:
+      # cucumber-lint: disable=GH101
+      | count | color |
+      | 1     | red   |
+      # cucumber-lint: disable=GH102,GH103
+      # one is spelled out on purpose
+      | one   | blue  |
+      | two   | green |
+      | 2     | green |
+      | 3     | green |
+`))
+	})
+
+	t.Run("disables diagnostics in the whole document with a comment above the feature", func(t *testing.T) {
+		require.Equal(t, []string{
+			`warning GH103 (8:9): "one" in column "count" is text, most values are decimal`,
+		}, analyze(DiagnosticConfig{}, `# cucumber-lint: disable=GH101
+Feature: Config
+  Scenario Outline: a
+    Given <count> cukes
+    Examples:
+      | count | color |
+      | 1     | red   |
+      | one   | blue  |
+      | 2     | green |
+`))
+	})
+}
@@ -49,3 +49,37 @@ type gherkinDialectMap map[string]*GherkinDialect
 func (g gherkinDialectMap) GetDialect(language string) *GherkinDialect {
 	return g[language]
 }
+
+// NewGherkinDialectProvider returns a provider with the builtin dialects
+// and the given custom dialects, which replace builtin dialects with the
+// same language.
+func NewGherkinDialectProvider(dialects ...*GherkinDialect) GherkinDialectProvider {
+	provider := gherkinDialectMap{}
+	for language, dialect := range buildinDialects {
+		provider[language] = dialect
+	}
+	for _, dialect := range dialects {
+		provider[dialect.Language] = dialect
+	}
+	return provider
+}
+
+// WithKeywords returns a copy of the dialect for another language, with
+// additional keywords. The keys of keywords are those of Keywords, such as
+// "given" or "scenario". Like the builtin ones, step keywords end with the
+// space that separates them from the step text.
+func (g *GherkinDialect) WithKeywords(language string, keywords map[string][]string) *GherkinDialect {
+	dialect := &GherkinDialect{
+		Language: language,
+		Name:     g.Name,
+		Native:   g.Native,
+		Keywords: map[string][]string{},
+	}
+	for key, values := range g.Keywords {
+		dialect.Keywords[key] = append([]string{}, values...)
+	}
+	for key, values := range keywords {
+		dialect.Keywords[key] = append(dialect.Keywords[key], values...)
+	}
+	return dialect
+}
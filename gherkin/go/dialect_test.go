@@ -0,0 +1,49 @@
+package gherkin
+
+import (
+	"github.com/cucumber/messages-go/v13"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestCustomDialects(t *testing.T) {
+	acme := GherkinDialectsBuildin().GetDialect("en").WithKeywords("en-acme", map[string][]string{
+		"feature": {"Capability"},
+		"given":   {"Assuming "},
+	})
+	provider := NewGherkinDialectProvider(acme)
+
+	t.Run("parses keyword aliases of a custom dialect", func(t *testing.T) {
+		text := "Capability: Aliases\n  Scenario: a\n    Assuming an alias\n    Given a keyword\n"
+		doc, err := ParseGherkinDocumentWithOptions(strings.NewReader(text), (&messages.Incrementing{}).NewId, ParseOptions{Language: "en-acme", Dialects: provider})
+		require.NoError(t, err)
+		require.Equal(t, "en-acme", doc.Feature.Language)
+		require.Equal(t, "Capability", doc.Feature.Keyword)
+		steps := doc.Feature.Children[0].GetScenario().Steps
+		require.Equal(t, "Assuming ", steps[0].Keyword)
+		require.Equal(t, "Given ", steps[1].Keyword)
+
+		pickles := Pickles(*doc, "aliases.feature", (&messages.Incrementing{}).NewId)
+		require.Equal(t, "en-acme", pickles[0].Language)
+	})
+
+	t.Run("selects a custom dialect with a language header", func(t *testing.T) {
+		text := "# language: en-acme\nCapability: Aliases\n"
+		doc, err := ParseGherkinDocumentWithOptions(strings.NewReader(text), (&messages.Incrementing{}).NewId, ParseOptions{Dialects: provider})
+		require.NoError(t, err)
+		require.Equal(t, "en-acme", doc.Feature.Language)
+	})
+
+	t.Run("does not change the dialect it was copied from", func(t *testing.T) {
+		require.NotContains(t, GherkinDialectsBuildin().GetDialect("en").FeatureKeywords(), "Capability")
+		_, err := ParseGherkinDocument(strings.NewReader("Capability: Aliases\n"), (&messages.Incrementing{}).NewId)
+		require.Error(t, err)
+	})
+
+	t.Run("reports the language of builtin dialects other than English", func(t *testing.T) {
+		doc, err := ParseGherkinDocumentForLanguage(strings.NewReader("Fonctionnalité: a\n"), "fr", (&messages.Incrementing{}).NewId)
+		require.NoError(t, err)
+		require.Equal(t, "fr", doc.Feature.Language)
+	})
+}
@@ -0,0 +1,107 @@
+package gherkin
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// newDecodingReader returns a reader converting feature files to UTF-8.
+// A UTF-8 byte order mark is removed. UTF-16 is recognized by its byte order
+// mark, or by the zero bytes of the ASCII characters that feature files
+// start with, and is transcoded.
+func newDecodingReader(r io.Reader) io.Reader {
+	in := bufio.NewReader(r)
+	start, _ := in.Peek(3)
+	switch {
+	case len(start) >= 3 && start[0] == 0xEF && start[1] == 0xBB && start[2] == 0xBF:
+		in.Discard(3)
+		return in
+	case len(start) >= 2 && start[0] == 0xFF && start[1] == 0xFE:
+		in.Discard(2)
+		return &utf16Reader{r: in, order: binary.LittleEndian}
+	case len(start) >= 2 && start[0] == 0xFE && start[1] == 0xFF:
+		in.Discard(2)
+		return &utf16Reader{r: in, order: binary.BigEndian}
+	case len(start) >= 2 && start[0] != 0 && start[1] == 0:
+		return &utf16Reader{r: in, order: binary.LittleEndian}
+	case len(start) >= 2 && start[0] == 0 && start[1] != 0:
+		return &utf16Reader{r: in, order: binary.BigEndian}
+	}
+	return in
+}
+
+type utf16Reader struct {
+	r       *bufio.Reader
+	order   binary.ByteOrder
+	pending []byte
+	err     error
+}
+
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	for len(u.pending) < len(p) && u.err == nil {
+		var r rune
+		r, u.err = u.readRune()
+		if u.err == nil {
+			var encoded [utf8.UTFMax]byte
+			u.pending = append(u.pending, encoded[:utf8.EncodeRune(encoded[:], r)]...)
+		}
+	}
+	n := copy(p, u.pending)
+	u.pending = u.pending[n:]
+	if n == 0 {
+		return 0, u.err
+	}
+	return n, nil
+}
+
+func (u *utf16Reader) readRune() (rune, error) {
+	first, err := u.readUnit()
+	if err != nil {
+		return 0, err
+	}
+	if !utf16.IsSurrogate(first) {
+		return first, nil
+	}
+	second, err := u.readUnit()
+	if err == io.EOF {
+		err = errors.New("invalid UTF-16 encoding: unpaired surrogate at the end of the file")
+	}
+	if err != nil {
+		return 0, err
+	}
+	if r := utf16.DecodeRune(first, second); r != utf8.RuneError {
+		return r, nil
+	}
+	return 0, errors.New("invalid UTF-16 encoding: unpaired surrogate")
+}
+
+func (u *utf16Reader) readUnit() (rune, error) {
+	var unit [2]byte
+	_, err := io.ReadFull(u.r, unit[:])
+	if err == io.ErrUnexpectedEOF {
+		return 0, errors.New("invalid UTF-16 encoding: odd number of bytes")
+	}
+	return rune(u.order.Uint16(unit[:])), err
+}
+
+// invalidUtf8Column returns the column of the first byte in s that isn't
+// valid UTF-8, or 0 when s is valid
+func invalidUtf8Column(s string) int {
+	if utf8.ValidString(s) {
+		return 0
+	}
+	column := 1
+	for i, r := range s {
+		if r == utf8.RuneError {
+			if _, size := utf8.DecodeRuneInString(s[i:]); size == 1 {
+				return column
+			}
+		}
+		column++
+	}
+	return column
+}
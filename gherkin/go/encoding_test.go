@@ -0,0 +1,60 @@
+package gherkin
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/cucumber/messages-go/v13"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"unicode/utf16"
+)
+
+const encodingGherkinText = "Feature: Café 🥒\n  Scenario: ñ\n    Given ü\n"
+
+func TestEncodings(t *testing.T) {
+	parse := func(data []byte) (*messages.GherkinDocument, error) {
+		return ParseGherkinDocument(bytes.NewReader(data), (&messages.Incrementing{}).NewId)
+	}
+	utf16Bytes := func(order binary.ByteOrder, s string) []byte {
+		var buf bytes.Buffer
+		for _, unit := range utf16.Encode([]rune(s)) {
+			binary.Write(&buf, order, unit)
+		}
+		return buf.Bytes()
+	}
+
+	for name, data := range map[string][]byte{
+		"UTF-8":                []byte(encodingGherkinText),
+		"UTF-8 with BOM":       append([]byte{0xEF, 0xBB, 0xBF}, encodingGherkinText...),
+		"UTF-16LE with BOM":    utf16Bytes(binary.LittleEndian, "\uFEFF"+encodingGherkinText),
+		"UTF-16BE with BOM":    utf16Bytes(binary.BigEndian, "\uFEFF"+encodingGherkinText),
+		"UTF-16LE without BOM": utf16Bytes(binary.LittleEndian, encodingGherkinText),
+		"UTF-16BE without BOM": utf16Bytes(binary.BigEndian, encodingGherkinText),
+	} {
+		data := data
+		t.Run("parses "+name, func(t *testing.T) {
+			doc, err := parse(data)
+			require.NoError(t, err)
+			require.Equal(t, "Café 🥒", doc.Feature.Name)
+			require.Equal(t, uint32(1), doc.Feature.Location.Column)
+			require.Equal(t, "ü", doc.Feature.Children[0].GetScenario().Steps[0].Text)
+		})
+	}
+
+	t.Run("reports invalid UTF-8 with its location", func(t *testing.T) {
+		_, err := parse([]byte("Feature: a\n  Scenario: b\xff\n"))
+		require.EqualError(t, err, "Parser errors:\n(2:14): invalid UTF-8 encoding")
+	})
+
+	t.Run("reports UTF-16 with an odd number of bytes", func(t *testing.T) {
+		data := utf16Bytes(binary.LittleEndian, "\uFEFF"+encodingGherkinText)
+		_, err := parse(data[:len(data)-1])
+		require.EqualError(t, err, "Parser errors:\ninvalid UTF-16 encoding: odd number of bytes")
+	})
+
+	t.Run("reports unpaired UTF-16 surrogates", func(t *testing.T) {
+		data := append(utf16Bytes(binary.LittleEndian, "Feature: a"), 0x3E, 0xD8, 0x41, 0x00)
+		_, err := parse(data)
+		require.EqualError(t, err, "Parser errors:\ninvalid UTF-16 encoding: unpaired surrogate")
+	})
+}
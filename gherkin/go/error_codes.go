@@ -0,0 +1,55 @@
+package gherkin
+
+import (
+	"errors"
+)
+
+// ErrorCode identifies the kind of a parse error or a diagnostic, so tools
+// can link it to its documentation or suppress it. Codes don't change
+// between releases, unlike messages. Codes of diagnostics start at GH101,
+// and of parse errors at GH201.
+type ErrorCode string
+
+const (
+	UnusedExamplesColumnCode  ErrorCode = "GH101"
+	UndefinedPlaceholderCode  ErrorCode = "GH102"
+	MixedValueTypesCode       ErrorCode = "GH103"
+	UnmatchedExamplesRowCode  ErrorCode = "GH104"
+	UnexpectedTokenCode       ErrorCode = "GH201"
+	InvalidTagCode            ErrorCode = "GH202"
+	InconsistentCellCountCode ErrorCode = "GH203"
+	UnexpectedEOFCode         ErrorCode = "GH204"
+	LanguageNotSupportedCode  ErrorCode = "GH205"
+	InvalidEncodingCode       ErrorCode = "GH206"
+	LimitExceededCode         ErrorCode = "GH207"
+)
+
+// ErrorCodeOf returns the code of a parse error, or of the first one of
+// the errors of a document. It returns "" for other errors, such as the
+// ones of reading a file.
+func ErrorCodeOf(err error) ErrorCode {
+	var pe *parseError
+	if errors.As(err, &pe) {
+		return pe.code
+	}
+	return ""
+}
+
+// ErrorCodes returns the codes of every parse error of a document
+func ErrorCodes(err error) []ErrorCode {
+	var codes []ErrorCode
+	if errs, ok := err.(parseErrors); ok {
+		for _, err := range errs {
+			codes = append(codes, ErrorCodeOf(err))
+		}
+		return codes
+	}
+	if code := ErrorCodeOf(err); code != "" {
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+func (pe parseErrors) Unwrap() []error {
+	return pe
+}
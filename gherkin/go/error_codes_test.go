@@ -0,0 +1,66 @@
+package gherkin
+
+import (
+	"errors"
+	"github.com/cucumber/messages-go/v13"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestErrorCodes(t *testing.T) {
+	parse := func(text string) error {
+		_, err := ParseGherkinDocument(strings.NewReader(text), (&messages.Incrementing{}).NewId)
+		return err
+	}
+
+	t.Run("has a code for every parse error", func(t *testing.T) {
+		err := parse(`Feature: Codes
+  Scenario: a
+    Given a table
+      | a | b |
+      | c |
+  @a b
+`)
+		require.Equal(t, []ErrorCode{InconsistentCellCountCode, InvalidTagCode, UnexpectedEOFCode}, ErrorCodes(err))
+		require.Equal(t, InconsistentCellCountCode, ErrorCodeOf(err))
+
+		require.Equal(t, []ErrorCode{UnexpectedTokenCode}, ErrorCodes(parse("Given a step\n")))
+		require.Equal(t, []ErrorCode{LanguageNotSupportedCode}, ErrorCodes(parse("# language: xx\nFeature: Codes\n")))
+	})
+
+	t.Run("keeps the codes of errors mapped to a template", func(t *testing.T) {
+		sourceMap := &SourceMap{}
+		require.Equal(t, []ErrorCode{UnexpectedTokenCode}, ErrorCodes(sourceMap.MapError(parse("Given a step\n"))))
+	})
+
+	t.Run("has no code for other errors", func(t *testing.T) {
+		require.Equal(t, ErrorCode(""), ErrorCodeOf(errors.New("boom")))
+		require.Empty(t, ErrorCodes(nil))
+	})
+
+	t.Run("has a code for every diagnostic", func(t *testing.T) {
+		doc, err := ParseGherkinDocument(strings.NewReader(`Feature: Codes
+  Scenario Outline: a
+    Given <count> cukes
+
+    Examples:
+      | count | color |
+      | 1     | red   |
+      | one   | blue  |
+      | 2     | green |
+`), (&messages.Incrementing{}).NewId)
+		require.NoError(t, err)
+		var codes []ErrorCode
+		for _, diagnostic := range AnalyzePlaceholders(doc) {
+			codes = append(codes, diagnostic.Code)
+		}
+		require.Equal(t, []ErrorCode{UnusedExamplesColumnCode}, codes)
+
+		codes = nil
+		for _, diagnostic := range AnalyzeExamples(doc, func(text string) bool { return text != "2 cukes" }) {
+			codes = append(codes, diagnostic.Code)
+		}
+		require.Equal(t, []ErrorCode{MixedValueTypesCode, UnmatchedExamplesRowCode}, codes)
+	})
+}
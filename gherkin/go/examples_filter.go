@@ -0,0 +1,109 @@
+package gherkin
+
+import (
+	"fmt"
+	"github.com/cucumber/messages-go/v13"
+	"strings"
+)
+
+// ExamplesFilter selects the pickles compiled from Examples rows with
+// certain values, e.g. to rerun only the firefox rows of an outline
+// running in several browsers.
+type ExamplesFilter map[string]string
+
+// ParseExamplesFilter parses comma separated column=value pairs, such as
+// "browser=firefox,os=linux"
+func ParseExamplesFilter(s string) (ExamplesFilter, error) {
+	filter := ExamplesFilter{}
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("expected column=value, got %q", pair)
+		}
+		filter[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return filter, nil
+}
+
+// Filter returns the pickles compiled from a row of gherkinDocument having
+// all the values of the filter. Pickles of scenarios without examples are
+// never selected.
+func (f ExamplesFilter) Filter(gherkinDocument *messages.GherkinDocument, pickles []*messages.Pickle) []*messages.Pickle {
+	rows := examplesRows(gherkinDocument)
+	selected := make([]*messages.Pickle, 0)
+	for _, pickle := range pickles {
+		values := rows.values(pickle)
+		if values == nil {
+			continue
+		}
+		matches := true
+		for column, value := range f {
+			if actual, ok := values[column]; !ok || actual != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			selected = append(selected, pickle)
+		}
+	}
+	return selected
+}
+
+// ExamplesValues returns the values of the Examples row a pickle was
+// compiled from by column, or nil if the pickle is not from a row of
+// gherkinDocument
+func ExamplesValues(gherkinDocument *messages.GherkinDocument, pickle *messages.Pickle) map[string]string {
+	return examplesRows(gherkinDocument).values(pickle)
+}
+
+type examplesRow struct {
+	header *messages.GherkinDocument_Feature_TableRow
+	row    *messages.GherkinDocument_Feature_TableRow
+}
+
+type examplesRowsById map[string]examplesRow
+
+func examplesRows(gherkinDocument *messages.GherkinDocument) examplesRowsById {
+	rows := examplesRowsById{}
+	if gherkinDocument.Feature == nil {
+		return rows
+	}
+	addScenario := func(scenario *messages.GherkinDocument_Feature_Scenario) {
+		for _, examples := range scenario.Examples {
+			for _, row := range examples.TableBody {
+				rows[row.Id] = examplesRow{examples.TableHeader, row}
+			}
+		}
+	}
+	for _, child := range gherkinDocument.Feature.Children {
+		if scenario := child.GetScenario(); scenario != nil {
+			addScenario(scenario)
+		}
+		if rule := child.GetRule(); rule != nil {
+			for _, ruleChild := range rule.Children {
+				if scenario := ruleChild.GetScenario(); scenario != nil {
+					addScenario(scenario)
+				}
+			}
+		}
+	}
+	return rows
+}
+
+func (rows examplesRowsById) values(pickle *messages.Pickle) map[string]string {
+	// The ids of pickles compiled from outlines are those of the scenario
+	// and of the row
+	if len(pickle.AstNodeIds) < 2 {
+		return nil
+	}
+	row, ok := rows[pickle.AstNodeIds[len(pickle.AstNodeIds)-1]]
+	if !ok {
+		return nil
+	}
+	values := map[string]string{}
+	for i, cell := range row.header.Cells {
+		values[cell.Value] = row.row.Cells[i].Value
+	}
+	return values
+}
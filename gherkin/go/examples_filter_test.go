@@ -0,0 +1,66 @@
+package gherkin
+
+import (
+	"github.com/cucumber/messages-go/v13"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const examplesFilterGherkinText = `Feature: Browsers
+
+  Scenario: plain
+    Given a page
+
+  Scenario Outline: <page> in <browser>
+    Given <page> in <browser>
+
+    Examples:
+      | browser | page   |
+      | firefox | home   |
+      | chrome  | home   |
+      | firefox | search |
+
+  Rule: rules
+    Scenario Outline: <browser>
+      Given <browser>
+
+      Examples:
+        | browser |
+        | firefox |
+`
+
+func TestExamplesFilter(t *testing.T) {
+	doc, err := ParseGherkinDocument(strings.NewReader(examplesFilterGherkinText), (&messages.Incrementing{}).NewId)
+	require.NoError(t, err)
+	pickles := Pickles(*doc, "browsers.feature", (&messages.Incrementing{}).NewId)
+	names := func(pickles []*messages.Pickle) []string {
+		names := []string{}
+		for _, pickle := range pickles {
+			names = append(names, pickle.Name)
+		}
+		return names
+	}
+
+	t.Run("selects pickles by example values", func(t *testing.T) {
+		filter, err := ParseExamplesFilter("browser=firefox")
+		require.NoError(t, err)
+		require.Equal(t, []string{"home in firefox", "search in firefox", "firefox"}, names(filter.Filter(doc, pickles)))
+	})
+
+	t.Run("requires all values to match", func(t *testing.T) {
+		filter, err := ParseExamplesFilter("browser=firefox, page=home")
+		require.NoError(t, err)
+		require.Equal(t, []string{"home in firefox"}, names(filter.Filter(doc, pickles)))
+	})
+
+	t.Run("returns the example values of pickles", func(t *testing.T) {
+		require.Nil(t, ExamplesValues(doc, pickles[0]))
+		require.Equal(t, map[string]string{"browser": "chrome", "page": "home"}, ExamplesValues(doc, pickles[2]))
+	})
+
+	t.Run("rejects filters without values", func(t *testing.T) {
+		_, err := ParseExamplesFilter("browser")
+		require.EqualError(t, err, `expected column=value, got "browser"`)
+	})
+}
@@ -0,0 +1,57 @@
+//go:build go1.18
+// +build go1.18
+
+package gherkin
+
+import (
+	"github.com/cucumber/messages-go/v13"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// FuzzParseGherkinDocument checks that no input crashes the parser or the
+// pickle compiler. Run it with
+//
+//	go test -fuzz FuzzParseGherkinDocument
+func FuzzParseGherkinDocument(f *testing.F) {
+	paths, err := filepath.Glob("testdata/*/*.feature")
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(string(data))
+	}
+	limits := Limits{MaxFileSize: 1 << 16, MaxTableCells: 1000, MaxScenarios: 100}
+	f.Fuzz(func(t *testing.T, data string) {
+		doc, err := ParseGherkinDocumentWithOptions(strings.NewReader(data), (&messages.Incrementing{}).NewId, ParseOptions{Limits: limits})
+		if err == nil {
+			Pickles(*doc, "fuzz.feature", (&messages.Incrementing{}).NewId)
+		}
+	})
+}
+
+// FuzzScanner checks that the scanner reports every line of its input
+func FuzzScanner(f *testing.F) {
+	f.Add("Feature: a\n  Scenario: b\r\n    Given c")
+	f.Fuzz(func(t *testing.T, data string) {
+		scanner := NewScanner(strings.NewReader(data))
+		for i := 1; ; i++ {
+			line, atEof, err := scanner.Scan()
+			if err != nil {
+				return
+			}
+			if line.LineNumber != i {
+				t.Fatalf("expected line %d, got %d", i, line.LineNumber)
+			}
+			if atEof {
+				return
+			}
+		}
+	})
+}
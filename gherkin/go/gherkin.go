@@ -2,6 +2,7 @@ package gherkin
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"github.com/cucumber/messages-go/v13"
 	"io"
@@ -74,7 +75,7 @@ func (p *parser) StopAtFirstError(b bool) {
 
 func NewScanner(r io.Reader) Scanner {
 	return &scanner{
-		s:    bufio.NewScanner(r),
+		s:    bufio.NewScanner(newDecodingReader(r)),
 		line: 0,
 	}
 }
@@ -95,6 +96,9 @@ func (t *scanner) Scan() (line *Line, atEof bool, err error) {
 	if err == nil {
 		t.line += 1
 		str := t.s.Text()
+		if column := invalidUtf8Column(str); column > 0 {
+			return nil, false, &parseError{"invalid UTF-8 encoding", &Location{Line: t.line, Column: column}, InvalidEncodingCode}
+		}
 		line = &Line{str, t.line, strings.TrimLeft(str, " \t"), atEof}
 	}
 	return
@@ -124,19 +128,79 @@ func (g *Line) StartsWith(prefix string) bool {
 }
 
 func ParseGherkinDocument(in io.Reader, newId func() string) (gherkinDocument *messages.GherkinDocument, err error) {
-	return ParseGherkinDocumentForLanguage(in, DEFAULT_DIALECT, newId)
+	return ParseGherkinDocumentWithOptions(in, newId, ParseOptions{})
 }
 
 func ParseGherkinDocumentForLanguage(in io.Reader, language string, newId func() string) (gherkinDocument *messages.GherkinDocument, err error) {
-
-	builder := NewAstBuilder(newId)
+	return ParseGherkinDocumentWithOptions(in, newId, ParseOptions{Language: language})
+}
+
+// ParseOptions change how ParseGherkinDocumentWithOptions parses a document.
+// The zero ParseOptions parse it like ParseGherkinDocument.
+type ParseOptions struct {
+	// Language is the language of documents without a # language header,
+	// DEFAULT_DIALECT when empty
+	Language string
+	// Dialects provides the dialects of the languages, such as custom
+	// dialects of NewGherkinDialectProvider, GherkinDialectsBuildin when nil
+	Dialects GherkinDialectProvider
+	// Limits stop parsing documents that are too large, see Limits
+	Limits Limits
+	// OnScenario receives every scenario and the pickles compiled from it
+	// as soon as the scenario has been parsed, instead of the document
+	// keeping it in memory. The document then has the feature, its
+	// backgrounds and rules, but no scenarios.
+	OnScenario ScenarioHandler
+	// URI is the URI of the pickles of OnScenario
+	URI string
+	// Context stops parsing with its error when it is done, such as when a
+	// language server request is cancelled
+	Context context.Context
+	// LineContinuation joins lines ending with a backslash with the next
+	// line, except in doc strings and tables, so long steps can be wrapped
+	LineContinuation bool
+}
+
+// ParseGherkinDocumentWithOptions parses a document like
+// ParseGherkinDocument, with options
+func ParseGherkinDocumentWithOptions(in io.Reader, newId func() string, options ParseOptions) (gherkinDocument *messages.GherkinDocument, err error) {
+	language := options.Language
+	if language == "" {
+		language = DEFAULT_DIALECT
+	}
+	astBuilder := NewAstBuilder(newId).(*astBuilder)
+	var builder Builder = astBuilder
+	var scanner Scanner = NewScanner(in)
+	if options.LineContinuation {
+		scanner = &continuationScanner{scanner: scanner}
+	}
+	if options.Context != nil {
+		scanner = &contextScanner{ctx: options.Context, scanner: scanner}
+	}
+	var streaming *streamingBuilder
+	if options.OnScenario != nil {
+		streaming = &streamingBuilder{astBuilder: astBuilder, scanner: scanner, uri: options.URI, handle: options.OnScenario}
+		builder, scanner = streaming, streaming
+	}
+	if options.Limits != (Limits{}) {
+		limiter := &limiter{limits: options.Limits, builder: builder, scanner: scanner}
+		builder, scanner = limiter, limiter
+	}
 	parser := NewParser(builder)
 	parser.StopAtFirstError(false)
-	matcher := NewLanguageMatcher(GherkinDialectsBuildin(), language)
-
-	scanner := NewScanner(in)
+	dialects := options.Dialects
+	if dialects == nil {
+		dialects = GherkinDialectsBuildin()
+	}
+	matcher := NewLanguageMatcher(dialects, language)
 
 	err = parser.Parse(scanner, matcher)
+	if options.Context != nil && options.Context.Err() != nil {
+		return nil, options.Context.Err()
+	}
+	if streaming != nil && streaming.err != nil {
+		return nil, streaming.err
+	}
 
-	return builder.GetGherkinDocument(), err
+	return astBuilder.GetGherkinDocument(), err
 }
@@ -0,0 +1,87 @@
+package gherkin
+
+import (
+	"fmt"
+)
+
+// Limits protects services parsing untrusted feature files from running out
+// of memory, with ParseGherkinDocumentWithOptions. Parsing stops with a parse
+// error at the first limit exceeded. Zero means unlimited.
+type Limits struct {
+	// MaxFileSize is the maximum number of bytes in the document
+	MaxFileSize int
+	// MaxTableCells is the maximum number of cells in all data tables and
+	// examples tables of the document
+	MaxTableCells int
+	// MaxScenarios is the maximum number of scenarios and scenario outlines
+	// in the document
+	MaxScenarios int
+}
+
+// limiter sits between the scanner, the parser and the builder. When the
+// builder sees too many cells or scenarios, the next scan fails, which
+// stops the parser.
+type limiter struct {
+	limits    Limits
+	scanner   Scanner
+	builder   Builder
+	size      int
+	cells     int
+	scenarios int
+	err       error
+}
+
+func (l *limiter) Scan() (line *Line, atEof bool, err error) {
+	if l.err != nil {
+		return nil, false, l.err
+	}
+	line, atEof, err = l.scanner.Scan()
+	if err != nil || atEof {
+		return line, atEof, err
+	}
+	l.size += len(line.LineText) + 1
+	if l.limits.MaxFileSize > 0 && l.size > l.limits.MaxFileSize {
+		l.err = &parseError{
+			msg:  fmt.Sprintf("file is larger than %d bytes", l.limits.MaxFileSize),
+			loc:  &Location{Line: line.LineNumber, Column: 0},
+			code: LimitExceededCode,
+		}
+		return nil, false, l.err
+	}
+	return line, atEof, err
+}
+
+func (l *limiter) Build(tok *Token) (bool, error) {
+	switch tok.Type {
+	case TokenTypeTableRow:
+		l.cells += len(tok.Items)
+		if l.limits.MaxTableCells > 0 && l.cells > l.limits.MaxTableCells {
+			l.exceeded(tok, fmt.Sprintf("more than %d table cells", l.limits.MaxTableCells))
+		}
+	case TokenTypeScenarioLine:
+		l.scenarios++
+		if l.limits.MaxScenarios > 0 && l.scenarios > l.limits.MaxScenarios {
+			l.exceeded(tok, fmt.Sprintf("more than %d scenarios", l.limits.MaxScenarios))
+		}
+	}
+	return l.builder.Build(tok)
+}
+
+func (l *limiter) exceeded(tok *Token, msg string) {
+	if l.err == nil {
+		l.err = &parseError{msg: msg, loc: tok.Location, code: LimitExceededCode}
+	}
+}
+
+func (l *limiter) StartRule(r RuleType) (bool, error) {
+	return l.builder.StartRule(r)
+}
+
+func (l *limiter) EndRule(r RuleType) (bool, error) {
+	return l.builder.EndRule(r)
+}
+
+func (l *limiter) Reset() {
+	l.builder.Reset()
+	l.size, l.cells, l.scenarios, l.err = 0, 0, 0, nil
+}
@@ -0,0 +1,56 @@
+package gherkin
+
+import (
+	"github.com/cucumber/messages-go/v13"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const limitsGherkinText = `Feature: Limits
+
+  Scenario: a
+    Given a table
+      | a | b |
+      | c | d |
+
+  Scenario Outline: b
+    Given <x>
+
+    Examples:
+      | x |
+      | 1 |
+      | 2 |
+`
+
+func TestLimits(t *testing.T) {
+	parse := func(text string, limits Limits) (*messages.GherkinDocument, error) {
+		return ParseGherkinDocumentWithOptions(strings.NewReader(text), (&messages.Incrementing{}).NewId, ParseOptions{Limits: limits})
+	}
+
+	t.Run("parses documents within the limits", func(t *testing.T) {
+		doc, err := parse(limitsGherkinText, Limits{MaxFileSize: len(limitsGherkinText), MaxTableCells: 7, MaxScenarios: 2})
+		require.NoError(t, err)
+		require.Len(t, doc.Feature.Children, 2)
+	})
+
+	t.Run("stops at the line exceeding the file size", func(t *testing.T) {
+		_, err := parse(limitsGherkinText, Limits{MaxFileSize: 30})
+		require.EqualError(t, err, "Parser errors:\n(3:0): file is larger than 30 bytes")
+	})
+
+	t.Run("stops at the row exceeding the number of table cells", func(t *testing.T) {
+		_, err := parse(limitsGherkinText, Limits{MaxTableCells: 6})
+		require.EqualError(t, err, "Parser errors:\n(14:7): more than 6 table cells")
+	})
+
+	t.Run("stops at the scenario exceeding the number of scenarios", func(t *testing.T) {
+		_, err := parse(limitsGherkinText, Limits{MaxScenarios: 1})
+		require.EqualError(t, err, "Parser errors:\n(8:3): more than 1 scenarios")
+	})
+
+	t.Run("reports lines too long to scan instead of panicking", func(t *testing.T) {
+		_, err := parse("Feature: "+strings.Repeat("x", 100000), Limits{})
+		require.EqualError(t, err, "Parser errors:\nbufio.Scanner: token too long")
+	})
+}
@@ -0,0 +1,70 @@
+package gherkin
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"github.com/cucumber/messages-go/v13"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"os/exec"
+	"path"
+	"sort"
+)
+
+// SourcesFromFS reads all .feature files of a file system, such as
+// os.DirFS, a zip.Reader, a fstest.MapFS or the file systems of
+// GitArchiveFS and HTTPArchiveFS. Their uris are their paths in fsys.
+func SourcesFromFS(fsys fs.FS) ([]*messages.Source, error) {
+	var sources []*messages.Source
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || path.Ext(name) != ".feature" {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, &messages.Source{
+			Uri:       name,
+			Data:      string(data),
+			MediaType: "text/x.cucumber.gherkin+plain",
+		})
+		return nil
+	})
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Uri < sources[j].Uri })
+	return sources, err
+}
+
+// GitArchiveFS returns the files of a git ref, such as a remote branch, of
+// the repository in dir without checking it out
+func GitArchiveFS(dir string, ref string) (fs.FS, error) {
+	cmd := exec.Command("git", "archive", "--format=zip", ref)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	archive, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git archive %s: %s %s", ref, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+}
+
+// HTTPArchiveFS downloads a zip archive, like those of branches on code
+// hosting sites. Credentials go into the headers of request.
+func HTTPArchiveFS(client *http.Client, request *http.Request) (fs.FS, error) {
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get %s: %s", request.URL, response.Status)
+	}
+	archive, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	return zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+}
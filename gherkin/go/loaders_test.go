@@ -0,0 +1,110 @@
+package gherkin
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSourcesFromFS(t *testing.T) {
+	uris := func(fsys fs.FS) []string {
+		sources, err := SourcesFromFS(fsys)
+		require.NoError(t, err)
+		uris := []string{}
+		for _, source := range sources {
+			uris = append(uris, source.Uri)
+		}
+		return uris
+	}
+	files := map[string]string{
+		"features/b.feature":     "Feature: b\n",
+		"features/a/a.feature":   "Feature: a\n",
+		"features/steps.go":      "package steps\n",
+		"features/README.md":     "# Features\n",
+		"features/c.feature.bak": "Feature: c\n",
+	}
+
+	t.Run("reads feature files from an in-memory file system", func(t *testing.T) {
+		fsys := fstest.MapFS{}
+		for name, data := range files {
+			fsys[name] = &fstest.MapFile{Data: []byte(data)}
+		}
+		sources, err := SourcesFromFS(fsys)
+		require.NoError(t, err)
+		require.Len(t, sources, 2)
+		require.Equal(t, "features/a/a.feature", sources[0].Uri)
+		require.Equal(t, "Feature: a\n", sources[0].Data)
+		require.Equal(t, "text/x.cucumber.gherkin+plain", sources[0].MediaType)
+	})
+
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	for name, data := range files {
+		w, err := writer.Create(name)
+		require.NoError(t, err)
+		w.Write([]byte(data))
+	}
+	require.NoError(t, writer.Close())
+
+	t.Run("reads feature files from an archive over HTTP", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				http.Error(w, "who are you?", http.StatusUnauthorized)
+				return
+			}
+			w.Write(archive.Bytes())
+		}))
+		defer server.Close()
+
+		request, err := http.NewRequest("GET", server.URL+"/archive.zip", nil)
+		require.NoError(t, err)
+		_, err = HTTPArchiveFS(server.Client(), request)
+		require.EqualError(t, err, "get "+server.URL+"/archive.zip: 401 Unauthorized")
+
+		request.Header.Set("Authorization", "Bearer secret")
+		fsys, err := HTTPArchiveFS(server.Client(), request)
+		require.NoError(t, err)
+		require.Equal(t, []string{"features/a/a.feature", "features/b.feature"}, uris(fsys))
+	})
+
+	t.Run("reads feature files from a git ref", func(t *testing.T) {
+		if _, err := exec.LookPath("git"); err != nil {
+			t.Skip("git is not installed")
+		}
+		dir, err := ioutil.TempDir("", "gherkin-git")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		git := func(args ...string) {
+			cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+			cmd.Dir = dir
+			output, err := cmd.CombinedOutput()
+			require.NoError(t, err, string(output))
+		}
+		git("init", "-q")
+		for name, data := range files {
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644))
+		}
+		git("add", ".")
+		git("commit", "-q", "-m", "features")
+		git("branch", "features")
+		git("rm", "-q", "features/b.feature")
+		git("commit", "-q", "-m", "remove b")
+
+		fsys, err := GitArchiveFS(dir, "features")
+		require.NoError(t, err)
+		require.Equal(t, []string{"features/a/a.feature", "features/b.feature"}, uris(fsys))
+
+		_, err = GitArchiveFS(dir, "no-such-ref")
+		require.Error(t, err)
+	})
+}
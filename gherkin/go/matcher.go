@@ -52,9 +52,9 @@ func NewLanguageMatcher(gdp GherkinDialectProvider, language string) Matcher {
 func (m *matcher) Reset() {
 	m.indentToRemove = 0
 	m.activeDocStringSeparator = ""
-	if m.lang != "en" {
+	if m.lang != m.defaultLang {
 		m.dialect = m.gdp.GetDialect(m.defaultLang)
-		m.lang = "en"
+		m.lang = m.defaultLang
 	}
 }
 
@@ -111,7 +111,7 @@ func (m *matcher) MatchTagLine(line *Line) (ok bool, token *Token, err error) {
 		if !regexp.MustCompile(`^\S+$`).MatchString(txt) {
 			location := &Location{line.LineNumber, column}
 			msg := "A tag may not contain whitespace"
-			err = &parseError{msg, location}
+			err = &parseError{msg, location, InvalidTagCode}
 			break
 		}
 		tags = append(tags, &LineSpan{column, TAG_PREFIX + txt})
@@ -265,7 +265,7 @@ func (m *matcher) MatchLanguage(line *Line) (ok bool, token *Token, err error) {
 
 		dialect := m.gdp.GetDialect(lang)
 		if dialect == nil {
-			err = &parseError{"Language not supported: " + lang, token.Location}
+			err = &parseError{"Language not supported: " + lang, token.Location, LanguageNotSupportedCode}
 		} else {
 			m.lang = lang
 			m.dialect = dialect
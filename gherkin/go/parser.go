@@ -219,8 +219,9 @@ type Location struct {
 }
 
 type parseError struct {
-	msg string
-	loc *Location
+	msg  string
+	loc  *Location
+	code ErrorCode
 }
 
 func (a *parseError) Error() string {
@@ -246,10 +247,9 @@ func (p *parser) Parse(s Scanner, m Matcher) (err error) {
 	for {
 		gl, eof, err := ctxt.scan()
 		if err != nil {
+			// there is no line to match when the scanner fails
 			ctxt.addError(err)
-			if p.stopAtFirstError {
-				break
-			}
+			break
 		}
 		state, err = ctxt.match(state, gl)
 		if err != nil {
@@ -470,11 +470,13 @@ func (ctxt *parseContext) matchAt0(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -508,11 +510,13 @@ func (ctxt *parseContext) matchAt1(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -546,11 +550,13 @@ func (ctxt *parseContext) matchAt2(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -613,11 +619,13 @@ func (ctxt *parseContext) matchAt3(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -681,11 +689,13 @@ func (ctxt *parseContext) matchAt4(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -743,11 +753,13 @@ func (ctxt *parseContext) matchAt5(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -809,11 +821,13 @@ func (ctxt *parseContext) matchAt6(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -876,11 +890,13 @@ func (ctxt *parseContext) matchAt7(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -937,11 +953,13 @@ func (ctxt *parseContext) matchAt8(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -1013,11 +1031,13 @@ func (ctxt *parseContext) matchAt9(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -1088,11 +1108,13 @@ func (ctxt *parseContext) matchAt10(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -1127,11 +1149,13 @@ func (ctxt *parseContext) matchAt11(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -1211,11 +1235,13 @@ func (ctxt *parseContext) matchAt12(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -1298,11 +1324,13 @@ func (ctxt *parseContext) matchAt13(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -1377,11 +1405,13 @@ func (ctxt *parseContext) matchAt14(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -1473,11 +1503,13 @@ func (ctxt *parseContext) matchAt15(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -1570,11 +1602,13 @@ func (ctxt *parseContext) matchAt16(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -1609,11 +1643,13 @@ func (ctxt *parseContext) matchAt17(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -1705,11 +1741,13 @@ func (ctxt *parseContext) matchAt18(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -1804,11 +1842,13 @@ func (ctxt *parseContext) matchAt19(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -1895,11 +1935,13 @@ func (ctxt *parseContext) matchAt20(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -1991,11 +2033,13 @@ func (ctxt *parseContext) matchAt21(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -2060,11 +2104,13 @@ func (ctxt *parseContext) matchAt22(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -2130,11 +2176,13 @@ func (ctxt *parseContext) matchAt23(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -2194,11 +2242,13 @@ func (ctxt *parseContext) matchAt24(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -2262,11 +2312,13 @@ func (ctxt *parseContext) matchAt25(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -2331,11 +2383,13 @@ func (ctxt *parseContext) matchAt26(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -2394,11 +2448,13 @@ func (ctxt *parseContext) matchAt27(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -2472,11 +2528,13 @@ func (ctxt *parseContext) matchAt28(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -2549,11 +2607,13 @@ func (ctxt *parseContext) matchAt29(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -2588,11 +2648,13 @@ func (ctxt *parseContext) matchAt30(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -2674,11 +2736,13 @@ func (ctxt *parseContext) matchAt31(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -2763,11 +2827,13 @@ func (ctxt *parseContext) matchAt32(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -2844,11 +2910,13 @@ func (ctxt *parseContext) matchAt33(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -2942,11 +3010,13 @@ func (ctxt *parseContext) matchAt34(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -3041,11 +3111,13 @@ func (ctxt *parseContext) matchAt35(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -3080,11 +3152,13 @@ func (ctxt *parseContext) matchAt36(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -3178,11 +3252,13 @@ func (ctxt *parseContext) matchAt37(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -3279,11 +3355,13 @@ func (ctxt *parseContext) matchAt38(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -3372,11 +3450,13 @@ func (ctxt *parseContext) matchAt39(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -3470,11 +3550,13 @@ func (ctxt *parseContext) matchAt40(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -3499,11 +3581,13 @@ func (ctxt *parseContext) matchAt42(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -3594,11 +3678,13 @@ func (ctxt *parseContext) matchAt43(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -3623,11 +3709,13 @@ func (ctxt *parseContext) matchAt44(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -3696,11 +3784,13 @@ func (ctxt *parseContext) matchAt45(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -3725,11 +3815,13 @@ func (ctxt *parseContext) matchAt46(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -3818,11 +3910,13 @@ func (ctxt *parseContext) matchAt47(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -3847,11 +3941,13 @@ func (ctxt *parseContext) matchAt48(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -3918,11 +4014,13 @@ func (ctxt *parseContext) matchAt49(line *Line) (newState int, err error) {
 		err = &parseError{
 			msg: fmt.Sprintf("unexpected end of file, expected: %s", strings.Join(expectedTokens, ", ")),
 			loc: &Location{Line: line.LineNumber, Column: 0},
+			code: UnexpectedEOFCode,
 		}
 	} else {
 		err = &parseError{
 			msg: fmt.Sprintf("expected: %s, got '%s'", strings.Join(expectedTokens, ", "), line.LineText),
 			loc: &Location{Line: line.LineNumber, Column: line.Indent() + 1},
+			code: UnexpectedTokenCode,
 		}
 	}
 	// if (ctxt.p.stopAtFirstError) throw error;
@@ -4106,6 +4204,9 @@ func (ctxt *parseContext) lookahead0(initialLine *Line) bool {
 	for {
 		line, atEof, err := ctxt.scan()
 		queue = append(queue, &scanResult{line, atEof, err})
+		if err != nil {
+			break
+		}
 
 		if false || ctxt.isMatchExamplesLine(line) {
 			match = true
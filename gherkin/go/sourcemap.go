@@ -0,0 +1,160 @@
+package gherkin
+
+import (
+	"github.com/cucumber/messages-go/v13"
+	"sort"
+)
+
+// SourceMap maps the lines of a generated feature file back to the template
+// it was generated from, e.g. generated.feature to template.feature.tmpl.
+// It can be decoded from JSON such as
+//
+//	{"uri": "template.feature.tmpl", "lines": {"1": 1, "3": 2, "4": 2}}
+//
+// A generated line without a mapping is mapped like the nearest line above
+// it that has one, so a generator only has to record the first of the lines
+// expanded from a template line.
+type SourceMap struct {
+	// Uri of the template
+	Uri string `json:"uri"`
+	// Lines maps lines of the generated file to lines of the template
+	Lines map[uint32]uint32 `json:"lines"`
+}
+
+type sourceMapper struct {
+	lines          map[uint32]uint32
+	generatedLines []uint32
+}
+
+func (s *SourceMap) mapper() *sourceMapper {
+	mapper := &sourceMapper{lines: s.Lines}
+	for generatedLine := range s.Lines {
+		mapper.generatedLines = append(mapper.generatedLines, generatedLine)
+	}
+	sort.Slice(mapper.generatedLines, func(i, j int) bool { return mapper.generatedLines[i] < mapper.generatedLines[j] })
+	return mapper
+}
+
+func (m *sourceMapper) templateLine(line uint32) uint32 {
+	i := sort.Search(len(m.generatedLines), func(i int) bool { return m.generatedLines[i] > line })
+	if i == 0 {
+		return line
+	}
+	return m.lines[m.generatedLines[i-1]]
+}
+
+func (m *sourceMapper) mapLocation(location *messages.Location) {
+	if location != nil {
+		location.Line = m.templateLine(location.Line)
+	}
+}
+
+// MapGherkinDocument changes the uri and all locations of a document to
+// point at the template
+func (s *SourceMap) MapGherkinDocument(doc *messages.GherkinDocument) {
+	doc.Uri = s.Uri
+	m := s.mapper()
+	for _, comment := range doc.Comments {
+		m.mapLocation(comment.Location)
+	}
+	feature := doc.Feature
+	if feature == nil {
+		return
+	}
+	m.mapLocation(feature.Location)
+	m.mapTags(feature.Tags)
+	for _, child := range feature.Children {
+		switch t := child.Value.(type) {
+		case *messages.GherkinDocument_Feature_FeatureChild_Background:
+			m.mapBackground(t.Background)
+		case *messages.GherkinDocument_Feature_FeatureChild_Scenario:
+			m.mapScenario(t.Scenario)
+		case *messages.GherkinDocument_Feature_FeatureChild_Rule_:
+			m.mapLocation(t.Rule.Location)
+			for _, ruleChild := range t.Rule.Children {
+				switch t := ruleChild.Value.(type) {
+				case *messages.GherkinDocument_Feature_FeatureChild_RuleChild_Background:
+					m.mapBackground(t.Background)
+				case *messages.GherkinDocument_Feature_FeatureChild_RuleChild_Scenario:
+					m.mapScenario(t.Scenario)
+				}
+			}
+		}
+	}
+}
+
+func (m *sourceMapper) mapTags(tags []*messages.GherkinDocument_Feature_Tag) {
+	for _, tag := range tags {
+		m.mapLocation(tag.Location)
+	}
+}
+
+func (m *sourceMapper) mapBackground(background *messages.GherkinDocument_Feature_Background) {
+	m.mapLocation(background.Location)
+	m.mapSteps(background.Steps)
+}
+
+func (m *sourceMapper) mapScenario(scenario *messages.GherkinDocument_Feature_Scenario) {
+	m.mapLocation(scenario.Location)
+	m.mapTags(scenario.Tags)
+	m.mapSteps(scenario.Steps)
+	for _, examples := range scenario.Examples {
+		m.mapLocation(examples.Location)
+		m.mapTags(examples.Tags)
+		if examples.TableHeader != nil {
+			m.mapRows([]*messages.GherkinDocument_Feature_TableRow{examples.TableHeader})
+		}
+		m.mapRows(examples.TableBody)
+	}
+}
+
+func (m *sourceMapper) mapSteps(steps []*messages.GherkinDocument_Feature_Step) {
+	for _, step := range steps {
+		m.mapLocation(step.Location)
+		if dataTable := step.GetDataTable(); dataTable != nil {
+			m.mapLocation(dataTable.Location)
+			m.mapRows(dataTable.Rows)
+		}
+		if docString := step.GetDocString(); docString != nil {
+			m.mapLocation(docString.Location)
+		}
+	}
+}
+
+func (m *sourceMapper) mapRows(rows []*messages.GherkinDocument_Feature_TableRow) {
+	for _, row := range rows {
+		m.mapLocation(row.Location)
+		for _, cell := range row.Cells {
+			m.mapLocation(cell.Location)
+		}
+	}
+}
+
+// MapPickles changes the uri of pickles to the template. Their steps refer
+// to the locations of the document through ids.
+func (s *SourceMap) MapPickles(pickles []*messages.Pickle) {
+	for _, pickle := range pickles {
+		pickle.Uri = s.Uri
+	}
+}
+
+// MapError changes the locations of parse errors to the template
+func (s *SourceMap) MapError(err error) error {
+	errs, ok := err.(parseErrors)
+	if !ok {
+		return err
+	}
+	m := s.mapper()
+	var mapped parseErrors
+	for _, err := range errs {
+		if pe, ok := err.(*parseError); ok {
+			err = &parseError{
+				msg:  pe.msg,
+				loc:  &Location{Line: int(m.templateLine(uint32(pe.loc.Line))), Column: pe.loc.Column},
+				code: pe.code,
+			}
+		}
+		mapped = append(mapped, err)
+	}
+	return mapped
+}
@@ -0,0 +1,46 @@
+package gherkin
+
+import (
+	"encoding/json"
+	"github.com/cucumber/messages-go/v13"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestSourceMap(t *testing.T) {
+	var sourceMap SourceMap
+	require.NoError(t, json.Unmarshal([]byte(`{"uri": "template.feature.tmpl", "lines": {"1": 1, "3": 4, "6": 5}}`), &sourceMap))
+
+	t.Run("maps documents and pickles to the template", func(t *testing.T) {
+		text := `Feature: Generated
+
+  Scenario: one
+    Given a table
+      | a |
+  Scenario: two
+    Given b
+`
+		doc, err := ParseGherkinDocument(strings.NewReader(text), (&messages.Incrementing{}).NewId)
+		require.NoError(t, err)
+		pickles := Pickles(*doc, "generated.feature", (&messages.Incrementing{}).NewId)
+
+		sourceMap.MapGherkinDocument(doc)
+		sourceMap.MapPickles(pickles)
+
+		require.Equal(t, "template.feature.tmpl", doc.Uri)
+		require.Equal(t, uint32(1), doc.Feature.Location.Line)
+		one := doc.Feature.Children[0].GetScenario()
+		require.Equal(t, uint32(4), one.Location.Line)
+		require.Equal(t, uint32(4), one.Steps[0].GetDataTable().Rows[0].Cells[0].Location.Line)
+		require.Equal(t, uint32(9), one.Steps[0].GetDataTable().Rows[0].Cells[0].Location.Column)
+		require.Equal(t, uint32(5), doc.Feature.Children[1].GetScenario().Steps[0].Location.Line)
+		require.Equal(t, "template.feature.tmpl", pickles[1].Uri)
+	})
+
+	t.Run("maps parse errors to the template", func(t *testing.T) {
+		_, err := ParseGherkinDocument(strings.NewReader("Feature: Generated\n\n  Scenario: one\n    Given a\n  Nonsense\n"), (&messages.Incrementing{}).NewId)
+		require.Error(t, err)
+		require.EqualError(t, sourceMap.MapError(err), "Parser errors:\n(4:3): expected: #EOF, #TableRow, #DocStringSeparator, #StepLine, #TagLine, #ExamplesLine, #ScenarioLine, #RuleLine, #Comment, #Empty, got '  Nonsense'")
+	})
+}
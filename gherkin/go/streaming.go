@@ -0,0 +1,119 @@
+package gherkin
+
+import (
+	"errors"
+	"github.com/cucumber/messages-go/v13"
+	"io"
+	"iter"
+)
+
+// ScenarioHandler receives a scenario and the pickles compiled from it as
+// soon as the scenario has been parsed, see ParseOptions.OnScenario.
+// Returning an error stops parsing.
+type ScenarioHandler func(scenario *messages.GherkinDocument_Feature_Scenario, pickles []*messages.Pickle) error
+
+// errStopPickles stops parsing when the caller of StreamPickles stops
+// ranging over the pickles
+var errStopPickles = errors.New("stop streaming pickles")
+
+// StreamPickles yields the pickles of a document as soon as their scenario
+// has been parsed, like ParseOptions.OnScenario. It stops after
+// yielding an error with a nil pickle.
+func StreamPickles(in io.Reader, language string, uri string, newId func() string) iter.Seq2[*messages.Pickle, error] {
+	return func(yield func(*messages.Pickle, error) bool) {
+		_, err := ParseGherkinDocumentWithOptions(in, newId, ParseOptions{
+			Language: language,
+			URI:      uri,
+			OnScenario: func(scenario *messages.GherkinDocument_Feature_Scenario, pickles []*messages.Pickle) error {
+				for _, pickle := range pickles {
+					if !yield(pickle, nil) {
+						return errStopPickles
+					}
+				}
+				return nil
+			},
+		})
+		if err != nil && err != errStopPickles {
+			yield(nil, err)
+		}
+	}
+}
+
+type streamingBuilder struct {
+	*astBuilder
+	scanner     Scanner
+	uri         string
+	handle      ScenarioHandler
+	featureTags []*messages.GherkinDocument_Feature_Tag
+	err         error
+}
+
+// Scan fails once the handler has returned an error, which stops the parser
+func (t *streamingBuilder) Scan() (line *Line, atEof bool, err error) {
+	if t.err != nil {
+		return nil, false, t.err
+	}
+	return t.scanner.Scan()
+}
+
+func (t *streamingBuilder) Reset() {
+	t.astBuilder.Reset()
+	t.featureTags = nil
+	t.err = nil
+}
+
+func (t *streamingBuilder) EndRule(r RuleType) (bool, error) {
+	if r == RuleTypeFeatureHeader {
+		// The tags are needed by the pickles before the feature is complete
+		t.featureTags = astTags(t.currentNode(), t.newId)
+		delete(t.currentNode().subNodes, RuleTypeTags)
+	}
+	ok, err := t.astBuilder.EndRule(r)
+	if err != nil || t.err != nil {
+		return ok, err
+	}
+
+	switch r {
+	case RuleTypeFeature:
+		if feature, ok := t.currentNode().getSingle(RuleTypeFeature).(*messages.GherkinDocument_Feature); ok {
+			feature.Tags = t.featureTags
+		}
+	case RuleTypeScenarioDefinition:
+		parent := t.currentNode()
+		scenarios := parent.subNodes[RuleTypeScenarioDefinition]
+		scenario := scenarios[len(scenarios)-1].(*messages.GherkinDocument_Feature_Scenario)
+		parent.subNodes[RuleTypeScenarioDefinition] = scenarios[:len(scenarios)-1]
+
+		language, backgroundSteps := t.scenarioContext()
+		var pickles []*messages.Pickle
+		if len(scenario.GetExamples()) == 0 {
+			pickles = compileScenario(pickles, backgroundSteps, scenario, t.featureTags, t.uri, language, t.newId)
+		} else {
+			pickles = compileScenarioOutline(pickles, scenario, t.featureTags, backgroundSteps, t.uri, language, t.newId)
+		}
+		t.err = t.handle(scenario, pickles)
+	}
+	return ok, err
+}
+
+// scenarioContext finds the language of the feature and the steps of the
+// feature and rule backgrounds on the stack
+func (t *streamingBuilder) scenarioContext() (language string, backgroundSteps []*messages.GherkinDocument_Feature_Step) {
+	backgroundSteps = make([]*messages.GherkinDocument_Feature_Step, 0)
+	for _, node := range t.stack {
+		switch node.ruleType {
+		case RuleTypeFeature:
+			if header, ok := node.getSingle(RuleTypeFeatureHeader).(*astNode); ok {
+				if featureLine := header.getToken(TokenTypeFeatureLine); featureLine != nil {
+					language = featureLine.GherkinDialect
+				}
+			}
+			fallthrough
+		case RuleTypeRule:
+			if background, ok := node.getSingle(RuleTypeBackground).(*messages.GherkinDocument_Feature_Background); ok {
+				backgroundSteps = append(backgroundSteps, background.Steps...)
+			}
+		}
+	}
+	return
+}
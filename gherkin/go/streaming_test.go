@@ -0,0 +1,136 @@
+package gherkin
+
+import (
+	"errors"
+	"github.com/cucumber/messages-go/v13"
+	"github.com/stretchr/testify/require"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseOptionsOnScenario(t *testing.T) {
+	parseStreaming := func(in io.Reader, uri string, handle ScenarioHandler) (*messages.GherkinDocument, error) {
+		return ParseGherkinDocumentWithOptions(in, (&messages.Incrementing{}).NewId, ParseOptions{URI: uri, OnScenario: handle})
+	}
+
+	t.Run("compiles the same pickles as Pickles", func(t *testing.T) {
+		paths, err := filepath.Glob("testdata/good/*.feature")
+		require.NoError(t, err)
+		for _, path := range paths {
+			data, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+
+			doc, err := ParseGherkinDocument(strings.NewReader(string(data)), (&messages.Incrementing{}).NewId)
+			require.NoError(t, err, path)
+			expected := pickleSummaries(Pickles(*doc, path, (&messages.Incrementing{}).NewId))
+
+			actual := []string{}
+			_, err = parseStreaming(strings.NewReader(string(data)), path,
+				func(scenario *messages.GherkinDocument_Feature_Scenario, pickles []*messages.Pickle) error {
+					actual = append(actual, pickleSummaries(pickles)...)
+					return nil
+				})
+			require.NoError(t, err, path)
+			require.Equal(t, expected, actual, path)
+		}
+	})
+
+	t.Run("returns the document without scenarios", func(t *testing.T) {
+		text := `@slow
+Feature: Streaming
+  Background:
+    Given a background
+
+  Scenario: a
+    Given a
+
+  Rule: b
+    Scenario: c
+      Given c
+`
+		var scenarios []string
+		doc, err := parseStreaming(strings.NewReader(text), "streaming.feature",
+			func(scenario *messages.GherkinDocument_Feature_Scenario, pickles []*messages.Pickle) error {
+				scenarios = append(scenarios, scenario.Name)
+				require.Len(t, pickles, 1)
+				require.Equal(t, "@slow", pickles[0].Tags[0].Name)
+				require.Len(t, pickles[0].Steps, 2)
+				return nil
+			})
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "c"}, scenarios)
+		require.Equal(t, "@slow", doc.Feature.Tags[0].Name)
+		require.Len(t, doc.Feature.Children, 2)
+		require.NotNil(t, doc.Feature.Children[0].GetBackground())
+		require.Empty(t, doc.Feature.Children[1].GetRule().Children)
+	})
+
+	t.Run("stops at the first error of the handler", func(t *testing.T) {
+		text := "Feature: a\n  Scenario: b\n  Scenario: c\n"
+		count := 0
+		_, err := parseStreaming(strings.NewReader(text), "a.feature",
+			func(scenario *messages.GherkinDocument_Feature_Scenario, pickles []*messages.Pickle) error {
+				count++
+				return errors.New("enough")
+			})
+		require.EqualError(t, err, "enough")
+		require.Equal(t, 1, count)
+	})
+}
+
+func TestStreamPickles(t *testing.T) {
+	text := "Feature: a\n  Scenario: b\n    Given b\n  Scenario: c\n    Given c\n"
+
+	t.Run("yields the pickles of every scenario", func(t *testing.T) {
+		var names []string
+		for pickle, err := range StreamPickles(strings.NewReader(text), DEFAULT_DIALECT, "a.feature", (&messages.Incrementing{}).NewId) {
+			require.NoError(t, err)
+			names = append(names, pickle.Name)
+		}
+		require.Equal(t, []string{"b", "c"}, names)
+	})
+
+	t.Run("stops parsing when the caller stops", func(t *testing.T) {
+		var names []string
+		for pickle := range StreamPickles(strings.NewReader(text), DEFAULT_DIALECT, "a.feature", (&messages.Incrementing{}).NewId) {
+			names = append(names, pickle.Name)
+			break
+		}
+		require.Equal(t, []string{"b"}, names)
+	})
+
+	t.Run("yields parse errors", func(t *testing.T) {
+		text := "Feature: a\n  Scenario: b\n    Given b\n  @c\n"
+		var names []string
+		var errs []error
+		for pickle, err := range StreamPickles(strings.NewReader(text), DEFAULT_DIALECT, "a.feature", (&messages.Incrementing{}).NewId) {
+			if err != nil {
+				require.Nil(t, pickle)
+				errs = append(errs, err)
+				continue
+			}
+			names = append(names, pickle.Name)
+		}
+		require.Equal(t, []string{"b"}, names)
+		require.Len(t, errs, 1)
+		require.EqualError(t, errs[0], "Parser errors:\n(5:0): unexpected end of file, expected: #TagLine, #ScenarioLine, #Comment, #Empty")
+	})
+}
+
+func pickleSummaries(pickles []*messages.Pickle) []string {
+	summaries := []string{}
+	for _, pickle := range pickles {
+		summary := []string{pickle.Uri, pickle.Language, pickle.Name}
+		for _, tag := range pickle.Tags {
+			summary = append(summary, tag.Name)
+		}
+		for _, step := range pickle.Steps {
+			summary = append(summary, step.Text, step.Argument.String())
+		}
+		summaries = append(summaries, strings.Join(summary, "|"))
+	}
+	return summaries
+}
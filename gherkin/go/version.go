@@ -0,0 +1,15 @@
+package gherkin
+
+// The version of the parser, updated by make pre-release
+const version = "15.0.2"
+
+// The version of the messages module the parser is built with, updated by
+// make pre-release
+const protocolVersion = "13.1.0"
+
+// ProtocolVersion returns the version of the message protocol of the
+// documents, pickles and errors of the parser, which is the version of the
+// messages module it is built with
+func ProtocolVersion() string {
+	return protocolVersion
+}
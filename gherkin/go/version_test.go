@@ -0,0 +1,16 @@
+package gherkin
+
+import (
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"regexp"
+	"testing"
+)
+
+func TestProtocolVersion(t *testing.T) {
+	t.Run("is the version of the messages module", func(t *testing.T) {
+		goMod, err := ioutil.ReadFile("go.mod")
+		require.NoError(t, err)
+		require.Regexp(t, regexp.MustCompile(`github.com/cucumber/messages-go/v\d+ v`+regexp.QuoteMeta(ProtocolVersion())+`\n`), string(goMod))
+	})
+}
@@ -0,0 +1,35 @@
+package checks
+
+// CheckRun is the body of a request to the GitHub Checks API that creates
+// a check run
+type CheckRun struct {
+	Name       string  `json:"name"`
+	HeadSHA    string  `json:"head_sha,omitempty"`
+	Status     string  `json:"status"`
+	Conclusion string  `json:"conclusion"`
+	Output     *Output `json:"output"`
+}
+
+// Output is the title, summary and annotations of a check run
+type Output struct {
+	Title       string        `json:"title"`
+	Summary     string        `json:"summary"`
+	Annotations []*Annotation `json:"annotations"`
+}
+
+// Annotation points at the line of a feature file that a problem is on
+type Annotation struct {
+	Path            string `json:"path"`
+	StartLine       uint32 `json:"start_line"`
+	EndLine         uint32 `json:"end_line"`
+	AnnotationLevel string `json:"annotation_level"`
+	Title           string `json:"title,omitempty"`
+	Message         string `json:"message"`
+}
+
+// Annotation levels
+const (
+	Notice  = "notice"
+	Warning = "warning"
+	Failure = "failure"
+)
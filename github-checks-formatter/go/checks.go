@@ -0,0 +1,155 @@
+/*
+Package checks implements a Cucumber formatter that converts failed steps and
+parse errors to GitHub Checks annotations, on the lines of the feature files
+they happened on.
+*/
+package checks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/cucumber/messages-go/v13"
+	gio "github.com/gogo/protobuf/io"
+)
+
+// maxAnnotations is the number of annotations the Checks API accepts in a
+// request
+const maxAnnotations = 50
+
+type Formatter struct {
+	// Name of the check run, "Cucumber" by default
+	Name string
+	// HeadSHA is the commit the check run is for
+	HeadSHA string
+	// PathPrefix is prepended to the URIs of feature files, when they are
+	// not relative to the root of the repository
+	PathPrefix string
+	// Token authenticates with the Checks API. When it is empty, the check
+	// run is written to STDOUT as JSON instead of being created.
+	Token string
+	// Repository is the owner and name of the repository, e.g.
+	// "cucumber/cucumber"
+	Repository string
+	// APIURL is the GitHub API, "https://api.github.com" by default
+	APIURL string
+	// Client sends the requests, http.DefaultClient when it is nil
+	Client *http.Client
+}
+
+// ProcessMessages creates a check run when the TestRunFinished message is
+// read
+func (self *Formatter) ProcessMessages(reader gio.ReadCloser, stdout io.Writer) error {
+	if self.Token != "" && (self.Repository == "" || self.HeadSHA == "") {
+		return errors.New("a repository and head SHA are needed to create a check run")
+	}
+
+	run := newRun(self.PathPrefix)
+	for {
+		envelope := &messages.Envelope{}
+		err := reader.ReadMsg(envelope)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		run.processMessage(envelope)
+
+		if m, ok := envelope.Message.(*messages.Envelope_TestRunFinished); ok {
+			checkRun := run.checkRun(m.TestRunFinished)
+			checkRun.Name = self.Name
+			if checkRun.Name == "" {
+				checkRun.Name = "Cucumber"
+			}
+			checkRun.HeadSHA = self.HeadSHA
+			if self.Token == "" {
+				err = json.NewEncoder(stdout).Encode(checkRun)
+			} else {
+				err = self.create(checkRun)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// create creates a check run with the first annotations, and adds the
+// others by updating it, as many as the Checks API accepts at a time
+func (self *Formatter) create(checkRun *CheckRun) error {
+	annotations := checkRun.Output.Annotations
+	batch := func() []*Annotation {
+		n := len(annotations)
+		if n > maxAnnotations {
+			n = maxAnnotations
+		}
+		b := annotations[:n]
+		annotations = annotations[n:]
+		return b
+	}
+
+	first := *checkRun
+	first.Output = &Output{Title: checkRun.Output.Title, Summary: checkRun.Output.Summary, Annotations: batch()}
+	var created struct {
+		Id int64 `json:"id"`
+	}
+	err := self.request(http.MethodPost, "/repos/"+self.Repository+"/check-runs", &first, &created)
+	if err != nil {
+		return err
+	}
+
+	for len(annotations) > 0 {
+		update := struct {
+			Output *Output `json:"output"`
+		}{&Output{Title: checkRun.Output.Title, Summary: checkRun.Output.Summary, Annotations: batch()}}
+		err = self.request(http.MethodPatch, fmt.Sprintf("/repos/%s/check-runs/%d", self.Repository, created.Id), &update, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (self *Formatter) request(method string, path string, body interface{}, result interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	apiURL := self.APIURL
+	if apiURL == "" {
+		apiURL = "https://api.github.com"
+	}
+	request, err := http.NewRequest(method, strings.TrimSuffix(apiURL, "/")+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/vnd.github.v3+json")
+	request.Header.Set("Authorization", "token "+self.Token)
+	request.Header.Set("Content-Type", "application/json")
+
+	client := self.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("%s %s responded with %s: %s", method, path, response.Status, bytes.TrimSpace(message))
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(response.Body).Decode(result)
+}
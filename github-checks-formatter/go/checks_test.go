@@ -0,0 +1,182 @@
+package checks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cucumber/messages-go/v13"
+	"github.com/cucumber/query-go/querytest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotatesTheLinesOfStepsThatDidNotPass(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	err := (&Formatter{HeadSHA: "abc123"}).ProcessMessages(querytest.NewReader(t, failingRun()), stdout)
+	require.NoError(t, err)
+
+	checkRun := &CheckRun{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), checkRun))
+	require.Equal(t, &CheckRun{
+		Name:       "Cucumber",
+		HeadSHA:    "abc123",
+		Status:     "completed",
+		Conclusion: "failure",
+		Output: &Output{
+			Title:   "3 scenarios (1 failed, 1 undefined, 1 passed)",
+			Summary: "3 scenarios (1 failed, 1 undefined, 1 passed)",
+			Annotations: []*Annotation{
+				{Path: "features/b.feature", StartLine: 4, EndLine: 4, AnnotationLevel: Failure, Title: "Parse error", Message: "(4:1): expected: #Step, got 'Nope'"},
+				{Path: "features/eating.feature", StartLine: 8, EndLine: 8, AnnotationLevel: Warning, Title: "Eating", Message: "Step is undefined: I eat"},
+				{Path: "features/eating.feature", StartLine: 13, EndLine: 13, AnnotationLevel: Failure, Title: "Eating 6", Message: "expected 0\n\tat eating.go:12"},
+				{Path: "features/eating.feature", StartLine: 18, EndLine: 18, AnnotationLevel: Failure, Title: "Eating 6", Message: "Hook failed: cleanup failed"},
+			},
+		},
+	}, checkRun)
+}
+
+func TestPrefixesThePaths(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	err := (&Formatter{PathPrefix: "acceptance"}).ProcessMessages(querytest.NewReader(t, failingRun()), stdout)
+	require.NoError(t, err)
+
+	checkRun := &CheckRun{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), checkRun))
+	require.Equal(t, "acceptance/features/b.feature", checkRun.Output.Annotations[0].Path)
+	require.Equal(t, "acceptance/features/eating.feature", checkRun.Output.Annotations[1].Path)
+}
+
+func TestDropsTheAnnotationsOfRetriedAttempts(t *testing.T) {
+	envelopes := append(querytest.Feature(),
+		querytest.TestCase("tc1", "p1", querytest.TestStep("ts1", "ps2", "")),
+		querytest.TestCaseStarted("tcs1", "tc1", 0),
+		querytest.TestStepFinished("tcs1", "ts1", messages.TestStepFinished_TestStepResult_FAILED, "flaky", true),
+		querytest.TestCaseFinished("tcs1"),
+		querytest.TestCaseStarted("tcs2", "tc1", 1),
+		querytest.TestStepFinished("tcs2", "ts1", messages.TestStepFinished_TestStepResult_PASSED, "", false),
+		querytest.TestCaseFinished("tcs2"),
+		querytest.TestRunFinished(true, ""),
+	)
+	stdout := &bytes.Buffer{}
+	err := (&Formatter{}).ProcessMessages(querytest.NewReader(t, envelopes), stdout)
+	require.NoError(t, err)
+
+	checkRun := &CheckRun{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), checkRun))
+	require.Equal(t, "success", checkRun.Conclusion)
+	require.Equal(t, "1 scenarios (1 passed)", checkRun.Output.Title)
+	require.Empty(t, checkRun.Output.Annotations)
+}
+
+func TestConcludesAsNeutralWithoutFailures(t *testing.T) {
+	envelopes := append(querytest.Feature(),
+		querytest.TestCase("tc1", "p2", querytest.TestStep("ts1", "ps3", "")),
+		querytest.TestCaseStarted("tcs1", "tc1", 0),
+		querytest.TestStepFinished("tcs1", "ts1", messages.TestStepFinished_TestStepResult_PENDING, "", false),
+		querytest.TestCaseFinished("tcs1"),
+		querytest.TestRunFinished(false, ""),
+	)
+	stdout := &bytes.Buffer{}
+	err := (&Formatter{}).ProcessMessages(querytest.NewReader(t, envelopes), stdout)
+	require.NoError(t, err)
+
+	checkRun := &CheckRun{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), checkRun))
+	require.Equal(t, "neutral", checkRun.Conclusion)
+	require.Equal(t, Notice, checkRun.Output.Annotations[0].AnnotationLevel)
+}
+
+func TestConcludesAsFailureWhenTheRunFailed(t *testing.T) {
+	envelopes := []*messages.Envelope{querytest.TestRunFinished(false, "No step definitions")}
+	stdout := &bytes.Buffer{}
+	err := (&Formatter{}).ProcessMessages(querytest.NewReader(t, envelopes), stdout)
+	require.NoError(t, err)
+
+	checkRun := &CheckRun{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), checkRun))
+	require.Equal(t, "failure", checkRun.Conclusion)
+	require.Equal(t, "0 scenarios\n\nNo step definitions", checkRun.Output.Summary)
+}
+
+func TestCreatesTheCheckRunInBatchesOfAnnotations(t *testing.T) {
+	envelopes := append(querytest.Feature(), querytest.TestCase("tc1", "p1", querytest.TestStep("ts1", "ps2", "")))
+	for i := 0; i < 60; i++ {
+		id := fmt.Sprintf("tcs%d", i)
+		envelopes = append(envelopes,
+			querytest.TestCaseStarted(id, "tc1", 0),
+			querytest.TestStepFinished(id, "ts1", messages.TestStepFinished_TestStepResult_FAILED, id, false),
+			querytest.TestCaseFinished(id),
+		)
+	}
+	envelopes = append(envelopes, querytest.TestRunFinished(false, ""))
+
+	type request struct {
+		method        string
+		path          string
+		authorization string
+		body          map[string]interface{}
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]interface{}{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests = append(requests, request{r.Method, r.URL.Path, r.Header.Get("Authorization"), body})
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 7}`))
+		}
+	}))
+	defer server.Close()
+
+	formatter := &Formatter{HeadSHA: "abc123", Token: "s3cr3t", Repository: "cucumber/cucumber", APIURL: server.URL}
+	stdout := &bytes.Buffer{}
+	err := formatter.ProcessMessages(querytest.NewReader(t, envelopes), stdout)
+	require.NoError(t, err)
+	require.Empty(t, stdout.String())
+
+	require.Len(t, requests, 2)
+	require.Equal(t, http.MethodPost, requests[0].method)
+	require.Equal(t, "/repos/cucumber/cucumber/check-runs", requests[0].path)
+	require.Equal(t, "token s3cr3t", requests[0].authorization)
+	require.Equal(t, "abc123", requests[0].body["head_sha"])
+	require.Equal(t, "failure", requests[0].body["conclusion"])
+	require.Len(t, requests[0].body["output"].(map[string]interface{})["annotations"], 50)
+
+	require.Equal(t, http.MethodPatch, requests[1].method)
+	require.Equal(t, "/repos/cucumber/cucumber/check-runs/7", requests[1].path)
+	annotations := requests[1].body["output"].(map[string]interface{})["annotations"].([]interface{})
+	require.Len(t, annotations, 10)
+	require.Equal(t, "tcs50", annotations[0].(map[string]interface{})["message"])
+}
+
+func TestReturnsAnErrorWhenTheChecksAPIFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Bad credentials"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	formatter := &Formatter{HeadSHA: "abc123", Token: "s3cr3t", Repository: "cucumber/cucumber", APIURL: server.URL}
+	err := formatter.ProcessMessages(querytest.NewReader(t, failingRun()), &bytes.Buffer{})
+	require.EqualError(t, err, `POST /repos/cucumber/cucumber/check-runs responded with 401 Unauthorized: {"message": "Bad credentials"}`)
+}
+
+func TestNeedsARepositoryToCreateTheCheckRun(t *testing.T) {
+	err := (&Formatter{HeadSHA: "abc123", Token: "s3cr3t"}).ProcessMessages(querytest.NewReader(t, failingRun()), &bytes.Buffer{})
+	require.EqualError(t, err, "a repository and head SHA are needed to create a check run")
+}
+
+// failingRun is the querytest.FailingRun after a parse error
+func failingRun() []*messages.Envelope {
+	return append([]*messages.Envelope{
+		{Message: &messages.Envelope_ParseError{ParseError: &messages.ParseError{
+			Source: &messages.SourceReference{
+				Reference: &messages.SourceReference_Uri{Uri: "features/b.feature"},
+				Location:  &messages.Location{Line: 4, Column: 1},
+			},
+			Message: "(4:1): expected: #Step, got 'Nope'",
+		}}},
+	}, querytest.FailingRun()...)
+}
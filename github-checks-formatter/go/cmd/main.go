@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+
+	checksFormatter "github.com/cucumber/github-checks-formatter-go"
+	fio "github.com/cucumber/messages-go/v13/io"
+	gio "github.com/gogo/protobuf/io"
+)
+
+var formatFlag = flag.String("format", "protobuf", "output format")
+var nameFlag = flag.String("name", "Cucumber", "name of the check run")
+var shaFlag = flag.String("sha", os.Getenv("GITHUB_SHA"), "commit the check run is for")
+var repositoryFlag = flag.String("repository", os.Getenv("GITHUB_REPOSITORY"), "owner and name of the repository")
+var pathPrefixFlag = flag.String("path-prefix", "", "directory of the feature file URIs, relative to the repository")
+var apiURLFlag = flag.String("api-url", os.Getenv("GITHUB_API_URL"), "GitHub API")
+
+func main() {
+	flag.Parse()
+
+	cf := &checksFormatter.Formatter{
+		Name:       *nameFlag,
+		HeadSHA:    *shaFlag,
+		PathPrefix: *pathPrefixFlag,
+		// The token is only read from the environment, to keep it out of
+		// the process list
+		Token:      os.Getenv("GITHUB_TOKEN"),
+		Repository: *repositoryFlag,
+		APIURL:     *apiURLFlag,
+	}
+	err := cf.ProcessMessages(newReader(os.Stdin), os.Stdout)
+	if err != nil {
+		log.Fatal("ERROR: ", err)
+	}
+}
+
+func newReader(in io.Reader) gio.ReadCloser {
+	var reader gio.ReadCloser
+	switch *formatFlag {
+	case "protobuf":
+		reader = gio.NewDelimitedReader(in, math.MaxInt32)
+	case "ndjson":
+		reader = fio.NewNdjsonReader(in)
+	default:
+		_, err := fmt.Fprintf(os.Stderr, "Unsupported format: %s\n", *formatFlag)
+		if err != nil {
+			log.Fatal("ERROR: ", err)
+		}
+	}
+	return reader
+}
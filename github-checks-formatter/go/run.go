@@ -0,0 +1,187 @@
+package checks
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/cucumber/messages-go/v13"
+	"github.com/cucumber/query-go"
+)
+
+// run collects the annotations of the steps that did not pass, and counts
+// the scenarios by status
+type run struct {
+	pathPrefix  string
+	query       *query.Query
+	attempts    map[string][]*Annotation
+	annotations []*Annotation
+	counts      map[messages.TestStepFinished_TestStepResult_Status]int
+}
+
+func newRun(pathPrefix string) *run {
+	return &run{
+		pathPrefix:  pathPrefix,
+		query:       query.New(),
+		attempts:    make(map[string][]*Annotation),
+		annotations: make([]*Annotation, 0),
+		counts:      make(map[messages.TestStepFinished_TestStepResult_Status]int),
+	}
+}
+
+// processMessage collects the annotations of an attempt at a test case
+// until it finishes, and drops them if it is retried
+func (self *run) processMessage(envelope *messages.Envelope) {
+	self.query.Update(envelope)
+	switch m := envelope.Message.(type) {
+	case *messages.Envelope_ParseError:
+		self.annotations = append(self.annotations, self.parseErrorAnnotation(m.ParseError))
+
+	case *messages.Envelope_TestStepFinished:
+		testCaseRun := self.query.TestCaseRun(m.TestStepFinished.TestCaseStartedId)
+		result := m.TestStepFinished.TestStepResult
+		if testCaseRun == nil || testCaseRun.Pickle == nil || result == nil {
+			return
+		}
+		if annotation := self.stepAnnotation(testCaseRun.Pickle, self.query.TestStep(m.TestStepFinished.TestStepId), result); annotation != nil {
+			self.attempts[testCaseRun.Started.Id] = append(self.attempts[testCaseRun.Started.Id], annotation)
+		}
+
+	case *messages.Envelope_TestCaseFinished:
+		testCaseRun := self.query.TestCaseRun(m.TestCaseFinished.TestCaseStartedId)
+		if testCaseRun != nil && !testCaseRun.WillBeRetried {
+			self.counts[testCaseRun.Status]++
+			self.annotations = append(self.annotations, self.attempts[testCaseRun.Started.Id]...)
+		}
+		delete(self.attempts, m.TestCaseFinished.TestCaseStartedId)
+	}
+}
+
+// stepAnnotation annotates the line of a step that did not pass, or the
+// line of the scenario if a hook failed
+func (self *run) stepAnnotation(pickle *messages.Pickle, testStep *messages.TestCase_TestStep, result *messages.TestStepFinished_TestStepResult) *Annotation {
+	var level, message string
+	switch result.Status {
+	case messages.TestStepFinished_TestStepResult_FAILED:
+		level, message = Failure, "Step failed"
+	case messages.TestStepFinished_TestStepResult_AMBIGUOUS:
+		level, message = Failure, "Step is ambiguous"
+	case messages.TestStepFinished_TestStepResult_UNDEFINED:
+		level, message = Warning, "Step is undefined"
+	case messages.TestStepFinished_TestStepResult_PENDING:
+		level, message = Notice, "Step is pending"
+	default:
+		return nil
+	}
+	if result.Message != "" {
+		message = result.Message
+	}
+
+	var astNodeIds []string
+	if testStep != nil && testStep.HookId != "" {
+		message = "Hook failed: " + message
+		astNodeIds = pickle.AstNodeIds
+	} else if testStep != nil && self.query.PickleStep(testStep.PickleStepId) != nil {
+		pickleStep := self.query.PickleStep(testStep.PickleStepId)
+		if result.Message == "" {
+			message = fmt.Sprintf("%s: %s", message, pickleStep.Text)
+		}
+		// The first node is the step, the others are example rows
+		astNodeIds = pickleStep.AstNodeIds
+		if len(astNodeIds) > 1 {
+			astNodeIds = astNodeIds[:1]
+		}
+	}
+	line := self.line(astNodeIds)
+	return &Annotation{
+		Path:            self.path(pickle.Uri),
+		StartLine:       line,
+		EndLine:         line,
+		AnnotationLevel: level,
+		Title:           pickle.Name,
+		Message:         message,
+	}
+}
+
+func (self *run) parseErrorAnnotation(parseError *messages.ParseError) *Annotation {
+	line := uint32(1)
+	if location := parseError.Source.GetLocation(); location != nil && location.Line > 0 {
+		line = location.Line
+	}
+	return &Annotation{
+		Path:            self.path(parseError.Source.GetUri()),
+		StartLine:       line,
+		EndLine:         line,
+		AnnotationLevel: Failure,
+		Title:           "Parse error",
+		Message:         parseError.Message,
+	}
+}
+
+// line is the line of the last of the nodes with a location, 1 if none has
+// one
+func (self *run) line(astNodeIds []string) uint32 {
+	if line := self.query.Line(astNodeIds); line > 0 {
+		return line
+	}
+	return 1
+}
+
+func (self *run) path(uri string) string {
+	if self.pathPrefix == "" {
+		return uri
+	}
+	return path.Join(self.pathPrefix, uri)
+}
+
+// checkRun concludes the run as a failure if an annotation is a failure or
+// the run failed before any scenario ran, and as neutral if there are only
+// warnings and notices
+func (self *run) checkRun(finished *messages.TestRunFinished) *CheckRun {
+	conclusion := "success"
+	if len(self.annotations) > 0 {
+		conclusion = "neutral"
+	}
+	for _, annotation := range self.annotations {
+		if annotation.AnnotationLevel == Failure {
+			conclusion = "failure"
+		}
+	}
+	if !finished.Success && finished.Message != "" {
+		conclusion = "failure"
+	}
+
+	title := self.title()
+	summary := title
+	if finished.Message != "" {
+		summary += "\n\n" + finished.Message
+	}
+	return &CheckRun{
+		Status:     "completed",
+		Conclusion: conclusion,
+		Output:     &Output{Title: title, Summary: summary, Annotations: self.annotations},
+	}
+}
+
+func (self *run) title() string {
+	total := 0
+	var counts []string
+	for _, status := range []messages.TestStepFinished_TestStepResult_Status{
+		messages.TestStepFinished_TestStepResult_FAILED,
+		messages.TestStepFinished_TestStepResult_AMBIGUOUS,
+		messages.TestStepFinished_TestStepResult_UNDEFINED,
+		messages.TestStepFinished_TestStepResult_PENDING,
+		messages.TestStepFinished_TestStepResult_SKIPPED,
+		messages.TestStepFinished_TestStepResult_PASSED,
+		messages.TestStepFinished_TestStepResult_UNKNOWN,
+	} {
+		if n := self.counts[status]; n > 0 {
+			total += n
+			counts = append(counts, fmt.Sprintf("%d %s", n, strings.ToLower(status.String())))
+		}
+	}
+	if total == 0 {
+		return "0 scenarios"
+	}
+	return fmt.Sprintf("%d scenarios (%s)", total, strings.Join(counts, ", "))
+}
@@ -13,13 +13,22 @@ import (
 )
 
 var formatFlag = flag.String("format", "protobuf", "output format")
+var compactFlag = flag.Bool("compact", false, "print the report on a single line, without indentation")
+var indentFlag = flag.String("indent", "  ", "indentation of each level of the report, such as a tab")
+var sortFlag = flag.String("sort", "execution", "order of features and scenarios: execution, location or duration")
+var groupFlag = flag.String("group", "feature", "group scenarios by feature, status or tag")
 
 func main() {
 	flag.Parse()
 
 	var err error
 	var file *os.File
-	jf := &jsonFormatter.Formatter{}
+	jf := &jsonFormatter.Formatter{
+		Compact: *compactFlag,
+		Indent:  *indentFlag,
+		Sort:    jsonFormatter.SortOrder(*sortFlag),
+		Group:   jsonFormatter.Grouping(*groupFlag),
+	}
 	paths := flag.Args()
 	if len(paths) > 1 {
 		for _, arg := range paths {
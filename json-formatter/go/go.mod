@@ -2,7 +2,7 @@ module github.com/cucumber/json-formatter-go/v6
 
 replace github.com/cucumber/messages-go/v13 => ../../messages/go
 
-go 1.13
+go 1.23
 
 require (
 	github.com/cucumber/messages-go/v13 v13.1.0
@@ -10,3 +10,15 @@ require (
 	github.com/onsi/ginkgo v1.14.2
 	github.com/onsi/gomega v1.10.3
 )
+
+require (
+	github.com/fsnotify/fsnotify v1.4.9 // indirect
+	github.com/gofrs/uuid v3.3.0+incompatible // indirect
+	github.com/nxadm/tail v1.4.4 // indirect
+	golang.org/x/net v0.0.0-20201006153459-a7d1128ccaa0 // indirect
+	golang.org/x/sys v0.0.0-20200930185726-fdedc70b468f // indirect
+	golang.org/x/text v0.3.3 // indirect
+	golang.org/x/xerrors v0.0.0-20191204190536-9bdfabe68543 // indirect
+	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
+	gopkg.in/yaml.v2 v2.3.0 // indirect
+)
@@ -0,0 +1,211 @@
+package json
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cucumber/messages-go/v13"
+	"github.com/gogo/protobuf/proto"
+)
+
+func makeScenario(id string, steps []*messages.GherkinDocument_Feature_Step) *messages.GherkinDocument_Feature_Scenario {
+	return &messages.GherkinDocument_Feature_Scenario{
+		Id:    id,
+		Steps: steps,
+	}
+}
+
+func makeGherkinStep(id string, keyword string, text string) *messages.GherkinDocument_Feature_Step {
+	return &messages.GherkinDocument_Feature_Step{
+		Id:      id,
+		Keyword: keyword,
+		Text:    text,
+	}
+}
+
+func makeTestStep(id string, pickleStepId string, stepDefinitionIds []string) *messages.TestCase_TestStep {
+	return &messages.TestCase_TestStep{
+		Id:                id,
+		PickleStepId:      pickleStepId,
+		StepDefinitionIds: stepDefinitionIds,
+	}
+}
+
+func makeHookTestStep(id string, hookId string) *messages.TestCase_TestStep {
+	return &messages.TestCase_TestStep{
+		Id:     id,
+		HookId: hookId,
+	}
+}
+
+func makeTestCase(id string, pickleId string, testSteps []*messages.TestCase_TestStep) *messages.TestCase {
+	return &messages.TestCase{
+		Id:        id,
+		PickleId:  pickleId,
+		TestSteps: testSteps,
+	}
+}
+
+func makeTestCaseEnvelope(testCase *messages.TestCase) *messages.Envelope {
+	return &messages.Envelope{
+		Message: &messages.Envelope_TestCase{
+			TestCase: testCase,
+		},
+	}
+}
+
+func makeTestCaseStartedEnvelope(testCaseStarted *messages.TestCaseStarted) *messages.Envelope {
+	return &messages.Envelope{
+		Message: &messages.Envelope_TestCaseStarted{
+			TestCaseStarted: testCaseStarted,
+		},
+	}
+}
+
+func makeHookEnvelope(testCaseHook *messages.Hook) *messages.Envelope {
+	return &messages.Envelope{
+		Message: &messages.Envelope_Hook{
+			Hook: testCaseHook,
+		},
+	}
+}
+
+func makeStepDefinitionEnvelope(stepDefinitionConfig *messages.StepDefinition) *messages.Envelope {
+	return &messages.Envelope{
+		Message: &messages.Envelope_StepDefinition{
+			StepDefinition: stepDefinitionConfig,
+		},
+	}
+}
+
+func makePickleEnvelope(pickle *messages.Pickle) *messages.Envelope {
+	return &messages.Envelope{
+		Message: &messages.Envelope_Pickle{
+			Pickle: pickle,
+		},
+	}
+}
+
+// makeScenarioRunEnvelopes returns the messages of a feature file with a
+// single one-step scenario, and of that scenario being run.
+func makeScenarioRunEnvelopes(uri string, line uint32, status messages.TestStepFinished_TestStepResult_Status) []*messages.Envelope {
+	return makeFeatureRunEnvelopes(uri, scenarioRun{line: line, status: status})
+}
+
+// scenarioRun is a one-step scenario of makeFeatureRunEnvelopes
+type scenarioRun struct {
+	line     uint32
+	status   messages.TestStepFinished_TestStepResult_Status
+	duration time.Duration
+	tags     []string
+}
+
+// makeFeatureRunEnvelopes returns the messages of a feature file with
+// one-step scenarios, and of those scenarios being run in the given order.
+func makeFeatureRunEnvelopes(uri string, runs ...scenarioRun) []*messages.Envelope {
+	feature := &messages.GherkinDocument_Feature{
+		Keyword:  "Feature",
+		Name:     "feature in " + uri,
+		Location: &messages.Location{Line: 1},
+	}
+	envelopes := []*messages.Envelope{
+		{
+			Message: &messages.Envelope_GherkinDocument{
+				GherkinDocument: &messages.GherkinDocument{
+					Uri:     uri,
+					Feature: feature,
+				},
+			},
+		},
+	}
+
+	for _, run := range runs {
+		id := fmt.Sprintf("%s-%d", uri, run.line)
+		step := makeGherkinStep(id+"-step", "Given ", "a step")
+		step.Location = &messages.Location{Line: run.line + 1}
+		scenario := makeScenario(id+"-scenario", []*messages.GherkinDocument_Feature_Step{step})
+		scenario.Keyword = "Scenario"
+		scenario.Name = "scenario in " + uri
+		scenario.Location = &messages.Location{Line: run.line}
+		feature.Children = append(feature.Children, &messages.GherkinDocument_Feature_FeatureChild{
+			Value: &messages.GherkinDocument_Feature_FeatureChild_Scenario{
+				Scenario: scenario,
+			},
+		})
+		pickleStep := &messages.Pickle_PickleStep{
+			Id:         id + "-pickle-step",
+			Text:       "a step",
+			AstNodeIds: []string{step.Id},
+		}
+		pickle := &messages.Pickle{
+			Id:         id + "-pickle",
+			Uri:        uri,
+			AstNodeIds: []string{scenario.Id},
+			Steps:      []*messages.Pickle_PickleStep{pickleStep},
+		}
+		for i, name := range run.tags {
+			tag := &messages.GherkinDocument_Feature_Tag{
+				Id:       fmt.Sprintf("%s-tag-%d", id, i),
+				Name:     name,
+				Location: &messages.Location{Line: run.line - 1},
+			}
+			scenario.Tags = append(scenario.Tags, tag)
+			pickle.Tags = append(pickle.Tags, &messages.Pickle_PickleTag{Name: name, AstNodeId: tag.Id})
+		}
+		testCase := makeTestCase(id+"-test-case", pickle.Id, []*messages.TestCase_TestStep{
+			makeTestStep(id+"-test-step", pickleStep.Id, []string{}),
+		})
+		duration := messages.GoDurationToDuration(run.duration)
+
+		envelopes = append(envelopes,
+			makePickleEnvelope(pickle),
+			makeTestCaseEnvelope(testCase),
+			&messages.Envelope{
+				Message: &messages.Envelope_TestCaseStarted{
+					TestCaseStarted: &messages.TestCaseStarted{
+						Id:         id + "-test-case-started",
+						TestCaseId: testCase.Id,
+					},
+				},
+			},
+			&messages.Envelope{
+				Message: &messages.Envelope_TestStepFinished{
+					TestStepFinished: &messages.TestStepFinished{
+						TestCaseStartedId: id + "-test-case-started",
+						TestStepId:        id + "-test-step",
+						TestStepResult: &messages.TestStepFinished_TestStepResult{
+							Status:   run.status,
+							Duration: &duration,
+						},
+					},
+				},
+			},
+			&messages.Envelope{
+				Message: &messages.Envelope_TestCaseFinished{
+					TestCaseFinished: &messages.TestCaseFinished{
+						TestCaseStartedId: id + "-test-case-started",
+					},
+				},
+			},
+		)
+	}
+	return envelopes
+}
+
+type envelopeReader struct {
+	envelopes []*messages.Envelope
+}
+
+func (self *envelopeReader) ReadMsg(msg proto.Message) error {
+	if len(self.envelopes) == 0 {
+		return io.EOF
+	}
+	*msg.(*messages.Envelope) = *self.envelopes[0]
+	self.envelopes = self.envelopes[1:]
+	return nil
+}
+
+func (self *envelopeReader) Close() error {
+	return nil
+}
@@ -10,7 +10,24 @@ import (
 	gio "github.com/gogo/protobuf/io"
 )
 
+// Formatter writes the legacy Cucumber JSON report. The output is the same
+// for the same messages: the keys of every object are in a fixed order,
+// the one of the fields of the jsonFeature, jsonFeatureElement and jsonStep
+// structs, and nothing is null. Lists that every object has, such as
+// elements and steps, are [] when they are empty, and optional values, such
+// as tags, rows and error_message, are left out when they are empty.
 type Formatter struct {
+	// Compact writes the report on a single line, without indentation
+	Compact bool
+	// Indent is the indentation of each level of the report, two spaces by
+	// default, when it isn't compact
+	Indent string
+	// Sort is the order of features and scenarios, by default the order in
+	// which scenarios finished
+	Sort SortOrder
+	// Group groups the scenarios of every feature by status or tag
+	Group Grouping
+
 	lookup *MessageLookup
 
 	jsonFeatures      []*jsonFeature
@@ -21,6 +38,10 @@ type Formatter struct {
 
 // ProcessMessages writes a JSON report to STDOUT
 func (self *Formatter) ProcessMessages(reader gio.ReadCloser, stdout io.Writer) (err error) {
+	err = self.checkOptions()
+	if err != nil {
+		return err
+	}
 	self.verbose = false
 	self.lookup = &MessageLookup{}
 	self.lookup.Initialize(self.verbose)
@@ -73,14 +94,31 @@ func (self *Formatter) ProcessMessages(reader gio.ReadCloser, stdout io.Writer)
 
 			if ok {
 				jsonFeature := self.findOrCreateJsonFeature(testCase.Pickle)
-				for _, jsonElement := range TestCaseToJSON(testCase) {
-					jsonFeature.Elements = append(jsonFeature.Elements, jsonElement)
-				}
+				jsonFeature.testCases = append(jsonFeature.testCases, &reportedTestCase{TestCaseToJSON(testCase)})
 			}
 		}
 	}
 
-	output, _ := json.MarshalIndent(self.jsonFeatures, "", "  ")
+	self.orderReport()
+	for _, jsonFeature := range self.jsonFeatures {
+		for _, testCase := range jsonFeature.testCases {
+			jsonFeature.Elements = append(jsonFeature.Elements, testCase.elements...)
+		}
+	}
+
+	var output []byte
+	if self.Compact {
+		output, err = json.Marshal(self.jsonFeatures)
+	} else {
+		indent := self.Indent
+		if indent == "" {
+			indent = "  "
+		}
+		output, err = json.MarshalIndent(self.jsonFeatures, "", indent)
+	}
+	if err != nil {
+		return err
+	}
 	_, err = fmt.Fprintln(stdout, string(output))
 	return err
 }
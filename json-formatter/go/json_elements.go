@@ -9,6 +9,8 @@ type jsonFeature struct {
 	Name        string                `json:"name"`
 	URI         string                `json:"uri"`
 	Tags        []*jsonTag            `json:"tags,omitempty"`
+
+	testCases []*reportedTestCase
 }
 
 type jsonFeatureElement struct {
@@ -0,0 +1,55 @@
+package json
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/cucumber/messages-go/v13"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Formatter.ProcessMessages", func() {
+	var envelopes []*messages.Envelope
+
+	BeforeEach(func() {
+		envelopes = makeScenarioRunEnvelopes("features/a.feature", 3, messages.TestStepFinished_TestStepResult_PASSED)
+	})
+
+	It("indents the report by default", func() {
+		output := &bytes.Buffer{}
+		err := (&Formatter{}).ProcessMessages(&envelopeReader{envelopes}, output)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.String()).To(HavePrefix("[\n  {\n    \"description\": \"\","))
+	})
+
+	It("writes the report on a single line when compact", func() {
+		output := &bytes.Buffer{}
+		err := (&Formatter{Compact: true}).ProcessMessages(&envelopeReader{envelopes}, output)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Count(output.String(), "\n")).To(Equal(1))
+		Expect(output.String()).To(HavePrefix(`[{"description":"","elements":[{"description":"",`))
+	})
+
+	It("indents the report with the given indentation", func() {
+		output := &bytes.Buffer{}
+		err := (&Formatter{Indent: "\t"}).ProcessMessages(&envelopeReader{envelopes}, output)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.String()).To(HavePrefix("[\n\t{\n\t\t\"description\": \"\","))
+	})
+
+	It("writes keys in a fixed order and no nulls", func() {
+		envelopes = makeFeatureRunEnvelopes("features/a.feature", scenarioRun{line: 3, status: messages.TestStepFinished_TestStepResult_FAILED})
+		for i := 0; i < 3; i++ {
+			output := &bytes.Buffer{}
+			err := (&Formatter{Compact: true}).ProcessMessages(&envelopeReader{envelopes}, output)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.String()).To(Equal(`[{"description":"","elements":[{"description":"","id":"feature-in-features/a.feature;scenario-in-features/a.feature","keyword":"Scenario","line":3,"name":"scenario in features/a.feature","steps":[{"keyword":"Given ","line":4,"name":"a step","result":{"status":"failed"},"match":{"location":"features/a.feature:4"}}],"type":"scenario"}],"id":"feature-in-features/a.feature","keyword":"Feature","line":1,"name":"feature in features/a.feature","uri":"features/a.feature"}]` + "\n"))
+			Expect(output.String()).NotTo(ContainSubstring("null"))
+		}
+	})
+})
@@ -0,0 +1,163 @@
+package json
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SortOrder is the order of the features and scenarios of a report
+type SortOrder string
+
+const (
+	// SortByExecution keeps the order in which the scenarios finished
+	SortByExecution SortOrder = "execution"
+	// SortByLocation sorts features by URI and scenarios by line
+	SortByLocation SortOrder = "location"
+	// SortByDuration puts the longest features and scenarios first
+	SortByDuration SortOrder = "duration"
+)
+
+// Grouping puts the scenarios of every feature of a report with the same
+// status, or first tag, next to each other
+type Grouping string
+
+const (
+	// GroupByFeature only groups scenarios by feature
+	GroupByFeature Grouping = "feature"
+	// GroupByStatus groups failed scenarios first and passed ones last
+	GroupByStatus Grouping = "status"
+	// GroupByTag groups scenarios by the name of their first tag, with
+	// untagged scenarios last
+	GroupByTag Grouping = "tag"
+)
+
+// The statuses of scenarios by group, worst first
+var statusOrder = []string{"failed", "ambiguous", "undefined", "pending", "unknown", "skipped", "passed"}
+
+// reportedTestCase holds the elements of a test case, which are its
+// background and its scenario, so they stay together when sorting
+type reportedTestCase struct {
+	elements []*jsonFeatureElement
+}
+
+// checkOptions returns an error for an unknown sort order or grouping, so
+// the formatter fails before it reads any message
+func (self *Formatter) checkOptions() error {
+	switch self.Sort {
+	case "", SortByExecution, SortByLocation, SortByDuration:
+	default:
+		return fmt.Errorf("unknown sort order: %s", self.Sort)
+	}
+	switch self.Group {
+	case "", GroupByFeature, GroupByStatus, GroupByTag:
+	default:
+		return fmt.Errorf("unknown grouping: %s", self.Group)
+	}
+	return nil
+}
+
+func (self *Formatter) orderReport() {
+	var less func(a, b *reportedTestCase) bool
+	switch self.Sort {
+	case "", SortByExecution:
+	case SortByLocation:
+		less = func(a, b *reportedTestCase) bool { return a.line() < b.line() }
+		sort.SliceStable(self.jsonFeatures, func(i, j int) bool {
+			return self.jsonFeatures[i].URI < self.jsonFeatures[j].URI
+		})
+	case SortByDuration:
+		less = func(a, b *reportedTestCase) bool { return a.duration() > b.duration() }
+		sort.SliceStable(self.jsonFeatures, func(i, j int) bool {
+			return featureDuration(self.jsonFeatures[i]) > featureDuration(self.jsonFeatures[j])
+		})
+	}
+
+	// Groups are ordered by rank, and then by name
+	var group func(testCase *reportedTestCase) (rank int, name string)
+	switch self.Group {
+	case "", GroupByFeature:
+	case GroupByStatus:
+		group = func(testCase *reportedTestCase) (int, string) {
+			return statusIndex(testCase.status()), ""
+		}
+	case GroupByTag:
+		group = func(testCase *reportedTestCase) (int, string) {
+			tags := testCase.scenario().Tags
+			if len(tags) == 0 {
+				return 1, ""
+			}
+			return 0, tags[0].Name
+		}
+	}
+
+	for _, jsonFeature := range self.jsonFeatures {
+		testCases := jsonFeature.testCases
+		sort.SliceStable(testCases, func(i, j int) bool {
+			if group != nil {
+				rankI, nameI := group(testCases[i])
+				rankJ, nameJ := group(testCases[j])
+				if rankI != rankJ {
+					return rankI < rankJ
+				}
+				if nameI != nameJ {
+					return nameI < nameJ
+				}
+			}
+			return less != nil && less(testCases[i], testCases[j])
+		})
+	}
+}
+
+func featureDuration(jsonFeature *jsonFeature) uint64 {
+	duration := uint64(0)
+	for _, testCase := range jsonFeature.testCases {
+		duration += testCase.duration()
+	}
+	return duration
+}
+
+func (self *reportedTestCase) scenario() *jsonFeatureElement {
+	return self.elements[len(self.elements)-1]
+}
+
+func (self *reportedTestCase) line() uint32 {
+	return self.scenario().Line
+}
+
+func (self *reportedTestCase) steps() []*jsonStep {
+	var steps []*jsonStep
+	for _, element := range self.elements {
+		steps = append(steps, element.Before...)
+		steps = append(steps, element.Steps...)
+		steps = append(steps, element.After...)
+	}
+	return steps
+}
+
+func (self *reportedTestCase) duration() uint64 {
+	duration := uint64(0)
+	for _, step := range self.steps() {
+		duration += step.Result.Duration
+	}
+	return duration
+}
+
+// status returns the worst status of the steps
+func (self *reportedTestCase) status() string {
+	status := "passed"
+	for _, step := range self.steps() {
+		if statusIndex(step.Result.Status) < statusIndex(status) {
+			status = step.Result.Status
+		}
+	}
+	return status
+}
+
+func statusIndex(status string) int {
+	for i, s := range statusOrder {
+		if s == status {
+			return i
+		}
+	}
+	return statusIndex("unknown")
+}
@@ -0,0 +1,125 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cucumber/messages-go/v13"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Formatter report order", func() {
+	var envelopes []*messages.Envelope
+
+	BeforeEach(func() {
+		envelopes = append(
+			makeFeatureRunEnvelopes("features/b.feature",
+				scenarioRun{line: 10, status: messages.TestStepFinished_TestStepResult_PASSED, duration: 3 * time.Second, tags: []string{"@slow"}},
+				scenarioRun{line: 4, status: messages.TestStepFinished_TestStepResult_FAILED, duration: time.Second},
+				scenarioRun{line: 7, status: messages.TestStepFinished_TestStepResult_SKIPPED, duration: 2 * time.Second, tags: []string{"@fast", "@slow"}},
+			),
+			makeFeatureRunEnvelopes("features/a.feature",
+				scenarioRun{line: 3, status: messages.TestStepFinished_TestStepResult_PASSED, duration: time.Second},
+			)...,
+		)
+	})
+
+	// reportOrder returns the URI and line of every scenario in the report
+	reportOrder := func(formatter *Formatter) []string {
+		output := &bytes.Buffer{}
+		err := formatter.ProcessMessages(&envelopeReader{envelopes}, output)
+		Expect(err).NotTo(HaveOccurred())
+
+		var features []struct {
+			URI      string `json:"uri"`
+			Elements []struct {
+				Line uint32 `json:"line"`
+			} `json:"elements"`
+		}
+		Expect(json.Unmarshal(output.Bytes(), &features)).To(Succeed())
+		var order []string
+		for _, feature := range features {
+			for _, element := range feature.Elements {
+				order = append(order, fmt.Sprintf("%s:%d", feature.URI, element.Line))
+			}
+		}
+		return order
+	}
+
+	It("keeps the execution order by default", func() {
+		Expect(reportOrder(&Formatter{})).To(Equal([]string{
+			"features/b.feature:10",
+			"features/b.feature:4",
+			"features/b.feature:7",
+			"features/a.feature:3",
+		}))
+	})
+
+	It("sorts by location", func() {
+		Expect(reportOrder(&Formatter{Sort: SortByLocation})).To(Equal([]string{
+			"features/a.feature:3",
+			"features/b.feature:4",
+			"features/b.feature:7",
+			"features/b.feature:10",
+		}))
+	})
+
+	It("sorts by duration, longest first", func() {
+		Expect(reportOrder(&Formatter{Sort: SortByDuration})).To(Equal([]string{
+			"features/b.feature:10",
+			"features/b.feature:7",
+			"features/b.feature:4",
+			"features/a.feature:3",
+		}))
+	})
+
+	It("groups by status, worst first", func() {
+		Expect(reportOrder(&Formatter{Group: GroupByStatus})).To(Equal([]string{
+			"features/b.feature:4",
+			"features/b.feature:7",
+			"features/b.feature:10",
+			"features/a.feature:3",
+		}))
+	})
+
+	It("groups by first tag, untagged last", func() {
+		Expect(reportOrder(&Formatter{Group: GroupByTag})).To(Equal([]string{
+			"features/b.feature:7",
+			"features/b.feature:10",
+			"features/b.feature:4",
+			"features/a.feature:3",
+		}))
+	})
+
+	It("sorts within groups", func() {
+		envelopes = makeFeatureRunEnvelopes("features/c.feature",
+			scenarioRun{line: 9, status: messages.TestStepFinished_TestStepResult_PASSED},
+			scenarioRun{line: 2, status: messages.TestStepFinished_TestStepResult_FAILED},
+			scenarioRun{line: 5, status: messages.TestStepFinished_TestStepResult_PASSED},
+			scenarioRun{line: 7, status: messages.TestStepFinished_TestStepResult_FAILED},
+		)
+		Expect(reportOrder(&Formatter{Sort: SortByLocation, Group: GroupByStatus})).To(Equal([]string{
+			"features/c.feature:2",
+			"features/c.feature:7",
+			"features/c.feature:5",
+			"features/c.feature:9",
+		}))
+	})
+
+	It("fails on an unknown sort order before reading messages", func() {
+		reader := &envelopeReader{envelopes}
+		err := (&Formatter{Sort: "name"}).ProcessMessages(reader, &bytes.Buffer{})
+		Expect(err).To(MatchError("unknown sort order: name"))
+		Expect(reader.envelopes).To(Equal(envelopes))
+	})
+
+	It("fails on an unknown grouping before reading messages", func() {
+		reader := &envelopeReader{envelopes}
+		err := (&Formatter{Group: "step"}).ProcessMessages(reader, &bytes.Buffer{})
+		Expect(err).To(MatchError("unknown grouping: step"))
+		Expect(reader.envelopes).To(Equal(envelopes))
+	})
+})
@@ -0,0 +1,53 @@
+package messages
+
+import "encoding/base64"
+
+// Media types for the attachment kinds formatters know how to display.
+const (
+	MediaTypePng             = "image/png"
+	MediaTypeJpeg            = "image/jpeg"
+	MediaTypeWebm            = "video/webm"
+	MediaTypeMp4             = "video/mp4"
+	MediaTypePlaywrightTrace = "application/x.playwright.trace+zip"
+	MediaTypeZip             = "application/zip"
+	MediaTypeLog             = "text/x.cucumber.log+plain"
+)
+
+func NewScreenshotAttachment(png []byte, fileName string) *Attachment {
+	return newBinaryAttachment(png, MediaTypePng, fileName)
+}
+
+func NewJpegScreenshotAttachment(jpeg []byte, fileName string) *Attachment {
+	return newBinaryAttachment(jpeg, MediaTypeJpeg, fileName)
+}
+
+// NewVideoAttachment accepts MediaTypeWebm or MediaTypeMp4.
+func NewVideoAttachment(video []byte, mediaType string, fileName string) *Attachment {
+	return newBinaryAttachment(video, mediaType, fileName)
+}
+
+func NewPlaywrightTraceAttachment(trace []byte, fileName string) *Attachment {
+	return newBinaryAttachment(trace, MediaTypePlaywrightTrace, fileName)
+}
+
+// NewLogBundleAttachment attaches a zip archive of log files.
+func NewLogBundleAttachment(zip []byte, fileName string) *Attachment {
+	return newBinaryAttachment(zip, MediaTypeZip, fileName)
+}
+
+func NewLogAttachment(text string) *Attachment {
+	return &Attachment{
+		Body:            text,
+		MediaType:       MediaTypeLog,
+		ContentEncoding: Attachment_IDENTITY,
+	}
+}
+
+func newBinaryAttachment(data []byte, mediaType string, fileName string) *Attachment {
+	return &Attachment{
+		Body:            base64.StdEncoding.EncodeToString(data),
+		MediaType:       mediaType,
+		ContentEncoding: Attachment_BASE64,
+		FileName:        fileName,
+	}
+}
@@ -0,0 +1,43 @@
+package messages
+
+import (
+	"encoding/base64"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAttachments(t *testing.T) {
+	t.Run("base64 encodes screenshots", func(t *testing.T) {
+		png := []byte{0x89, 'P', 'N', 'G'}
+		attachment := NewScreenshotAttachment(png, "failure.png")
+
+		require.Equal(t, MediaTypePng, attachment.MediaType)
+		require.Equal(t, Attachment_BASE64, attachment.ContentEncoding)
+		require.Equal(t, "failure.png", attachment.FileName)
+		body, err := base64.StdEncoding.DecodeString(attachment.Body)
+		require.NoError(t, err)
+		require.Equal(t, png, body)
+	})
+
+	t.Run("sets the media type of videos", func(t *testing.T) {
+		attachment := NewVideoAttachment([]byte("video"), MediaTypeWebm, "run.webm")
+
+		require.Equal(t, MediaTypeWebm, attachment.MediaType)
+		require.Equal(t, Attachment_BASE64, attachment.ContentEncoding)
+	})
+
+	t.Run("attaches playwright traces", func(t *testing.T) {
+		attachment := NewPlaywrightTraceAttachment([]byte("PK"), "trace.zip")
+
+		require.Equal(t, MediaTypePlaywrightTrace, attachment.MediaType)
+		require.Equal(t, "trace.zip", attachment.FileName)
+	})
+
+	t.Run("attaches logs as plain text", func(t *testing.T) {
+		attachment := NewLogAttachment("connected to db")
+
+		require.Equal(t, MediaTypeLog, attachment.MediaType)
+		require.Equal(t, Attachment_IDENTITY, attachment.ContentEncoding)
+		require.Equal(t, "connected to db", attachment.Body)
+	})
+}
@@ -0,0 +1,33 @@
+package messages
+
+import (
+	gogoio "github.com/gogo/protobuf/io"
+	"io"
+	"iter"
+)
+
+// Envelopes yields the envelopes read by reader, such as an NDJSON reader,
+// until the end of its input. It stops after yielding an error with a nil
+// envelope.
+//
+//	for envelope, err := range messages.Envelopes(messagesio.NewNdjsonReader(os.Stdin)) {
+//		...
+//	}
+func Envelopes(reader gogoio.Reader) iter.Seq2[*Envelope, error] {
+	return func(yield func(*Envelope, error) bool) {
+		for {
+			envelope := &Envelope{}
+			err := reader.ReadMsg(envelope)
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(envelope, nil) {
+				return
+			}
+		}
+	}
+}
@@ -104,4 +104,39 @@ func TestMessages(t *testing.T) {
 		require.NoError(t, r.ReadMsg(&decoded))
 		require.NoError(t, r.ReadMsg(&decoded))
 	})
+
+	t.Run("yields envelopes until the end of the input", func(t *testing.T) {
+		b := &bytes.Buffer{}
+		writer := messagesio.NewNdjsonWriter(b)
+		for _, name := range []string{"first", "second", "third"} {
+			envelope := &Envelope{Message: &Envelope_Source{Source: &Source{Uri: name}}}
+			require.NoError(t, writer.WriteMsg(envelope))
+		}
+
+		var uris []string
+		for envelope, err := range Envelopes(messagesio.NewNdjsonReader(bytes.NewReader(b.Bytes()))) {
+			require.NoError(t, err)
+			uris = append(uris, envelope.GetSource().Uri)
+		}
+		require.Equal(t, []string{"first", "second", "third"}, uris)
+
+		uris = nil
+		for envelope := range Envelopes(messagesio.NewNdjsonReader(bytes.NewReader(b.Bytes()))) {
+			uris = append(uris, envelope.GetSource().Uri)
+			break
+		}
+		require.Equal(t, []string{"first"}, uris)
+	})
+
+	t.Run("stops yielding envelopes at an error", func(t *testing.T) {
+		b := &bytes.Buffer{}
+		b.WriteString("{}\nnot json\n{}\n")
+		var errs []error
+		for _, err := range Envelopes(messagesio.NewNdjsonReader(b)) {
+			errs = append(errs, err)
+		}
+		require.Len(t, errs, 2)
+		require.NoError(t, errs[0])
+		require.Error(t, errs[1])
+	})
 }
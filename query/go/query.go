@@ -0,0 +1,231 @@
+/*
+Package query indexes the messages of a Cucumber run by id, so formatters
+can look up what the ids in other messages refer to, and follows every
+attempt at running a test case from its TestCaseStarted message to its
+TestCaseFinished message.
+*/
+package query
+
+import (
+	"time"
+
+	"github.com/cucumber/messages-go/v13"
+)
+
+type Query struct {
+	features     map[string]*messages.GherkinDocument_Feature
+	scenarios    map[string]*messages.GherkinDocument_Feature_Scenario
+	steps        map[string]*messages.GherkinDocument_Feature_Step
+	tableRows    map[string]*messages.GherkinDocument_Feature_TableRow
+	examples     map[string]*messages.GherkinDocument_Feature_Scenario_Examples
+	locations    map[string]*messages.Location
+	pickles      map[string]*messages.Pickle
+	pickleSteps  map[string]*messages.Pickle_PickleStep
+	testCases    map[string]*messages.TestCase
+	testSteps    map[string]*messages.TestCase_TestStep
+	testCaseRuns map[string]*TestCaseRun
+	started      *messages.TestRunStarted
+}
+
+// TestCaseRun is an attempt at running a test case
+type TestCaseRun struct {
+	Started *messages.TestCaseStarted
+	// TestCase and Pickle are nil when their messages were not read before
+	// the TestCaseStarted message
+	TestCase *messages.TestCase
+	Pickle   *messages.Pickle
+	// Status is the worst status of the steps that finished, PASSED until
+	// one did not pass
+	Status messages.TestStepFinished_TestStepResult_Status
+	// FailedStep is the first step that finished with the Status, nil while
+	// the steps pass
+	FailedStep *messages.TestCase_TestStep
+	// Message is the message of the result of the FailedStep
+	Message string
+	// Duration is the sum of the durations of the steps that finished
+	Duration time.Duration
+	// WillBeRetried is true when a step failed and the test case runs
+	// again, in a TestCaseRun that replaces this one
+	WillBeRetried bool
+	// Finished is nil until the test case finished
+	Finished *messages.TestCaseFinished
+}
+
+func New() *Query {
+	return &Query{
+		features:     make(map[string]*messages.GherkinDocument_Feature),
+		scenarios:    make(map[string]*messages.GherkinDocument_Feature_Scenario),
+		steps:        make(map[string]*messages.GherkinDocument_Feature_Step),
+		tableRows:    make(map[string]*messages.GherkinDocument_Feature_TableRow),
+		examples:     make(map[string]*messages.GherkinDocument_Feature_Scenario_Examples),
+		locations:    make(map[string]*messages.Location),
+		pickles:      make(map[string]*messages.Pickle),
+		pickleSteps:  make(map[string]*messages.Pickle_PickleStep),
+		testCases:    make(map[string]*messages.TestCase),
+		testSteps:    make(map[string]*messages.TestCase_TestStep),
+		testCaseRuns: make(map[string]*TestCaseRun),
+	}
+}
+
+// Update indexes a message. Messages are looked up by the ids of the ones
+// read after them, so Update must be called in the order they are read.
+func (self *Query) Update(envelope *messages.Envelope) {
+	switch m := envelope.Message.(type) {
+	case *messages.Envelope_GherkinDocument:
+		if m.GherkinDocument.Feature != nil {
+			self.features[m.GherkinDocument.Uri] = m.GherkinDocument.Feature
+			self.indexChildren(m.GherkinDocument.Feature.Children)
+		}
+
+	case *messages.Envelope_Pickle:
+		self.pickles[m.Pickle.Id] = m.Pickle
+		for _, step := range m.Pickle.Steps {
+			self.pickleSteps[step.Id] = step
+		}
+
+	case *messages.Envelope_TestCase:
+		self.testCases[m.TestCase.Id] = m.TestCase
+		for _, step := range m.TestCase.TestSteps {
+			self.testSteps[step.Id] = step
+		}
+
+	case *messages.Envelope_TestRunStarted:
+		self.started = m.TestRunStarted
+
+	case *messages.Envelope_TestCaseStarted:
+		run := &TestCaseRun{
+			Started:  m.TestCaseStarted,
+			TestCase: self.testCases[m.TestCaseStarted.TestCaseId],
+			Status:   messages.TestStepFinished_TestStepResult_PASSED,
+		}
+		if run.TestCase != nil {
+			run.Pickle = self.pickles[run.TestCase.PickleId]
+		}
+		self.testCaseRuns[m.TestCaseStarted.Id] = run
+
+	case *messages.Envelope_TestStepFinished:
+		run := self.testCaseRuns[m.TestStepFinished.TestCaseStartedId]
+		result := m.TestStepFinished.TestStepResult
+		if run == nil || result == nil {
+			return
+		}
+		if result.Status > run.Status {
+			run.Status = result.Status
+			run.FailedStep = self.testSteps[m.TestStepFinished.TestStepId]
+			run.Message = result.Message
+		}
+		if result.Duration != nil {
+			run.Duration += messages.DurationToGoDuration(*result.Duration)
+		}
+		run.WillBeRetried = run.WillBeRetried || result.WillBeRetried
+
+	case *messages.Envelope_TestCaseFinished:
+		if run := self.testCaseRuns[m.TestCaseFinished.TestCaseStartedId]; run != nil {
+			run.Finished = m.TestCaseFinished
+		}
+	}
+}
+
+func (self *Query) indexChildren(children []*messages.GherkinDocument_Feature_FeatureChild) {
+	for _, child := range children {
+		if rule := child.GetRule(); rule != nil {
+			for _, ruleChild := range rule.Children {
+				self.indexSteps(ruleChild.GetBackground().GetSteps())
+				self.indexScenario(ruleChild.GetScenario())
+			}
+		}
+		self.indexSteps(child.GetBackground().GetSteps())
+		self.indexScenario(child.GetScenario())
+	}
+}
+
+func (self *Query) indexScenario(scenario *messages.GherkinDocument_Feature_Scenario) {
+	if scenario == nil {
+		return
+	}
+	self.scenarios[scenario.Id] = scenario
+	self.locations[scenario.Id] = scenario.Location
+	self.indexSteps(scenario.Steps)
+	for _, examples := range scenario.Examples {
+		for _, row := range examples.TableBody {
+			self.tableRows[row.Id] = row
+			self.examples[row.Id] = examples
+			self.locations[row.Id] = row.Location
+		}
+	}
+}
+
+func (self *Query) indexSteps(steps []*messages.GherkinDocument_Feature_Step) {
+	for _, step := range steps {
+		self.steps[step.Id] = step
+		self.locations[step.Id] = step.Location
+	}
+}
+
+// Feature is the feature of the Gherkin document with a URI
+func (self *Query) Feature(uri string) *messages.GherkinDocument_Feature {
+	return self.features[uri]
+}
+
+func (self *Query) Scenario(id string) *messages.GherkinDocument_Feature_Scenario {
+	return self.scenarios[id]
+}
+
+// Step is a step of a scenario or background
+func (self *Query) Step(id string) *messages.GherkinDocument_Feature_Step {
+	return self.steps[id]
+}
+
+// TableRow is a row of the examples of an outline
+func (self *Query) TableRow(id string) *messages.GherkinDocument_Feature_TableRow {
+	return self.tableRows[id]
+}
+
+// Examples are the examples with the row with an id
+func (self *Query) Examples(tableRowId string) *messages.GherkinDocument_Feature_Scenario_Examples {
+	return self.examples[tableRowId]
+}
+
+// Location is the location of a scenario, step or example row
+func (self *Query) Location(astNodeId string) *messages.Location {
+	return self.locations[astNodeId]
+}
+
+// Line is the line of the last of the nodes with a location, 0 if none has
+// one. The line of the AstNodeIds of a pickle is that of its example row,
+// or of its scenario if it is not from an outline.
+func (self *Query) Line(astNodeIds []string) uint32 {
+	for i := len(astNodeIds) - 1; i >= 0; i-- {
+		if location := self.locations[astNodeIds[i]]; location != nil {
+			return location.Line
+		}
+	}
+	return 0
+}
+
+func (self *Query) Pickle(id string) *messages.Pickle {
+	return self.pickles[id]
+}
+
+func (self *Query) PickleStep(id string) *messages.Pickle_PickleStep {
+	return self.pickleSteps[id]
+}
+
+func (self *Query) TestCase(id string) *messages.TestCase {
+	return self.testCases[id]
+}
+
+func (self *Query) TestStep(id string) *messages.TestCase_TestStep {
+	return self.testSteps[id]
+}
+
+// TestCaseRun is the attempt started by the TestCaseStarted message with
+// an id
+func (self *Query) TestCaseRun(testCaseStartedId string) *TestCaseRun {
+	return self.testCaseRuns[testCaseStartedId]
+}
+
+// TestRunStarted is nil until the run started
+func (self *Query) TestRunStarted() *messages.TestRunStarted {
+	return self.started
+}
@@ -0,0 +1,78 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cucumber/messages-go/v13"
+	"github.com/cucumber/query-go/querytest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowsTheTestCaseRuns(t *testing.T) {
+	query := newQuery(querytest.FailingRun())
+
+	run := query.TestCaseRun("tcs3")
+	require.Equal(t, "tc3", run.TestCase.Id)
+	require.Equal(t, "Eating 6", run.Pickle.Name)
+	require.Equal(t, messages.TestStepFinished_TestStepResult_FAILED, run.Status)
+	require.Equal(t, "ts9", run.FailedStep.Id)
+	require.Equal(t, "expected 0\n\tat eating.go:12", run.Message)
+	require.Equal(t, 4*time.Millisecond, run.Duration)
+	require.False(t, run.WillBeRetried)
+	require.Equal(t, "tcs3", run.Finished.TestCaseStartedId)
+
+	run = query.TestCaseRun("tcs2")
+	require.Equal(t, messages.TestStepFinished_TestStepResult_PASSED, run.Status)
+	require.Nil(t, run.FailedStep)
+	require.Empty(t, run.Message)
+
+	require.Equal(t, messages.TestStepFinished_TestStepResult_UNDEFINED, query.TestCaseRun("tcs1").Status)
+	require.Nil(t, query.TestCaseRun("tcs4"))
+}
+
+func TestFollowsRetriedTestCaseRuns(t *testing.T) {
+	query := newQuery(append(querytest.Feature(),
+		querytest.TestCase("tc1", "p1", querytest.TestStep("ts1", "ps2", "")),
+		querytest.TestCaseStarted("tcs1", "tc1", 0),
+		querytest.TestStepFinished("tcs1", "ts1", messages.TestStepFinished_TestStepResult_FAILED, "flaky", true),
+		querytest.TestCaseFinished("tcs1"),
+		querytest.TestCaseStarted("tcs2", "tc1", 1),
+		querytest.TestStepFinished("tcs2", "ts1", messages.TestStepFinished_TestStepResult_PASSED, "", false),
+	))
+
+	require.True(t, query.TestCaseRun("tcs1").WillBeRetried)
+	require.False(t, query.TestCaseRun("tcs2").WillBeRetried)
+	require.Nil(t, query.TestCaseRun("tcs2").Finished)
+}
+
+func TestLooksUpTheNodesOfGherkinDocuments(t *testing.T) {
+	query := newQuery(querytest.FailingRun())
+
+	require.Equal(t, "Eating", query.Feature(querytest.Uri).Name)
+	require.Equal(t, "Eating <count>", query.Scenario("s2").Name)
+	require.Equal(t, "Given ", query.Step("b1").Keyword)
+	require.Equal(t, "6", query.TableRow("r2").Cells[0].Value)
+	require.Equal(t, "count", query.Examples("r2").TableHeader.Cells[0].Value)
+	require.Equal(t, uint32(13), query.Location("st2").Line)
+	require.Equal(t, "I eat 6", query.PickleStep("ps6").Text)
+	require.Equal(t, "h2", query.TestStep("ts10").HookId)
+	require.NotNil(t, query.TestRunStarted())
+}
+
+func TestFindsTheLineOfNodes(t *testing.T) {
+	query := newQuery(querytest.Feature())
+
+	require.Equal(t, uint32(7), query.Line(query.Pickle("p1").AstNodeIds))
+	require.Equal(t, uint32(18), query.Line(query.Pickle("p3").AstNodeIds))
+	require.Equal(t, uint32(11), query.Line([]string{"s2", "unknown"}))
+	require.Equal(t, uint32(0), query.Line(nil))
+}
+
+func newQuery(envelopes []*messages.Envelope) *Query {
+	query := New()
+	for _, envelope := range envelopes {
+		query.Update(envelope)
+	}
+	return query
+}
@@ -0,0 +1,300 @@
+/*
+Package querytest has the messages of a Cucumber run, and functions to make
+them, for the tests of formatters.
+*/
+package querytest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cucumber/messages-go/v13"
+	fio "github.com/cucumber/messages-go/v13/io"
+	gio "github.com/gogo/protobuf/io"
+)
+
+// Uri is the URI of the feature file of the Feature
+const Uri = "features/eating.feature"
+
+// Feature is a Gherkin document and its pickles. It is the feature file
+//
+//	 1  Feature: Eating
+//	 2
+//	 3    Background:
+//	 4      Given I am hungry
+//	 5
+//	 6    @smoke
+//	 7    Scenario: Eating
+//	 8      When I eat
+//	 9
+//	10    @severity=critical @tms=ABC-1
+//	11    Scenario Outline: Eating <count>
+//	12      Cucumbers are good for you
+//	13      When I eat <count>
+//	14
+//	15      Examples:
+//	16        | count |
+//	17        | 5     |
+//	18        | 6     |
+//
+// whose pickle of the first example is also tagged @issue=JIRA-1,
+// @owner=aslak and @tms=ABC-2, as if the example were in Examples of its own.
+// The ids of the scenarios are s1 and s2, of the steps b1, st1 and st2,
+// of the example rows r1 and r2, of the pickles p1, p2 and p3, and of
+// their steps ps1 to ps6.
+func Feature() []*messages.Envelope {
+	return []*messages.Envelope{
+		{Message: &messages.Envelope_GherkinDocument{GherkinDocument: &messages.GherkinDocument{
+			Uri: Uri,
+			Feature: &messages.GherkinDocument_Feature{
+				Name:     "Eating",
+				Location: &messages.Location{Line: 1},
+				Children: []*messages.GherkinDocument_Feature_FeatureChild{
+					{Value: &messages.GherkinDocument_Feature_FeatureChild_Background{Background: &messages.GherkinDocument_Feature_Background{
+						Location: &messages.Location{Line: 3},
+						Steps: []*messages.GherkinDocument_Feature_Step{
+							{Id: "b1", Keyword: "Given ", Text: "I am hungry", Location: &messages.Location{Line: 4}},
+						},
+					}}},
+					{Value: &messages.GherkinDocument_Feature_FeatureChild_Scenario{Scenario: &messages.GherkinDocument_Feature_Scenario{
+						Id:       "s1",
+						Name:     "Eating",
+						Location: &messages.Location{Line: 7},
+						Steps: []*messages.GherkinDocument_Feature_Step{
+							{Id: "st1", Keyword: "When ", Text: "I eat", Location: &messages.Location{Line: 8}},
+						},
+					}}},
+					{Value: &messages.GherkinDocument_Feature_FeatureChild_Scenario{Scenario: &messages.GherkinDocument_Feature_Scenario{
+						Id:          "s2",
+						Name:        "Eating <count>",
+						Description: "    Cucumbers are good for you\n",
+						Location:    &messages.Location{Line: 11},
+						Steps: []*messages.GherkinDocument_Feature_Step{
+							{Id: "st2", Keyword: "When ", Text: "I eat <count>", Location: &messages.Location{Line: 13}},
+						},
+						Examples: []*messages.GherkinDocument_Feature_Scenario_Examples{{
+							Location: &messages.Location{Line: 15},
+							TableHeader: &messages.GherkinDocument_Feature_TableRow{
+								Location: &messages.Location{Line: 16},
+								Cells:    []*messages.GherkinDocument_Feature_TableRow_TableCell{{Value: "count"}},
+							},
+							TableBody: []*messages.GherkinDocument_Feature_TableRow{
+								{
+									Id:       "r1",
+									Location: &messages.Location{Line: 17},
+									Cells:    []*messages.GherkinDocument_Feature_TableRow_TableCell{{Value: "5"}},
+								},
+								{
+									Id:       "r2",
+									Location: &messages.Location{Line: 18},
+									Cells:    []*messages.GherkinDocument_Feature_TableRow_TableCell{{Value: "6"}},
+								},
+							},
+						}},
+					}}},
+				},
+			},
+		}}},
+		pickle("p1", "Eating", []string{"s1"}, []string{"@smoke"},
+			pickleStep("ps1", "I am hungry", "b1"),
+			pickleStep("ps2", "I eat", "st1"),
+		),
+		pickle("p2", "Eating 5", []string{"s2", "r1"}, []string{"@severity=critical", "@tms=ABC-1", "@issue=JIRA-1", "@owner=aslak", "@tms=ABC-2"},
+			pickleStep("ps3", "I am hungry", "b1"),
+			pickleStep("ps4", "I eat 5", "st2", "r1"),
+		),
+		pickle("p3", "Eating 6", []string{"s2", "r2"}, []string{"@severity=critical", "@tms=ABC-1"},
+			pickleStep("ps5", "I am hungry", "b1"),
+			pickleStep("ps6", "I eat 6", "st2", "r2"),
+		),
+	}
+}
+
+// FailingRun is a run of the Feature, with the test cases tc1 to tc3 of the
+// pickles, on CI. The scenario is undefined, the first example passes and
+// the second one fails, with a screenshot and a failing after hook. Both
+// examples have a before and an after hook.
+//
+// The run starts at 2020-08-10 21:00:00 UTC and finishes 4 seconds later.
+// The test cases start a second after each other, and every step takes a
+// millisecond.
+func FailingRun() []*messages.Envelope {
+	envelopes := []*messages.Envelope{
+		{Message: &messages.Envelope_Meta{Meta: &messages.Meta{
+			Implementation: &messages.Meta_Product{Name: "cucumber-go", Version: "1.0.0"},
+			Ci: &messages.Meta_CI{
+				Name: "GitHub Actions",
+				Git:  &messages.Meta_CI_Git{Revision: "abc123", Branch: "main"},
+			},
+		}}},
+	}
+	envelopes = append(envelopes, Feature()...)
+	envelopes = append(envelopes,
+		TestCase("tc1", "p1", TestStep("ts1", "ps1", ""), TestStep("ts2", "ps2", "")),
+		TestCase("tc2", "p2", TestStep("ts3", "", "h1"), TestStep("ts4", "ps3", ""), TestStep("ts5", "ps4", ""), TestStep("ts6", "", "h2")),
+		TestCase("tc3", "p3", TestStep("ts7", "", "h1"), TestStep("ts8", "ps5", ""), TestStep("ts9", "ps6", ""), TestStep("ts10", "", "h2")),
+		&messages.Envelope{Message: &messages.Envelope_TestRunStarted{TestRunStarted: &messages.TestRunStarted{
+			Timestamp: Timestamp(0),
+		}}},
+	)
+
+	run := &runBuilder{envelopes: envelopes}
+	run.testCase("tcs1", "tc1", 1000)
+	run.step("ts1", messages.TestStepFinished_TestStepResult_PASSED, "")
+	run.step("ts2", messages.TestStepFinished_TestStepResult_UNDEFINED, "")
+	run.finish()
+
+	run.testCase("tcs2", "tc2", 2000)
+	run.step("ts3", messages.TestStepFinished_TestStepResult_PASSED, "")
+	run.step("ts4", messages.TestStepFinished_TestStepResult_PASSED, "")
+	run.step("ts5", messages.TestStepFinished_TestStepResult_PASSED, "")
+	run.step("ts6", messages.TestStepFinished_TestStepResult_PASSED, "")
+	run.finish()
+
+	run.testCase("tcs3", "tc3", 3000)
+	run.step("ts7", messages.TestStepFinished_TestStepResult_PASSED, "")
+	run.step("ts8", messages.TestStepFinished_TestStepResult_PASSED, "")
+	run.step("ts9", messages.TestStepFinished_TestStepResult_FAILED, "expected 0\n\tat eating.go:12",
+		&messages.Envelope{Message: &messages.Envelope_Attachment{Attachment: &messages.Attachment{
+			TestCaseStartedId: "tcs3",
+			TestStepId:        "ts9",
+			Body:              "iVBORw==",
+			ContentEncoding:   messages.Attachment_BASE64,
+			MediaType:         "image/png",
+			FileName:          "screenshot.png",
+		}}},
+	)
+	run.step("ts10", messages.TestStepFinished_TestStepResult_FAILED, "cleanup failed")
+	run.finish()
+
+	return append(run.envelopes, &messages.Envelope{Message: &messages.Envelope_TestRunFinished{TestRunFinished: &messages.TestRunFinished{
+		Success:   false,
+		Timestamp: Timestamp(4000),
+	}}})
+}
+
+// runBuilder appends the messages of test cases whose steps take a
+// millisecond each
+type runBuilder struct {
+	envelopes         []*messages.Envelope
+	testCaseStartedId string
+	millis            int64
+}
+
+func (self *runBuilder) testCase(id string, testCaseId string, millis int64) {
+	self.testCaseStartedId = id
+	self.millis = millis
+	started := TestCaseStarted(id, testCaseId, 0)
+	started.GetTestCaseStarted().Timestamp = Timestamp(millis)
+	self.envelopes = append(self.envelopes, started)
+}
+
+// step appends the messages of a step, and the ones sent while it ran,
+// such as attachments
+func (self *runBuilder) step(testStepId string, status messages.TestStepFinished_TestStepResult_Status, message string, during ...*messages.Envelope) {
+	self.envelopes = append(self.envelopes, &messages.Envelope{Message: &messages.Envelope_TestStepStarted{TestStepStarted: &messages.TestStepStarted{
+		TestCaseStartedId: self.testCaseStartedId,
+		TestStepId:        testStepId,
+		Timestamp:         Timestamp(self.millis),
+	}}})
+	self.envelopes = append(self.envelopes, during...)
+	self.millis++
+	finished := TestStepFinished(self.testCaseStartedId, testStepId, status, message, false)
+	finished.GetTestStepFinished().Timestamp = Timestamp(self.millis)
+	finished.GetTestStepFinished().TestStepResult.Duration = &messages.Duration{Nanos: 1000000}
+	self.envelopes = append(self.envelopes, finished)
+}
+
+func (self *runBuilder) finish() {
+	finished := TestCaseFinished(self.testCaseStartedId)
+	finished.GetTestCaseFinished().Timestamp = Timestamp(self.millis)
+	self.envelopes = append(self.envelopes, finished)
+}
+
+func pickle(id string, name string, astNodeIds []string, tags []string, steps ...*messages.Pickle_PickleStep) *messages.Envelope {
+	pickleTags := make([]*messages.Pickle_PickleTag, len(tags))
+	for i, tag := range tags {
+		pickleTags[i] = &messages.Pickle_PickleTag{Name: tag}
+	}
+	return &messages.Envelope{Message: &messages.Envelope_Pickle{Pickle: &messages.Pickle{
+		Id:         id,
+		Uri:        Uri,
+		Name:       name,
+		AstNodeIds: astNodeIds,
+		Tags:       pickleTags,
+		Steps:      steps,
+	}}}
+}
+
+func pickleStep(id string, text string, astNodeIds ...string) *messages.Pickle_PickleStep {
+	return &messages.Pickle_PickleStep{Id: id, Text: text, AstNodeIds: astNodeIds}
+}
+
+// Timestamp is a time in milliseconds after the start of the FailingRun
+func Timestamp(millis int64) *messages.Timestamp {
+	millis += 1597093200000
+	return &messages.Timestamp{Seconds: millis / 1000, Nanos: int32(millis%1000) * 1000000}
+}
+
+// TestStep is a step of a TestCase, which runs a pickle step or a hook
+func TestStep(id string, pickleStepId string, hookId string) *messages.TestCase_TestStep {
+	return &messages.TestCase_TestStep{Id: id, PickleStepId: pickleStepId, HookId: hookId}
+}
+
+func TestCase(id string, pickleId string, testSteps ...*messages.TestCase_TestStep) *messages.Envelope {
+	return &messages.Envelope{Message: &messages.Envelope_TestCase{TestCase: &messages.TestCase{
+		Id:        id,
+		PickleId:  pickleId,
+		TestSteps: testSteps,
+	}}}
+}
+
+func TestCaseStarted(id string, testCaseId string, attempt uint32) *messages.Envelope {
+	return &messages.Envelope{Message: &messages.Envelope_TestCaseStarted{TestCaseStarted: &messages.TestCaseStarted{
+		Id:         id,
+		TestCaseId: testCaseId,
+		Attempt:    attempt,
+	}}}
+}
+
+func TestStepFinished(testCaseStartedId string, testStepId string, status messages.TestStepFinished_TestStepResult_Status, message string, willBeRetried bool) *messages.Envelope {
+	return &messages.Envelope{Message: &messages.Envelope_TestStepFinished{TestStepFinished: &messages.TestStepFinished{
+		TestCaseStartedId: testCaseStartedId,
+		TestStepId:        testStepId,
+		TestStepResult: &messages.TestStepFinished_TestStepResult{
+			Status:        status,
+			Message:       message,
+			WillBeRetried: willBeRetried,
+		},
+	}}}
+}
+
+func TestCaseFinished(testCaseStartedId string) *messages.Envelope {
+	return &messages.Envelope{Message: &messages.Envelope_TestCaseFinished{TestCaseFinished: &messages.TestCaseFinished{
+		TestCaseStartedId: testCaseStartedId,
+	}}}
+}
+
+func TestRunFinished(success bool, message string) *messages.Envelope {
+	return &messages.Envelope{Message: &messages.Envelope_TestRunFinished{TestRunFinished: &messages.TestRunFinished{
+		Success: success,
+		Message: message,
+	}}}
+}
+
+// NewReader reads envelopes from NDJSON, like formatters read them from
+// STDIN
+func NewReader(t testing.TB, envelopes []*messages.Envelope) gio.ReadCloser {
+	t.Helper()
+	stdin := &bytes.Buffer{}
+	writer := fio.NewNdjsonWriter(stdin)
+	for _, envelope := range envelopes {
+		if err := writer.WriteMsg(envelope); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return fio.NewNdjsonReader(bytes.NewReader(stdin.Bytes()))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+
+	fio "github.com/cucumber/messages-go/v13/io"
+	sqliteFormatter "github.com/cucumber/sqlite-formatter-go"
+	gio "github.com/gogo/protobuf/io"
+)
+
+var formatFlag = flag.String("format", "protobuf", "output format")
+var runIdFlag = flag.String("run-id", "", "id of the test run in the database, a random UUID by default")
+var trendsQueryFlag = flag.Bool("trends-query", false, "print the query that selects the rows --trends reads")
+var trendsFlag = flag.Bool("trends", false, "read the rows of the trends query from the JSON output of sqlite3 -json, and write their trends as JSON")
+var windowFlag = flag.Int("window", 10, "number of recent runs of a scenario the recent pass rate is computed over")
+
+func main() {
+	flag.Parse()
+
+	if *trendsQueryFlag {
+		fmt.Print(sqliteFormatter.TrendsQuery)
+		return
+	}
+	if *trendsFlag {
+		rows, err := sqliteFormatter.ReadTestCaseRows(os.Stdin)
+		if err != nil {
+			log.Fatal("ERROR: ", err)
+		}
+		analyzer := &sqliteFormatter.Analyzer{Window: *windowFlag}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(analyzer.Analyze(rows))
+		if err != nil {
+			log.Fatal("ERROR: ", err)
+		}
+		return
+	}
+
+	sf := &sqliteFormatter.Formatter{RunId: *runIdFlag}
+	err := sf.ProcessMessages(newReader(os.Stdin), os.Stdout)
+	if err != nil {
+		log.Fatal("ERROR: ", err)
+	}
+}
+
+func newReader(in io.Reader) gio.ReadCloser {
+	var reader gio.ReadCloser
+	switch *formatFlag {
+	case "protobuf":
+		reader = gio.NewDelimitedReader(in, math.MaxInt32)
+	case "ndjson":
+		reader = fio.NewNdjsonReader(in)
+	default:
+		_, err := fmt.Fprintf(os.Stderr, "Unsupported format: %s\n", *formatFlag)
+		if err != nil {
+			log.Fatal("ERROR: ", err)
+		}
+	}
+	return reader
+}
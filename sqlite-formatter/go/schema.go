@@ -0,0 +1,52 @@
+package sqlite
+
+// Schema creates the tables the script inserts into, unless they exist, so
+// the results of many runs can be written to the same database. Times are
+// UTC, in the format of SQLite's date and time functions.
+const Schema = `CREATE TABLE IF NOT EXISTS test_runs (
+  id TEXT PRIMARY KEY,
+  started_at TEXT,
+  finished_at TEXT,
+  success INTEGER,
+  message TEXT,
+  implementation TEXT,
+  implementation_version TEXT,
+  ci TEXT,
+  ci_url TEXT,
+  git_revision TEXT,
+  git_branch TEXT
+);
+CREATE TABLE IF NOT EXISTS test_cases (
+  test_run_id TEXT NOT NULL,
+  id TEXT NOT NULL,
+  uri TEXT,
+  line INTEGER,
+  name TEXT,
+  attempt INTEGER,
+  retried INTEGER,
+  status TEXT,
+  started_at TEXT,
+  finished_at TEXT,
+  duration_ms REAL,
+  PRIMARY KEY (test_run_id, id)
+);
+CREATE TABLE IF NOT EXISTS test_steps (
+  test_run_id TEXT NOT NULL,
+  test_case_id TEXT NOT NULL,
+  position INTEGER NOT NULL,
+  text TEXT,
+  hook INTEGER,
+  status TEXT,
+  duration_ms REAL,
+  message TEXT,
+  PRIMARY KEY (test_run_id, test_case_id, position)
+);
+CREATE TABLE IF NOT EXISTS tags (
+  test_run_id TEXT NOT NULL,
+  test_case_id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  PRIMARY KEY (test_run_id, test_case_id, name)
+);
+CREATE INDEX IF NOT EXISTS test_cases_uri_line ON test_cases (uri, line);
+CREATE INDEX IF NOT EXISTS tags_name ON tags (name);
+`
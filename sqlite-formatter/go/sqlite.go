@@ -0,0 +1,210 @@
+/*
+Package sqlite implements a Cucumber formatter that writes a test run as a
+SQLite script, which creates the tables of the Schema and inserts the run,
+its test cases, their steps and tags.
+*/
+package sqlite
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/cucumber/messages-go/v13"
+	"github.com/cucumber/query-go"
+	gio "github.com/gogo/protobuf/io"
+)
+
+type Formatter struct {
+	// RunId is the id of the test run in the database, a random UUID when
+	// it is empty
+	RunId string
+
+	query *query.Query
+	out   *statementWriter
+}
+
+// ProcessMessages writes a script that inserts the run in a transaction,
+// committed when all messages are read, so a run is inserted completely or
+// not at all
+func (self *Formatter) ProcessMessages(reader gio.ReadCloser, stdout io.Writer) error {
+	if self.RunId == "" {
+		self.RunId = messages.UUID{}.NewId()
+	}
+	self.query = query.New()
+	self.out = &statementWriter{w: stdout}
+
+	self.out.write(Schema)
+	self.out.write("BEGIN;\n")
+	self.out.insert("test_runs", columns{"id": self.RunId})
+
+	for {
+		envelope := &messages.Envelope{}
+		err := reader.ReadMsg(envelope)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		self.processMessage(envelope)
+		if self.out.err != nil {
+			return self.out.err
+		}
+	}
+
+	self.out.write("COMMIT;\n")
+	return self.out.err
+}
+
+func (self *Formatter) processMessage(envelope *messages.Envelope) {
+	self.query.Update(envelope)
+	switch m := envelope.Message.(type) {
+	case *messages.Envelope_Meta:
+		meta := m.Meta
+		self.updateRun(columns{
+			"implementation":         meta.GetImplementation().GetName(),
+			"implementation_version": meta.GetImplementation().GetVersion(),
+			"ci":                     meta.GetCi().GetName(),
+			"ci_url":                 meta.GetCi().GetUrl(),
+			"git_revision":           meta.GetCi().GetGit().GetRevision(),
+			"git_branch":             meta.GetCi().GetGit().GetBranch(),
+		})
+
+	case *messages.Envelope_TestRunStarted:
+		self.updateRun(columns{"started_at": timestamp(m.TestRunStarted.Timestamp)})
+
+	case *messages.Envelope_TestStepFinished:
+		self.finishTestStep(m.TestStepFinished)
+
+	case *messages.Envelope_TestCaseFinished:
+		self.finishTestCase(m.TestCaseFinished)
+
+	case *messages.Envelope_TestRunFinished:
+		self.updateRun(columns{
+			"finished_at": timestamp(m.TestRunFinished.Timestamp),
+			"success":     m.TestRunFinished.Success,
+			"message":     m.TestRunFinished.Message,
+		})
+	}
+}
+
+func (self *Formatter) updateRun(values columns) {
+	self.out.update("test_runs", values, columns{"id": self.RunId})
+}
+
+func (self *Formatter) finishTestStep(finished *messages.TestStepFinished) {
+	run := self.query.TestCaseRun(finished.TestCaseStartedId)
+	result := finished.TestStepResult
+	if run == nil || run.TestCase == nil || result == nil {
+		return
+	}
+
+	values := columns{
+		"test_run_id":  self.RunId,
+		"test_case_id": finished.TestCaseStartedId,
+		"status":       status(result.Status),
+		"duration_ms":  durationMillis(result.Duration),
+		"message":      result.Message,
+		"text":         nil,
+	}
+	for position, testStep := range run.TestCase.TestSteps {
+		if testStep.Id != finished.TestStepId {
+			continue
+		}
+		values["position"] = position
+		values["hook"] = testStep.HookId != ""
+		if pickleStep := self.query.PickleStep(testStep.PickleStepId); pickleStep != nil {
+			values["text"] = pickleStep.Text
+		}
+		self.out.insert("test_steps", values)
+	}
+}
+
+func (self *Formatter) finishTestCase(finished *messages.TestCaseFinished) {
+	run := self.query.TestCaseRun(finished.TestCaseStartedId)
+	if run == nil {
+		return
+	}
+
+	values := columns{
+		"test_run_id": self.RunId,
+		"id":          finished.TestCaseStartedId,
+		"attempt":     run.Started.Attempt,
+		"retried":     run.WillBeRetried,
+		"status":      status(run.Status),
+		"started_at":  timestamp(run.Started.Timestamp),
+		"finished_at": timestamp(finished.Timestamp),
+		"duration_ms": nil,
+		"uri":         nil,
+		"name":        nil,
+		"line":        nil,
+	}
+	if run.Started.Timestamp != nil && finished.Timestamp != nil {
+		duration := messages.TimestampToGoTime(*finished.Timestamp).Sub(messages.TimestampToGoTime(*run.Started.Timestamp))
+		values["duration_ms"] = float64(duration.Nanoseconds()) / 1e6
+	}
+	if run.Pickle != nil {
+		values["uri"] = run.Pickle.Uri
+		values["name"] = run.Pickle.Name
+		if line := self.query.Line(run.Pickle.AstNodeIds); line > 0 {
+			values["line"] = line
+		}
+	}
+	self.out.insert("test_cases", values)
+
+	for _, tag := range run.Pickle.GetTags() {
+		self.out.insertOrIgnore("tags", columns{
+			"test_run_id":  self.RunId,
+			"test_case_id": finished.TestCaseStartedId,
+			"name":         tag.Name,
+		})
+	}
+}
+
+func status(status messages.TestStepFinished_TestStepResult_Status) string {
+	return strings.ToLower(status.String())
+}
+
+// timestamp is a time in the format of SQLite's date and time functions
+func timestamp(timestamp *messages.Timestamp) interface{} {
+	if timestamp == nil {
+		return nil
+	}
+	return messages.TimestampToGoTime(*timestamp).UTC().Format("2006-01-02 15:04:05.000")
+}
+
+func durationMillis(duration *messages.Duration) interface{} {
+	if duration == nil {
+		return nil
+	}
+	return float64(messages.DurationToGoDuration(*duration).Nanoseconds()) / 1e6
+}
+
+// literal is a value as a SQL literal. Empty strings and nil are NULL.
+func literal(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		if v == "" {
+			return "NULL", nil
+		}
+		return "'" + strings.ReplaceAll(strings.ReplaceAll(v, "\x00", ""), "'", "''") + "'", nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	case int:
+		return strconv.Itoa(v), nil
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("no SQL literal for %T", value)
+	}
+}
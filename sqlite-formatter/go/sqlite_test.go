@@ -0,0 +1,106 @@
+package sqlite
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cucumber/messages-go/v13"
+	fio "github.com/cucumber/messages-go/v13/io"
+	"github.com/cucumber/query-go/querytest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritesTheRunAsInserts(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	err := (&Formatter{RunId: "run-1"}).ProcessMessages(querytest.NewReader(t, querytest.FailingRun()), stdout)
+	require.NoError(t, err)
+
+	require.True(t, strings.HasPrefix(stdout.String(), Schema))
+	script := strings.TrimPrefix(stdout.String(), Schema)
+	require.True(t, strings.HasPrefix(script, "BEGIN;\n"+
+		"INSERT INTO test_runs (id) VALUES ('run-1');\n"+
+		"UPDATE test_runs SET ci = 'GitHub Actions', ci_url = NULL, git_branch = 'main', git_revision = 'abc123', implementation = 'cucumber-go', implementation_version = '1.0.0' WHERE id = 'run-1';\n"+
+		"UPDATE test_runs SET started_at = '2020-08-10 21:00:00.000' WHERE id = 'run-1';\n"), script)
+	require.Contains(t, script, "\n"+
+		"INSERT INTO test_steps (duration_ms, hook, message, position, status, test_case_id, test_run_id, text) VALUES (1, 1, NULL, 0, 'passed', 'tcs3', 'run-1', NULL);\n"+
+		"INSERT INTO test_steps (duration_ms, hook, message, position, status, test_case_id, test_run_id, text) VALUES (1, 0, NULL, 1, 'passed', 'tcs3', 'run-1', 'I am hungry');\n"+
+		"INSERT INTO test_steps (duration_ms, hook, message, position, status, test_case_id, test_run_id, text) VALUES (1, 0, 'expected 0\n\tat eating.go:12', 2, 'failed', 'tcs3', 'run-1', 'I eat 6');\n"+
+		"INSERT INTO test_steps (duration_ms, hook, message, position, status, test_case_id, test_run_id, text) VALUES (1, 1, 'cleanup failed', 3, 'failed', 'tcs3', 'run-1', NULL);\n"+
+		"INSERT INTO test_cases (attempt, duration_ms, finished_at, id, line, name, retried, started_at, status, test_run_id, uri) VALUES (0, 4, '2020-08-10 21:00:03.004', 'tcs3', 18, 'Eating 6', 0, '2020-08-10 21:00:03.000', 'failed', 'run-1', 'features/eating.feature');\n"+
+		"INSERT OR IGNORE INTO tags (name, test_case_id, test_run_id) VALUES ('@severity=critical', 'tcs3', 'run-1');\n"+
+		"INSERT OR IGNORE INTO tags (name, test_case_id, test_run_id) VALUES ('@tms=ABC-1', 'tcs3', 'run-1');\n")
+	require.Contains(t, script, "VALUES (0, 2, '2020-08-10 21:00:01.002', 'tcs1', 7, 'Eating', 0, '2020-08-10 21:00:01.000', 'undefined', 'run-1', 'features/eating.feature');\n")
+	require.True(t, strings.HasSuffix(script, "\n"+
+		"UPDATE test_runs SET finished_at = '2020-08-10 21:00:04.000', message = NULL, success = 0 WHERE id = 'run-1';\n"+
+		"COMMIT;\n"), script)
+}
+
+func TestLeavesDuplicateTagsToTheDatabaseToIgnore(t *testing.T) {
+	envelopes := querytest.FailingRun()
+	for _, envelope := range envelopes {
+		if pickle := envelope.GetPickle(); pickle != nil {
+			pickle.Tags = append(pickle.Tags, pickle.Tags...)
+		}
+	}
+	stdout := &bytes.Buffer{}
+	err := (&Formatter{RunId: "run-1"}).ProcessMessages(querytest.NewReader(t, envelopes), stdout)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, strings.Count(stdout.String(), "INSERT OR IGNORE INTO tags (name, test_case_id, test_run_id) VALUES ('@smoke', 'tcs1', 'run-1');"))
+}
+
+func TestMarksRetriedAttempts(t *testing.T) {
+	envelopes := append(querytest.Feature(),
+		querytest.TestCase("tc1", "p3", querytest.TestStep("ts1", "ps6", "")),
+		querytest.TestCaseStarted("tcs1", "tc1", 0),
+		querytest.TestStepFinished("tcs1", "ts1", messages.TestStepFinished_TestStepResult_FAILED, "flaky", true),
+		querytest.TestCaseFinished("tcs1"),
+		querytest.TestCaseStarted("tcs2", "tc1", 1),
+		querytest.TestStepFinished("tcs2", "ts1", messages.TestStepFinished_TestStepResult_PASSED, "", false),
+		querytest.TestCaseFinished("tcs2"),
+	)
+	stdout := &bytes.Buffer{}
+	err := (&Formatter{RunId: "run-1"}).ProcessMessages(querytest.NewReader(t, envelopes), stdout)
+	require.NoError(t, err)
+
+	require.Contains(t, stdout.String(), "VALUES (0, NULL, NULL, 'tcs1', 18, 'Eating 6', 1, NULL, 'failed', 'run-1', 'features/eating.feature');")
+	require.Contains(t, stdout.String(), "VALUES (1, NULL, NULL, 'tcs2', 18, 'Eating 6', 0, NULL, 'passed', 'run-1', 'features/eating.feature');")
+}
+
+func TestDoesNotCommitWhenReadingFails(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	err := (&Formatter{RunId: "run-1"}).ProcessMessages(fio.NewNdjsonReader(strings.NewReader(`{"meta":`)), stdout)
+	require.Error(t, err)
+	require.NotContains(t, stdout.String(), "COMMIT;")
+}
+
+func TestWritesLiterals(t *testing.T) {
+	for value, expected := range map[interface{}]string{
+		nil:       "NULL",
+		"":        "NULL",
+		"O'Brien": "'O''Brien'",
+		"a\x00b":  "'ab'",
+		true:      "1",
+		false:     "0",
+		42:        "42",
+		uint32(7): "7",
+		0.0001:    "0.0001",
+	} {
+		s, err := literal(value)
+		require.NoError(t, err)
+		require.Equal(t, expected, s)
+	}
+}
+
+func TestStopsAtValuesWithoutALiteral(t *testing.T) {
+	_, err := literal(int64(42))
+	require.EqualError(t, err, "no SQL literal for int64")
+
+	stdout := &bytes.Buffer{}
+	out := &statementWriter{w: stdout}
+	out.insert("test_runs", columns{"id": "run-1", "success": int64(1)})
+	out.write("COMMIT;\n")
+	require.EqualError(t, out.err, "no SQL literal for int64")
+	require.Empty(t, stdout.String())
+}
@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"io"
+	"sort"
+	"strings"
+)
+
+// columns are values by column name
+type columns map[string]interface{}
+
+func (self columns) names() []string {
+	names := make([]string, 0, len(self))
+	for name := range self {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// statementWriter writes statements until writing one fails, or a value
+// has no SQL literal, and keeps the error
+type statementWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (self *statementWriter) write(s string) {
+	if self.err != nil {
+		return
+	}
+	_, self.err = io.WriteString(self.w, s)
+}
+
+func (self *statementWriter) insert(table string, values columns) {
+	self.writeInsert("INSERT", table, values)
+}
+
+func (self *statementWriter) insertOrIgnore(table string, values columns) {
+	self.writeInsert("INSERT OR IGNORE", table, values)
+}
+
+func (self *statementWriter) writeInsert(insert string, table string, values columns) {
+	names := values.names()
+	literals := make([]string, len(names))
+	for i, name := range names {
+		literals[i] = self.literal(values[name])
+	}
+	self.write(insert + " INTO " + table + " (" + strings.Join(names, ", ") + ") VALUES (" + strings.Join(literals, ", ") + ");\n")
+}
+
+func (self *statementWriter) update(table string, values columns, where columns) {
+	self.write("UPDATE " + table + " SET " + self.assignments(values, ", ") + " WHERE " + self.assignments(where, " AND ") + ";\n")
+}
+
+func (self *statementWriter) assignments(values columns, separator string) string {
+	names := values.names()
+	for i, name := range names {
+		names[i] = name + " = " + self.literal(values[name])
+	}
+	return strings.Join(names, separator)
+}
+
+// literal keeps the error of a value without a SQL literal, so the
+// statement with it is not written
+func (self *statementWriter) literal(value interface{}) string {
+	s, err := literal(value)
+	if err != nil && self.err == nil {
+		self.err = err
+	}
+	return s
+}
@@ -0,0 +1,291 @@
+package sqlite
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+)
+
+// TrendsQuery selects the attempts at scenarios that were not retried, with
+// their runs, as the TestCaseRows that ReadTestCaseRows reads from the JSON
+// output of the sqlite3 shell:
+//
+//	sqlite3 -json cucumber.db "<TrendsQuery>"
+const TrendsQuery = `SELECT r.id AS run_id, r.started_at AS run_started_at,
+  c.uri, c.line, c.name, c.status, c.duration_ms
+FROM test_cases c
+JOIN test_runs r ON r.id = c.test_run_id
+WHERE NOT c.retried AND c.uri IS NOT NULL AND c.line IS NOT NULL
+ORDER BY r.started_at, r.id, c.started_at;
+`
+
+// TestCaseRow is a row of the TrendsQuery
+type TestCaseRow struct {
+	RunId        string   `json:"run_id"`
+	RunStartedAt string   `json:"run_started_at"`
+	Uri          string   `json:"uri"`
+	Line         int      `json:"line"`
+	Name         string   `json:"name"`
+	Status       string   `json:"status"`
+	DurationMs   *float64 `json:"duration_ms"`
+}
+
+// ReadTestCaseRows reads the JSON array the sqlite3 shell writes with
+// -json, which is nothing at all when there are no rows
+func ReadTestCaseRows(r io.Reader) ([]*TestCaseRow, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]*TestCaseRow, 0)
+	if len(bytes.TrimSpace(b)) == 0 {
+		return rows, nil
+	}
+	err = json.Unmarshal(b, &rows)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Trends are the pass rates and durations of scenarios over many runs, for
+// dashboards. Lists are [] when they are empty, never null.
+type Trends struct {
+	// Runs are ordered by the time they started, oldest first
+	Runs []*RunTrend `json:"runs"`
+	// Scenarios are ordered by URI and line
+	Scenarios []*ScenarioTrend `json:"scenarios"`
+	// NewFailures are the scenarios that did not pass in the last run,
+	// but did in the run before that they were in, or were not in any run
+	// before. Skipped scenarios count as passed.
+	NewFailures []*NewFailure `json:"newFailures"`
+}
+
+type RunTrend struct {
+	Id        string `json:"id"`
+	StartedAt string `json:"startedAt,omitempty"`
+	// Total is the number of scenarios in the run
+	Total    int     `json:"total"`
+	Passed   int     `json:"passed"`
+	PassRate float64 `json:"passRate"`
+	// Durations are the percentiles of the durations of the scenarios, left
+	// out when none has a duration
+	Durations *Percentiles `json:"durations,omitempty"`
+}
+
+// ScenarioTrend is a scenario, or an example of a scenario outline, over the
+// runs it was in. Scenarios are told apart by URI and line, so a scenario
+// that moves to another line is a new scenario.
+type ScenarioTrend struct {
+	Uri  string `json:"uri"`
+	Line int    `json:"line"`
+	// Name is the name in the last run
+	Name string         `json:"name"`
+	Runs []*ScenarioRun `json:"runs"`
+	// PassRate is the share of the Runs that passed
+	PassRate float64 `json:"passRate"`
+	// RecentPassRate is the share of the last Window of the Runs that
+	// passed. Compared to the PassRate, it tells if the scenario got more
+	// or less reliable.
+	RecentPassRate float64      `json:"recentPassRate"`
+	Durations      *Percentiles `json:"durations,omitempty"`
+}
+
+type ScenarioRun struct {
+	RunId      string   `json:"runId"`
+	Status     string   `json:"status"`
+	DurationMs *float64 `json:"durationMs,omitempty"`
+	name       string
+}
+
+// Percentiles of durations in milliseconds, by the nearest-rank method
+type Percentiles struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+type NewFailure struct {
+	Uri    string `json:"uri"`
+	Line   int    `json:"line"`
+	Name   string `json:"name"`
+	RunId  string `json:"runId"`
+	Status string `json:"status"`
+	// PreviousRunId is the run before that the scenario was in, empty if it
+	// was not in any
+	PreviousRunId  string `json:"previousRunId,omitempty"`
+	PreviousStatus string `json:"previousStatus,omitempty"`
+}
+
+type Analyzer struct {
+	// Window is the number of runs of a scenario its RecentPassRate is
+	// computed over, 10 by default
+	Window int
+}
+
+type scenarioKey struct {
+	uri  string
+	line int
+}
+
+// Analyze computes the trends of the rows of the TrendsQuery
+func (self *Analyzer) Analyze(rows []*TestCaseRow) *Trends {
+	window := self.Window
+	if window <= 0 {
+		window = 10
+	}
+
+	runs := make([]*RunTrend, 0)
+	runsById := make(map[string]*RunTrend)
+	runDurations := make(map[string][]float64)
+	scenarios := make(map[scenarioKey]*ScenarioTrend)
+	for _, row := range rows {
+		run := runsById[row.RunId]
+		if run == nil {
+			run = &RunTrend{Id: row.RunId, StartedAt: row.RunStartedAt}
+			runsById[row.RunId] = run
+			runs = append(runs, run)
+		}
+		run.Total++
+		if row.Status == "passed" {
+			run.Passed++
+		}
+		if row.DurationMs != nil {
+			runDurations[row.RunId] = append(runDurations[row.RunId], *row.DurationMs)
+		}
+
+		key := scenarioKey{row.Uri, row.Line}
+		scenario := scenarios[key]
+		if scenario == nil {
+			scenario = &ScenarioTrend{Uri: row.Uri, Line: row.Line, Runs: make([]*ScenarioRun, 0)}
+			scenarios[key] = scenario
+		}
+		scenario.Runs = append(scenario.Runs, &ScenarioRun{
+			RunId:      row.RunId,
+			Status:     row.Status,
+			DurationMs: row.DurationMs,
+			name:       row.Name,
+		})
+	}
+
+	// The rows of the TrendsQuery are in order, but the order of runs that
+	// started at the same time, or of rows read from elsewhere, is kept
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].StartedAt < runs[j].StartedAt })
+	positions := make(map[string]int)
+	for i, run := range runs {
+		positions[run.Id] = i
+		run.PassRate = rate(run.Passed, run.Total)
+		run.Durations = percentiles(runDurations[run.Id])
+	}
+
+	trends := &Trends{Runs: runs, Scenarios: make([]*ScenarioTrend, 0, len(scenarios)), NewFailures: make([]*NewFailure, 0)}
+	for _, scenario := range scenarios {
+		sort.SliceStable(scenario.Runs, func(i, j int) bool {
+			return positions[scenario.Runs[i].RunId] < positions[scenario.Runs[j].RunId]
+		})
+		trends.Scenarios = append(trends.Scenarios, scenario)
+	}
+	sort.Slice(trends.Scenarios, func(i, j int) bool {
+		a, b := trends.Scenarios[i], trends.Scenarios[j]
+		if a.Uri != b.Uri {
+			return a.Uri < b.Uri
+		}
+		return a.Line < b.Line
+	})
+
+	for _, scenario := range trends.Scenarios {
+		scenario.Name = scenario.Runs[len(scenario.Runs)-1].name
+		scenario.PassRate = passRate(scenario.Runs)
+		recent := scenario.Runs
+		if len(recent) > window {
+			recent = recent[len(recent)-window:]
+		}
+		scenario.RecentPassRate = passRate(recent)
+		var durations []float64
+		for _, run := range scenario.Runs {
+			if run.DurationMs != nil {
+				durations = append(durations, *run.DurationMs)
+			}
+		}
+		scenario.Durations = percentiles(durations)
+
+		if newFailure := newFailure(scenario, runs); newFailure != nil {
+			trends.NewFailures = append(trends.NewFailures, newFailure)
+		}
+	}
+	return trends
+}
+
+// newFailure is the failure of a scenario in the last run, if it passed in
+// the run before that it was in
+func newFailure(scenario *ScenarioTrend, runs []*RunTrend) *NewFailure {
+	if len(runs) == 0 {
+		return nil
+	}
+	lastRunId := runs[len(runs)-1].Id
+	n := len(scenario.Runs)
+	last := scenario.Runs[n-1]
+	if last.RunId != lastRunId || passed(last.Status) {
+		return nil
+	}
+	failure := &NewFailure{
+		Uri:    scenario.Uri,
+		Line:   scenario.Line,
+		Name:   scenario.Name,
+		RunId:  last.RunId,
+		Status: last.Status,
+	}
+	for i := n - 2; i >= 0; i-- {
+		previous := scenario.Runs[i]
+		if previous.RunId == lastRunId {
+			continue
+		}
+		if !passed(previous.Status) {
+			return nil
+		}
+		failure.PreviousRunId = previous.RunId
+		failure.PreviousStatus = previous.Status
+		break
+	}
+	return failure
+}
+
+func passed(status string) bool {
+	return status == "passed" || status == "skipped"
+}
+
+func passRate(runs []*ScenarioRun) float64 {
+	n := 0
+	for _, run := range runs {
+		if run.Status == "passed" {
+			n++
+		}
+	}
+	return rate(n, len(runs))
+}
+
+func rate(n int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+func percentiles(durations []float64) *Percentiles {
+	if len(durations) == 0 {
+		return nil
+	}
+	sorted := append([]float64(nil), durations...)
+	sort.Float64s(sorted)
+	rank := func(p float64) float64 {
+		i := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+		if i < 0 {
+			i = 0
+		}
+		return sorted[i]
+	}
+	return &Percentiles{P50: rank(50), P90: rank(90), P99: rank(99), Max: sorted[len(sorted)-1]}
+}
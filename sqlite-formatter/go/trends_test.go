@@ -0,0 +1,121 @@
+package sqlite
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadsTheRowsOfTheSqliteShell(t *testing.T) {
+	rows, err := ReadTestCaseRows(strings.NewReader(`[{"run_id":"run-1","run_started_at":"2020-08-10 21:00:00.000","uri":"features/eating.feature","line":7,"name":"Eating","status":"passed","duration_ms":2.0},
+{"run_id":"run-1","run_started_at":null,"uri":"features/eating.feature","line":17,"name":"Eating 5","status":"undefined","duration_ms":null}]
+`))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, 7, rows[0].Line)
+	require.Equal(t, 2.0, *rows[0].DurationMs)
+	require.Empty(t, rows[1].RunStartedAt)
+	require.Nil(t, rows[1].DurationMs)
+
+	rows, err = ReadTestCaseRows(strings.NewReader(""))
+	require.NoError(t, err)
+	require.Empty(t, rows)
+
+	_, err = ReadTestCaseRows(strings.NewReader(`[{"run_id":`))
+	require.Error(t, err)
+}
+
+func TestFollowsTheScenariosOverTheRuns(t *testing.T) {
+	trends := (&Analyzer{Window: 2}).Analyze([]*TestCaseRow{
+		row("run-1", "2020-08-10 21:00:00.000", 7, "Eating", "passed", 10),
+		row("run-1", "2020-08-10 21:00:00.000", 17, "Eating 5", "failed", 20),
+		row("run-2", "2020-08-11 21:00:00.000", 7, "Eating", "passed", 30),
+		row("run-2", "2020-08-11 21:00:00.000", 17, "Eating 5", "passed", 40),
+		row("run-3", "2020-08-12 21:00:00.000", 7, "Eating lunch", "failed", 50),
+		row("run-3", "2020-08-12 21:00:00.000", 17, "Eating 5", "passed", 60),
+	})
+
+	require.Len(t, trends.Runs, 3)
+	require.Equal(t, "run-1", trends.Runs[0].Id)
+	require.Equal(t, 2, trends.Runs[0].Total)
+	require.Equal(t, 0.5, trends.Runs[0].PassRate)
+	require.Equal(t, 1.0, trends.Runs[1].PassRate)
+
+	require.Len(t, trends.Scenarios, 2)
+	eating := trends.Scenarios[0]
+	require.Equal(t, 7, eating.Line)
+	require.Equal(t, "Eating lunch", eating.Name)
+	require.Equal(t, []string{"passed", "passed", "failed"}, statuses(eating.Runs))
+	require.InDelta(t, 2.0/3, eating.PassRate, 1e-9)
+	require.Equal(t, 0.5, eating.RecentPassRate)
+	require.Equal(t, &Percentiles{P50: 30, P90: 50, P99: 50, Max: 50}, eating.Durations)
+	require.Equal(t, 1.0, trends.Scenarios[1].RecentPassRate)
+}
+
+func TestOrdersTheRunsByTheTimeTheyStarted(t *testing.T) {
+	trends := (&Analyzer{}).Analyze([]*TestCaseRow{
+		row("run-2", "2020-08-11 21:00:00.000", 7, "Eating", "failed", 1),
+		row("run-1", "2020-08-10 21:00:00.000", 7, "Eating", "passed", 1),
+	})
+
+	require.Equal(t, "run-1", trends.Runs[0].Id)
+	require.Equal(t, []string{"passed", "failed"}, statuses(trends.Scenarios[0].Runs))
+	require.Len(t, trends.NewFailures, 1)
+}
+
+func TestDetectsNewFailures(t *testing.T) {
+	trends := (&Analyzer{}).Analyze([]*TestCaseRow{
+		row("run-1", "2020-08-10 21:00:00.000", 7, "Eating", "passed", 1),
+		row("run-1", "2020-08-10 21:00:00.000", 17, "Eating 5", "failed", 1),
+		row("run-1", "2020-08-10 21:00:00.000", 18, "Eating 6", "skipped", 1),
+		row("run-2", "2020-08-11 21:00:00.000", 7, "Eating", "failed", 1),
+		row("run-2", "2020-08-11 21:00:00.000", 17, "Eating 5", "failed", 1),
+		row("run-2", "2020-08-11 21:00:00.000", 18, "Eating 6", "undefined", 1),
+		row("run-2", "2020-08-11 21:00:00.000", 21, "Eating 7", "failed", 1),
+		row("run-2", "2020-08-11 21:00:00.000", 22, "Eating 8", "skipped", 1),
+	})
+
+	require.Equal(t, []*NewFailure{
+		{Uri: "features/eating.feature", Line: 7, Name: "Eating", RunId: "run-2", Status: "failed", PreviousRunId: "run-1", PreviousStatus: "passed"},
+		{Uri: "features/eating.feature", Line: 18, Name: "Eating 6", RunId: "run-2", Status: "undefined", PreviousRunId: "run-1", PreviousStatus: "skipped"},
+		{Uri: "features/eating.feature", Line: 21, Name: "Eating 7", RunId: "run-2", Status: "failed"},
+	}, trends.NewFailures)
+}
+
+func TestWritesEmptyListsForNoRuns(t *testing.T) {
+	b, err := json.Marshal((&Analyzer{}).Analyze(nil))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"runs":[],"scenarios":[],"newFailures":[]}`, string(b))
+}
+
+func TestComputesNearestRankPercentiles(t *testing.T) {
+	var durations []float64
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, float64(i))
+	}
+	require.Equal(t, &Percentiles{P50: 50, P90: 90, P99: 99, Max: 100}, percentiles(durations))
+	require.Equal(t, &Percentiles{P50: 3, P90: 3, P99: 3, Max: 3}, percentiles([]float64{3}))
+	require.Nil(t, percentiles(nil))
+}
+
+func row(runId string, runStartedAt string, line int, name string, status string, durationMs float64) *TestCaseRow {
+	return &TestCaseRow{
+		RunId:        runId,
+		RunStartedAt: runStartedAt,
+		Uri:          "features/eating.feature",
+		Line:         line,
+		Name:         name,
+		Status:       status,
+		DurationMs:   &durationMs,
+	}
+}
+
+func statuses(runs []*ScenarioRun) []string {
+	var statuses []string
+	for _, run := range runs {
+		statuses = append(statuses, run.Status)
+	}
+	return statuses
+}
@@ -0,0 +1,36 @@
+package tagexpressions
+
+import (
+	"errors"
+)
+
+// ErrorCode identifies the kind of a syntax error of a tag expression, so
+// tools can link it to its documentation or suppress it. Codes don't change
+// between releases, unlike messages.
+type ErrorCode string
+
+const (
+	ExpectedOperandCode             ErrorCode = "TE101"
+	ExpectedOperatorCode            ErrorCode = "TE102"
+	UnmatchedClosingParenthesisCode ErrorCode = "TE103"
+	UnmatchedOpeningParenthesisCode ErrorCode = "TE104"
+)
+
+// ErrorCodeOf returns the code of a syntax error returned by Parse, or ""
+// for other errors
+func ErrorCodeOf(err error) ErrorCode {
+	var se *syntaxError
+	if errors.As(err, &se) {
+		return se.code
+	}
+	return ""
+}
+
+type syntaxError struct {
+	msg  string
+	code ErrorCode
+}
+
+func (e *syntaxError) Error() string {
+	return e.msg
+}
@@ -2,7 +2,6 @@ package tagexpressions
 
 import (
 	"bytes"
-	"errors"
 	"fmt"
 	"strings"
 	"unicode"
@@ -58,7 +57,7 @@ func Parse(infix string) (Evaluatable, error) {
 				pushExpr(operators.Pop(), expressions)
 			}
 			if operators.Len() == 0 {
-				return nil, errors.New("Syntax error. Unmatched )")
+				return nil, &syntaxError{"Syntax error. Unmatched )", UnmatchedClosingParenthesisCode}
 			}
 			if operators.Peek() == "(" {
 				operators.Pop()
@@ -75,7 +74,7 @@ func Parse(infix string) (Evaluatable, error) {
 
 	for operators.Len() > 0 {
 		if operators.Peek() == "(" {
-			return nil, errors.New("Syntax error. Unmatched (")
+			return nil, &syntaxError{"Syntax error. Unmatched (", UnmatchedOpeningParenthesisCode}
 		}
 		pushExpr(operators.Pop(), expressions)
 	}
@@ -152,7 +151,11 @@ func isOp(token string) bool {
 
 func check(expectedTokenType, tokenType string) error {
 	if expectedTokenType != tokenType {
-		return fmt.Errorf("Syntax error. Expected %s", expectedTokenType)
+		code := ExpectedOperandCode
+		if expectedTokenType == OPERATOR {
+			code = ExpectedOperatorCode
+		}
+		return &syntaxError{fmt.Sprintf("Syntax error. Expected %s", expectedTokenType), code}
 	}
 	return nil
 }
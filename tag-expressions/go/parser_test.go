@@ -69,46 +69,55 @@ func TestParseForSyntaxErrors(t *testing.T) {
 		name     string
 		given    string
 		expected string
+		code     ErrorCode
 	}{
 		{
 			name:     "no operators",
 			given:    "a b",
 			expected: "Syntax error. Expected operator",
+			code:     ExpectedOperatorCode,
 		},
 		{
 			name:     "missing operator in binary expression",
 			given:    "@a @b or",
 			expected: "Syntax error. Expected operator",
+			code:     ExpectedOperatorCode,
 		},
 		{
 			name:     "missing operator in unary expression",
 			given:    "@a and (@b not)",
 			expected: "Syntax error. Expected operator",
+			code:     ExpectedOperatorCode,
 		},
 		{
 			name:     "missing operator between operands",
 			given:    "@a and (@b @c) or",
 			expected: "Syntax error. Expected operator",
+			code:     ExpectedOperatorCode,
 		},
 		{
 			name:     "no operands",
 			given:    "or or",
 			expected: "Syntax error. Expected operand",
+			code:     ExpectedOperandCode,
 		},
 		{
 			name:     "missing operand",
 			given:    "@a and or",
 			expected: "Syntax error. Expected operand",
+			code:     ExpectedOperandCode,
 		},
 		{
 			name:     "unmatched closing parenthesis",
 			given:    "( a and b ) )",
 			expected: "Syntax error. Unmatched )",
+			code:     UnmatchedClosingParenthesisCode,
 		},
 		{
 			name:     "unmatched opening parenthesis",
 			given:    "( ( a and b )",
 			expected: "Syntax error. Unmatched (",
+			code:     UnmatchedOpeningParenthesisCode,
 		},
 	}
 
@@ -120,6 +129,7 @@ func TestParseForSyntaxErrors(t *testing.T) {
 			_, err := Parse(tc.given)
 			require.Error(t, err)
 			require.Equal(t, tc.expected, err.Error())
+			require.Equal(t, tc.code, ErrorCodeOf(err))
 		})
 	}
 }
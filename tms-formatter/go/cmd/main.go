@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"os"
+
+	fio "github.com/cucumber/messages-go/v13/io"
+	tmsFormatter "github.com/cucumber/tms-formatter-go"
+	gio "github.com/gogo/protobuf/io"
+)
+
+var formatFlag = flag.String("format", "protobuf", "output format")
+var systemFlag = flag.String("system", "", "test management system: testrail, xray or zephyr")
+var urlFlag = flag.String("url", "", "endpoint to upload the results to, or print them when empty")
+var testExecutionFlag = flag.String("test-execution", "", "Xray: key of the test execution to add the results to")
+var testPlanFlag = flag.String("test-plan", "", "Xray: key of the test plan of a new test execution")
+var summaryFlag = flag.String("summary", "", "Xray: summary of a new test execution")
+
+func main() {
+	flag.Parse()
+
+	tf := &tmsFormatter.Formatter{}
+	switch *systemFlag {
+	case "testrail":
+		tf.Adapter = &tmsFormatter.TestRail{}
+	case "xray":
+		tf.Adapter = &tmsFormatter.Xray{
+			TestExecutionKey: *testExecutionFlag,
+			TestPlanKey:      *testPlanFlag,
+			Summary:          *summaryFlag,
+		}
+	case "zephyr":
+		tf.Adapter = &tmsFormatter.Zephyr{}
+	default:
+		log.Fatal("ERROR: ", fmt.Errorf("Unsupported system: %s", *systemFlag))
+	}
+	if *urlFlag != "" {
+		uploader := &tmsFormatter.HTTPUploader{URL: *urlFlag, Header: authorization()}
+		if *systemFlag == "zephyr" {
+			uploader.FormField = "file"
+		}
+		tf.Uploader = uploader
+	}
+
+	err := tf.ProcessMessages(newReader(os.Stdin), os.Stdout)
+	if err != nil {
+		log.Fatal("ERROR: ", err)
+	}
+}
+
+// authorization is a bearer TMS_TOKEN, or the basic authentication of
+// TMS_USER and TMS_PASSWORD
+func authorization() http.Header {
+	request := &http.Request{Header: http.Header{}}
+	if token := os.Getenv("TMS_TOKEN"); token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	} else if user := os.Getenv("TMS_USER"); user != "" {
+		request.SetBasicAuth(user, os.Getenv("TMS_PASSWORD"))
+	}
+	return request.Header
+}
+
+func newReader(in io.Reader) gio.ReadCloser {
+	var reader gio.ReadCloser
+	switch *formatFlag {
+	case "protobuf":
+		reader = gio.NewDelimitedReader(in, math.MaxInt32)
+	case "ndjson":
+		reader = fio.NewNdjsonReader(in)
+	default:
+		_, err := fmt.Fprintf(os.Stderr, "Unsupported format: %s\n", *formatFlag)
+		if err != nil {
+			log.Fatal("ERROR: ", err)
+		}
+	}
+	return reader
+}
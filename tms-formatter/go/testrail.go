@@ -0,0 +1,87 @@
+package tms
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cucumber/messages-go/v13"
+)
+
+// TestRail statuses
+const (
+	TestRailPassed  = 1
+	TestRailBlocked = 2
+	TestRailRetest  = 4
+	TestRailFailed  = 5
+)
+
+// TestRail makes the payload of TestRail's add_results_for_cases endpoint.
+// Tests are tagged with the ids of cases, like @tms=C123.
+type TestRail struct{}
+
+type TestRailResults struct {
+	Results []*TestRailResult `json:"results"`
+}
+
+type TestRailResult struct {
+	CaseId   int    `json:"case_id"`
+	StatusId int    `json:"status_id"`
+	Comment  string `json:"comment,omitempty"`
+	Elapsed  string `json:"elapsed,omitempty"`
+}
+
+func (self *TestRail) Payload(run *Run) (interface{}, error) {
+	payload := &TestRailResults{Results: make([]*TestRailResult, 0, len(run.Results))}
+	for _, result := range run.Results {
+		caseId, err := strconv.Atoi(strings.TrimPrefix(result.TestId, "C"))
+		if err != nil {
+			return nil, fmt.Errorf("not a TestRail case id: %s", result.TestId)
+		}
+		payload.Results = append(payload.Results, &TestRailResult{
+			CaseId:   caseId,
+			StatusId: testRailStatus(result.Status),
+			Comment:  result.Comment,
+			Elapsed:  testRailTimespan(result.Duration),
+		})
+	}
+	return payload, nil
+}
+
+// testRailStatus is the status of a result. Scenarios that are pending or
+// undefined need to be tested again, and skipped ones were blocked.
+func testRailStatus(status messages.TestStepFinished_TestStepResult_Status) int {
+	switch status {
+	case messages.TestStepFinished_TestStepResult_PASSED:
+		return TestRailPassed
+	case messages.TestStepFinished_TestStepResult_SKIPPED:
+		return TestRailBlocked
+	case messages.TestStepFinished_TestStepResult_PENDING,
+		messages.TestStepFinished_TestStepResult_UNDEFINED,
+		messages.TestStepFinished_TestStepResult_UNKNOWN:
+		return TestRailRetest
+	default:
+		return TestRailFailed
+	}
+}
+
+// testRailTimespan is a duration as a TestRail timespan, like 1m 5s. TestRail
+// has no timespans shorter than a second, so durations are rounded up.
+func testRailTimespan(duration time.Duration) string {
+	if duration <= 0 {
+		return ""
+	}
+	seconds := int((duration + time.Second - 1) / time.Second)
+	var parts []string
+	if hours := seconds / 3600; hours > 0 {
+		parts = append(parts, strconv.Itoa(hours)+"h")
+	}
+	if minutes := seconds % 3600 / 60; minutes > 0 {
+		parts = append(parts, strconv.Itoa(minutes)+"m")
+	}
+	if seconds%60 > 0 {
+		parts = append(parts, strconv.Itoa(seconds%60)+"s")
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,189 @@
+/*
+Package tms implements a Cucumber formatter that exports the results of
+scenarios tagged with the id of a test in a test management system, like
+@tms=ABC-123, as the payload an Adapter makes for that system, and uploads
+it with an Uploader.
+*/
+package tms
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cucumber/messages-go/v13"
+	"github.com/cucumber/query-go"
+	gio "github.com/gogo/protobuf/io"
+)
+
+// tagPrefix starts the tags of scenarios with the id of a test
+const tagPrefix = "@tms="
+
+// Adapter makes the payload a test management system imports results from
+type Adapter interface {
+	Payload(run *Run) (interface{}, error)
+}
+
+// Uploader sends a payload to a test management system
+type Uploader interface {
+	Upload(payload []byte) error
+}
+
+// Run is the results of the tests of a test run
+type Run struct {
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Results    []*Result
+}
+
+// Result is the result of a test in a test management system, made of the
+// results of the scenarios tagged with its id. A test can be tagged on an
+// outline, whose examples are all its scenarios.
+type Result struct {
+	TestId     string
+	Status     messages.TestStepFinished_TestStepResult_Status
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// Duration is the sum of the durations of the scenarios
+	Duration time.Duration
+	// Comment lists the scenarios that did not pass, and why
+	Comment string
+	// Sources are the locations of the scenarios, as <uri>:<line>
+	Sources []string
+}
+
+type Formatter struct {
+	Adapter Adapter
+	// Uploader uploads the payload. When it is nil, the payload is written
+	// to STDOUT instead.
+	Uploader Uploader
+
+	results     []*Result
+	resultsById map[string]*Result
+	query       *query.Query
+}
+
+// ProcessMessages exports the results when the TestRunFinished message is
+// read. Nothing is exported if no scenario is tagged with the id of a test.
+func (self *Formatter) ProcessMessages(reader gio.ReadCloser, stdout io.Writer) error {
+	self.results = make([]*Result, 0)
+	self.resultsById = make(map[string]*Result)
+	self.query = query.New()
+
+	for {
+		envelope := &messages.Envelope{}
+		err := reader.ReadMsg(envelope)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		self.query.Update(envelope)
+		switch m := envelope.Message.(type) {
+		case *messages.Envelope_TestCaseFinished:
+			self.finishTestCase(m.TestCaseFinished)
+
+		case *messages.Envelope_TestRunFinished:
+			err = self.export(goTime(m.TestRunFinished.Timestamp), stdout)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// finishTestCase adds the last attempt at a scenario to the results of the
+// tests it is tagged with
+func (self *Formatter) finishTestCase(finished *messages.TestCaseFinished) {
+	attempt := self.query.TestCaseRun(finished.TestCaseStartedId)
+	if attempt == nil || attempt.Pickle == nil || attempt.WillBeRetried {
+		return
+	}
+
+	startedAt := goTime(attempt.Started.Timestamp)
+	finishedAt := goTime(finished.Timestamp)
+	source := self.source(attempt.Pickle)
+	for _, testId := range testIds(attempt.Pickle) {
+		result := self.resultsById[testId]
+		if result == nil {
+			result = &Result{
+				TestId:    testId,
+				Status:    messages.TestStepFinished_TestStepResult_PASSED,
+				StartedAt: startedAt,
+			}
+			self.resultsById[testId] = result
+			self.results = append(self.results, result)
+		}
+		if attempt.Status > result.Status {
+			result.Status = attempt.Status
+		}
+		if startedAt.Before(result.StartedAt) {
+			result.StartedAt = startedAt
+		}
+		if finishedAt.After(result.FinishedAt) {
+			result.FinishedAt = finishedAt
+		}
+		result.Duration += attempt.Duration
+		result.Sources = append(result.Sources, source)
+		if attempt.Status != messages.TestStepFinished_TestStepResult_PASSED {
+			comment := attempt.Pickle.Name + " (" + source + "): " + strings.ToLower(attempt.Status.String())
+			if attempt.Message != "" {
+				comment += "\n" + attempt.Message
+			}
+			if result.Comment != "" {
+				result.Comment += "\n\n"
+			}
+			result.Comment += comment
+		}
+	}
+}
+
+func (self *Formatter) source(pickle *messages.Pickle) string {
+	return pickle.Uri + ":" + strconv.FormatUint(uint64(self.query.Line(pickle.AstNodeIds)), 10)
+}
+
+func (self *Formatter) export(finishedAt time.Time, stdout io.Writer) error {
+	if len(self.results) == 0 {
+		return nil
+	}
+	payload, err := self.Adapter.Payload(&Run{
+		StartedAt:  goTime(self.query.TestRunStarted().GetTimestamp()),
+		FinishedAt: finishedAt,
+		Results:    self.results,
+	})
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	if self.Uploader == nil {
+		_, err = stdout.Write(append(b, '\n'))
+		return err
+	}
+	return self.Uploader.Upload(b)
+}
+
+// testIds are the ids of the tests a pickle is tagged with
+func testIds(pickle *messages.Pickle) []string {
+	var ids []string
+	for _, tag := range pickle.GetTags() {
+		if strings.HasPrefix(tag.Name, tagPrefix) && len(tag.Name) > len(tagPrefix) {
+			ids = append(ids, strings.TrimPrefix(tag.Name, tagPrefix))
+		}
+	}
+	return ids
+}
+
+func goTime(timestamp *messages.Timestamp) time.Time {
+	if timestamp == nil {
+		return time.Time{}
+	}
+	return messages.TimestampToGoTime(*timestamp).UTC()
+}
@@ -0,0 +1,210 @@
+package tms
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cucumber/messages-go/v13"
+	"github.com/cucumber/query-go/querytest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCombinesTheScenariosOfATest(t *testing.T) {
+	uploader := &recordingUploader{}
+	err := (&Formatter{Adapter: &recordingAdapter{}, Uploader: uploader}).ProcessMessages(querytest.NewReader(t, querytest.FailingRun()), nil)
+	require.NoError(t, err)
+
+	var run Run
+	require.NoError(t, json.Unmarshal(uploader.payload, &run))
+	require.Equal(t, []*Result{
+		{
+			TestId:     "ABC-1",
+			Status:     messages.TestStepFinished_TestStepResult_FAILED,
+			StartedAt:  time.Unix(1597093202, 0).UTC(),
+			FinishedAt: time.Unix(1597093203, 4000000).UTC(),
+			Duration:   8 * time.Millisecond,
+			Comment:    "Eating 6 (features/eating.feature:18): failed\nexpected 0\n\tat eating.go:12",
+			Sources:    []string{"features/eating.feature:17", "features/eating.feature:18"},
+		},
+		{
+			TestId:     "ABC-2",
+			Status:     messages.TestStepFinished_TestStepResult_PASSED,
+			StartedAt:  time.Unix(1597093202, 0).UTC(),
+			FinishedAt: time.Unix(1597093202, 4000000).UTC(),
+			Duration:   4 * time.Millisecond,
+			Sources:    []string{"features/eating.feature:17"},
+		},
+	}, run.Results)
+	require.Equal(t, time.Unix(1597093200, 0).UTC(), run.StartedAt)
+	require.Equal(t, time.Unix(1597093204, 0).UTC(), run.FinishedAt)
+}
+
+func TestOnlyExportsTheLastAttempt(t *testing.T) {
+	var envelopes []*messages.Envelope
+	for _, envelope := range querytest.FailingRun() {
+		if started := envelope.GetTestCaseStarted(); started != nil && started.Id == "tcs2" {
+			envelopes = append(envelopes,
+				querytest.TestCaseStarted("tcs0", "tc2", 0),
+				querytest.TestStepFinished("tcs0", "ts5", messages.TestStepFinished_TestStepResult_FAILED, "flaky", true),
+				querytest.TestCaseFinished("tcs0"),
+			)
+		}
+		envelopes = append(envelopes, envelope)
+	}
+
+	uploader := &recordingUploader{}
+	err := (&Formatter{Adapter: &recordingAdapter{}, Uploader: uploader}).ProcessMessages(querytest.NewReader(t, envelopes), nil)
+	require.NoError(t, err)
+
+	var run Run
+	require.NoError(t, json.Unmarshal(uploader.payload, &run))
+	require.Equal(t, messages.TestStepFinished_TestStepResult_PASSED, run.Results[1].Status)
+	require.Equal(t, time.Unix(1597093202, 0).UTC(), run.Results[1].StartedAt)
+}
+
+func TestWritesThePayloadWithoutAnUploader(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	err := (&Formatter{Adapter: &Zephyr{}}).ProcessMessages(querytest.NewReader(t, querytest.FailingRun()), stdout)
+	require.NoError(t, err)
+	require.Equal(t, `{"version":1,"executions":[`+
+		`{"source":"features/eating.feature:17, features/eating.feature:18","result":"Failed","testCase":{"key":"ABC-1","comment":"Eating 6 (features/eating.feature:18): failed\nexpected 0\n\tat eating.go:12"}},`+
+		`{"source":"features/eating.feature:17","result":"Passed","testCase":{"key":"ABC-2"}}]}`+"\n", stdout.String())
+}
+
+func TestExportsNothingWithoutTaggedScenarios(t *testing.T) {
+	envelopes := querytest.FailingRun()
+	for _, envelope := range envelopes {
+		if pickle := envelope.GetPickle(); pickle != nil {
+			pickle.Tags = []*messages.Pickle_PickleTag{{Name: "@tms="}, {Name: "@slow"}}
+		}
+	}
+	uploader := &recordingUploader{}
+	stdout := &bytes.Buffer{}
+	err := (&Formatter{Adapter: &Zephyr{}, Uploader: uploader}).ProcessMessages(querytest.NewReader(t, envelopes), stdout)
+	require.NoError(t, err)
+	require.Nil(t, uploader.payload)
+	require.Empty(t, stdout.String())
+}
+
+func TestMakesTestRailResults(t *testing.T) {
+	payload, err := (&TestRail{}).Payload(&Run{Results: []*Result{
+		{TestId: "C12", Status: messages.TestStepFinished_TestStepResult_PASSED, Duration: 65 * time.Second},
+		{TestId: "34", Status: messages.TestStepFinished_TestStepResult_AMBIGUOUS, Duration: time.Millisecond, Comment: "boom"},
+		{TestId: "C56", Status: messages.TestStepFinished_TestStepResult_UNDEFINED},
+		{TestId: "C78", Status: messages.TestStepFinished_TestStepResult_SKIPPED, Duration: 2 * time.Hour},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, &TestRailResults{Results: []*TestRailResult{
+		{CaseId: 12, StatusId: TestRailPassed, Elapsed: "1m 5s"},
+		{CaseId: 34, StatusId: TestRailFailed, Elapsed: "1s", Comment: "boom"},
+		{CaseId: 56, StatusId: TestRailRetest},
+		{CaseId: 78, StatusId: TestRailBlocked, Elapsed: "2h"},
+	}}, payload)
+}
+
+func TestRejectsTestRailIdsThatAreNotCaseIds(t *testing.T) {
+	_, err := (&TestRail{}).Payload(&Run{Results: []*Result{{TestId: "ABC-1"}}})
+	require.EqualError(t, err, "not a TestRail case id: ABC-1")
+}
+
+func TestMakesXrayExecutions(t *testing.T) {
+	run := &Run{
+		StartedAt:  time.Unix(1597093200, 0).UTC(),
+		FinishedAt: time.Unix(1597093205, 0).UTC(),
+		Results: []*Result{
+			{
+				TestId:     "ABC-1",
+				Status:     messages.TestStepFinished_TestStepResult_FAILED,
+				StartedAt:  time.Unix(1597093201, 0).UTC(),
+				FinishedAt: time.Unix(1597093204, 0).UTC(),
+				Comment:    "boom",
+			},
+			{TestId: "ABC-2", Status: messages.TestStepFinished_TestStepResult_PENDING},
+		},
+	}
+
+	payload, err := (&Xray{TestPlanKey: "ABC-9", Summary: "Nightly"}).Payload(run)
+	require.NoError(t, err)
+	require.Equal(t, &XrayExecution{
+		Info: &XrayInfo{
+			Summary:     "Nightly",
+			TestPlanKey: "ABC-9",
+			StartDate:   "2020-08-10T21:00:00Z",
+			FinishDate:  "2020-08-10T21:00:05Z",
+		},
+		Tests: []*XrayTest{
+			{TestKey: "ABC-1", Start: "2020-08-10T21:00:01Z", Finish: "2020-08-10T21:00:04Z", Status: XrayFailed, Comment: "boom"},
+			{TestKey: "ABC-2", Status: XrayTodo},
+		},
+	}, payload)
+
+	payload, err = (&Xray{TestExecutionKey: "ABC-7", Summary: "Nightly"}).Payload(run)
+	require.NoError(t, err)
+	require.Equal(t, "ABC-7", payload.(*XrayExecution).TestExecutionKey)
+	require.Nil(t, payload.(*XrayExecution).Info)
+}
+
+func TestPostsJSON(t *testing.T) {
+	var request *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	uploader := &HTTPUploader{URL: server.URL, Header: http.Header{"Authorization": {"Bearer secret"}}}
+	require.NoError(t, uploader.Upload([]byte(`{"tests":[]}`)))
+	require.Equal(t, http.MethodPost, request.Method)
+	require.Equal(t, "application/json", request.Header.Get("Content-Type"))
+	require.Equal(t, "Bearer secret", request.Header.Get("Authorization"))
+	require.Equal(t, `{"tests":[]}`, string(body))
+}
+
+func TestUploadsAFormFile(t *testing.T) {
+	var fileName string
+	var content []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		fileName = header.Filename
+		content, _ = io.ReadAll(file)
+	}))
+	defer server.Close()
+
+	uploader := &HTTPUploader{URL: server.URL, FormField: "file"}
+	require.NoError(t, uploader.Upload([]byte(`{"version":1}`)))
+	require.Equal(t, "results.json", fileName)
+	require.Equal(t, `{"version":1}`, string(content))
+}
+
+func TestFailsWhenTheUploadIsRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad credentials", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	err := (&HTTPUploader{URL: server.URL}).Upload([]byte(`{}`))
+	require.EqualError(t, err, server.URL+" responded with 401 Unauthorized: bad credentials")
+}
+
+// recordingAdapter makes the run itself the payload
+type recordingAdapter struct{}
+
+func (self *recordingAdapter) Payload(run *Run) (interface{}, error) {
+	return run, nil
+}
+
+type recordingUploader struct {
+	payload []byte
+}
+
+func (self *recordingUploader) Upload(payload []byte) error {
+	self.payload = payload
+	return nil
+}
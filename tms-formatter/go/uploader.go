@@ -0,0 +1,80 @@
+package tms
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// HTTPUploader posts payloads to an endpoint of a test management system
+type HTTPUploader struct {
+	URL string
+	// Header is added to the requests, for authentication
+	Header http.Header
+	// FormField is the field of a multipart form the payload is uploaded in
+	// as a file. When it is empty, the payload is posted as JSON.
+	FormField string
+	// FileName is the name of the uploaded file, results.json by default
+	FileName string
+	Client   *http.Client
+}
+
+func (self *HTTPUploader) Upload(payload []byte) error {
+	body := bytes.NewBuffer(payload)
+	contentType := "application/json"
+	if self.FormField != "" {
+		var err error
+		body, contentType, err = self.form(payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	request, err := http.NewRequest(http.MethodPost, self.URL, body)
+	if err != nil {
+		return err
+	}
+	for name, values := range self.Header {
+		request.Header[name] = values
+	}
+	request.Header.Set("Content-Type", contentType)
+
+	client := self.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("%s responded with %s: %s", self.URL, response.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+func (self *HTTPUploader) form(payload []byte) (*bytes.Buffer, string, error) {
+	fileName := self.FileName
+	if fileName == "" {
+		fileName = "results.json"
+	}
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	file, err := form.CreateFormFile(self.FormField, fileName)
+	if err != nil {
+		return nil, "", err
+	}
+	_, err = file.Write(payload)
+	if err != nil {
+		return nil, "", err
+	}
+	err = form.Close()
+	if err != nil {
+		return nil, "", err
+	}
+	return body, form.FormDataContentType(), nil
+}
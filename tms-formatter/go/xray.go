@@ -0,0 +1,91 @@
+package tms
+
+import (
+	"time"
+
+	"github.com/cucumber/messages-go/v13"
+)
+
+// Xray statuses
+const (
+	XrayPassed = "PASSED"
+	XrayFailed = "FAILED"
+	XrayTodo   = "TODO"
+)
+
+// Xray makes the payload of Xray's import/execution endpoint. Tests are
+// tagged with the keys of Jira issues, like @tms=ABC-123.
+type Xray struct {
+	// TestExecutionKey is the key of the test execution to add the results
+	// to. A new test execution is made when it is empty.
+	TestExecutionKey string
+	// TestPlanKey is the key of the test plan of a new test execution
+	TestPlanKey string
+	// Summary is the summary of a new test execution
+	Summary string
+}
+
+type XrayExecution struct {
+	TestExecutionKey string      `json:"testExecutionKey,omitempty"`
+	Info             *XrayInfo   `json:"info,omitempty"`
+	Tests            []*XrayTest `json:"tests"`
+}
+
+type XrayInfo struct {
+	Summary     string `json:"summary,omitempty"`
+	TestPlanKey string `json:"testPlanKey,omitempty"`
+	StartDate   string `json:"startDate,omitempty"`
+	FinishDate  string `json:"finishDate,omitempty"`
+}
+
+type XrayTest struct {
+	TestKey string `json:"testKey"`
+	Start   string `json:"start,omitempty"`
+	Finish  string `json:"finish,omitempty"`
+	Status  string `json:"status"`
+	Comment string `json:"comment,omitempty"`
+}
+
+func (self *Xray) Payload(run *Run) (interface{}, error) {
+	payload := &XrayExecution{
+		TestExecutionKey: self.TestExecutionKey,
+		Tests:            make([]*XrayTest, 0, len(run.Results)),
+	}
+	if self.TestExecutionKey == "" {
+		payload.Info = &XrayInfo{
+			Summary:     self.Summary,
+			TestPlanKey: self.TestPlanKey,
+			StartDate:   xrayDate(run.StartedAt),
+			FinishDate:  xrayDate(run.FinishedAt),
+		}
+	}
+	for _, result := range run.Results {
+		payload.Tests = append(payload.Tests, &XrayTest{
+			TestKey: result.TestId,
+			Start:   xrayDate(result.StartedAt),
+			Finish:  xrayDate(result.FinishedAt),
+			Status:  xrayStatus(result.Status),
+			Comment: result.Comment,
+		})
+	}
+	return payload, nil
+}
+
+func xrayStatus(status messages.TestStepFinished_TestStepResult_Status) string {
+	switch status {
+	case messages.TestStepFinished_TestStepResult_PASSED:
+		return XrayPassed
+	case messages.TestStepFinished_TestStepResult_FAILED,
+		messages.TestStepFinished_TestStepResult_AMBIGUOUS:
+		return XrayFailed
+	default:
+		return XrayTodo
+	}
+}
+
+func xrayDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,65 @@
+package tms
+
+import (
+	"strings"
+
+	"github.com/cucumber/messages-go/v13"
+)
+
+// Zephyr Scale statuses
+const (
+	ZephyrPassed      = "Passed"
+	ZephyrFailed      = "Failed"
+	ZephyrNotExecuted = "Not Executed"
+)
+
+// Zephyr makes the payload of Zephyr Scale's custom format for automated
+// executions. Tests are tagged with the keys of test cases, like
+// @tms=ABC-T123.
+type Zephyr struct{}
+
+type ZephyrResults struct {
+	Version    int                `json:"version"`
+	Executions []*ZephyrExecution `json:"executions"`
+}
+
+type ZephyrExecution struct {
+	Source   string          `json:"source"`
+	Result   string          `json:"result"`
+	TestCase *ZephyrTestCase `json:"testCase"`
+}
+
+type ZephyrTestCase struct {
+	Key     string `json:"key"`
+	Comment string `json:"comment,omitempty"`
+}
+
+func (self *Zephyr) Payload(run *Run) (interface{}, error) {
+	payload := &ZephyrResults{
+		Version:    1,
+		Executions: make([]*ZephyrExecution, 0, len(run.Results)),
+	}
+	for _, result := range run.Results {
+		payload.Executions = append(payload.Executions, &ZephyrExecution{
+			Source: strings.Join(result.Sources, ", "),
+			Result: zephyrStatus(result.Status),
+			TestCase: &ZephyrTestCase{
+				Key:     result.TestId,
+				Comment: result.Comment,
+			},
+		})
+	}
+	return payload, nil
+}
+
+func zephyrStatus(status messages.TestStepFinished_TestStepResult_Status) string {
+	switch status {
+	case messages.TestStepFinished_TestStepResult_PASSED:
+		return ZephyrPassed
+	case messages.TestStepFinished_TestStepResult_FAILED,
+		messages.TestStepFinished_TestStepResult_AMBIGUOUS:
+		return ZephyrFailed
+	default:
+		return ZephyrNotExecuted
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"text/template"
+
+	fio "github.com/cucumber/messages-go/v13/io"
+	webhookFormatter "github.com/cucumber/webhook-formatter-go"
+	gio "github.com/gogo/protobuf/io"
+)
+
+var formatFlag = flag.String("format", "protobuf", "output format")
+var urlFlag = flag.String("url", os.Getenv("CUCUMBER_WEBHOOK_URL"), "webhook to post the summary to, or print it when empty")
+var payloadFlag = flag.String("payload", "json", "payload: json, slack or teams")
+var templateFlag = flag.String("template", "", "file with a template of the payload, instead of --payload")
+var reportURLFlag = flag.String("report-url", "", "link to the full report")
+var maxFailuresFlag = flag.Int("max-failures", 5, "number of failed scenarios listed, or 0 for all")
+
+func main() {
+	flag.Parse()
+
+	tmpl, err := newTemplate()
+	if err != nil {
+		log.Fatal("ERROR: ", err)
+	}
+	wf := &webhookFormatter.Formatter{
+		URL:         *urlFlag,
+		Template:    tmpl,
+		ReportURL:   *reportURLFlag,
+		MaxFailures: *maxFailuresFlag,
+	}
+	err = wf.ProcessMessages(newReader(os.Stdin), os.Stdout)
+	if err != nil {
+		log.Fatal("ERROR: ", err)
+	}
+}
+
+func newTemplate() (*template.Template, error) {
+	if *templateFlag != "" {
+		text, err := os.ReadFile(*templateFlag)
+		if err != nil {
+			return nil, err
+		}
+		return webhookFormatter.NewTemplate(string(text))
+	}
+	switch *payloadFlag {
+	case "json":
+		return nil, nil
+	case "slack":
+		return webhookFormatter.NewTemplate(webhookFormatter.SlackTemplate)
+	case "teams":
+		return webhookFormatter.NewTemplate(webhookFormatter.TeamsTemplate)
+	default:
+		return nil, fmt.Errorf("Unsupported payload: %s", *payloadFlag)
+	}
+}
+
+func newReader(in io.Reader) gio.ReadCloser {
+	var reader gio.ReadCloser
+	switch *formatFlag {
+	case "protobuf":
+		reader = gio.NewDelimitedReader(in, math.MaxInt32)
+	case "ndjson":
+		reader = fio.NewNdjsonReader(in)
+	default:
+		_, err := fmt.Fprintf(os.Stderr, "Unsupported format: %s\n", *formatFlag)
+		if err != nil {
+			log.Fatal("ERROR: ", err)
+		}
+	}
+	return reader
+}
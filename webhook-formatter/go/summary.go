@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cucumber/messages-go/v13"
+	"github.com/cucumber/query-go"
+)
+
+// Summary is what the payload template is rendered with
+type Summary struct {
+	// Success is true when TestRunFinished says so, or when no scenario
+	// failed, was pending, undefined or ambiguous and there is no Message.
+	// Not every Cucumber reports success.
+	Success bool `json:"success"`
+	// Message explains why the run failed, if it did so before the
+	// scenarios ran
+	Message string `json:"message,omitempty"`
+	// Total is the number of scenarios that ran, counting retried
+	// scenarios once
+	Total     int `json:"total"`
+	Passed    int `json:"passed"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+	Pending   int `json:"pending"`
+	Undefined int `json:"undefined"`
+	Ambiguous int `json:"ambiguous"`
+	Unknown   int `json:"unknown"`
+	// Duration is the time between TestRunStarted and TestRunFinished, in
+	// nanoseconds in JSON
+	Duration time.Duration `json:"duration"`
+	// Failures are the failed scenarios, in the order they finished
+	Failures  []*Failure `json:"failures"`
+	ReportURL string     `json:"reportUrl,omitempty"`
+}
+
+// Failure is a failed scenario
+type Failure struct {
+	Scenario string `json:"scenario"`
+	Uri      string `json:"uri"`
+	Line     uint32 `json:"line"`
+	// Step is the text of the step that failed, empty if a hook failed
+	Step    string `json:"step,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Title is a one line summary of the run
+func (self *Summary) Title() string {
+	verdict := "passed"
+	if !self.Success {
+		verdict = "failed"
+	}
+	counts := []string{fmt.Sprintf("%d passed", self.Passed), fmt.Sprintf("%d failed", self.Failed)}
+	for _, count := range []struct {
+		n    int
+		name string
+	}{
+		{self.Skipped, "skipped"},
+		{self.Pending, "pending"},
+		{self.Undefined, "undefined"},
+		{self.Ambiguous, "ambiguous"},
+		{self.Unknown, "unknown"},
+	} {
+		if count.n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", count.n, count.name))
+		}
+	}
+	return fmt.Sprintf("Cucumber run %s: %d scenarios (%s) in %s", verdict, self.Total, strings.Join(counts, ", "), self.Duration)
+}
+
+// Text is the Title followed by a line for every failure, and the link to
+// the report
+func (self *Summary) Text() string {
+	var text strings.Builder
+	text.WriteString(self.Title())
+	if self.Message != "" {
+		text.WriteString("\n" + self.Message)
+	}
+	for _, failure := range self.Failures {
+		fmt.Fprintf(&text, "\n- %s (%s:%d)", failure.Scenario, failure.Uri, failure.Line)
+		if failure.Step != "" {
+			fmt.Fprintf(&text, ": %s", failure.Step)
+		}
+		if failure.Message != "" {
+			fmt.Fprintf(&text, ": %s", firstLine(failure.Message))
+		}
+	}
+	if self.ReportURL != "" {
+		text.WriteString("\nReport: " + self.ReportURL)
+	}
+	return text.String()
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return strings.TrimSpace(s[:i])
+	}
+	return strings.TrimSpace(s)
+}
+
+// run collects the test case runs the Summary is made of
+type run struct {
+	query    *query.Query
+	finished []*query.TestCaseRun
+}
+
+func newRun() *run {
+	return &run{query: query.New()}
+}
+
+func (self *run) processMessage(envelope *messages.Envelope) {
+	self.query.Update(envelope)
+	if m, ok := envelope.Message.(*messages.Envelope_TestCaseFinished); ok {
+		testCaseRun := self.query.TestCaseRun(m.TestCaseFinished.TestCaseStartedId)
+		if testCaseRun != nil && !testCaseRun.WillBeRetried {
+			self.finished = append(self.finished, testCaseRun)
+		}
+	}
+}
+
+func (self *run) summary(finished *messages.TestRunFinished, maxFailures int) *Summary {
+	summary := &Summary{
+		Success:  finished.Success,
+		Message:  finished.Message,
+		Failures: make([]*Failure, 0),
+	}
+	if started := self.query.TestRunStarted(); started != nil && started.Timestamp != nil && finished.Timestamp != nil {
+		summary.Duration = messages.TimestampToGoTime(*finished.Timestamp).Sub(messages.TimestampToGoTime(*started.Timestamp))
+	}
+
+	for _, testCaseRun := range self.finished {
+		summary.Total++
+		switch testCaseRun.Status {
+		case messages.TestStepFinished_TestStepResult_PASSED:
+			summary.Passed++
+		case messages.TestStepFinished_TestStepResult_SKIPPED:
+			summary.Skipped++
+		case messages.TestStepFinished_TestStepResult_PENDING:
+			summary.Pending++
+		case messages.TestStepFinished_TestStepResult_UNDEFINED:
+			summary.Undefined++
+		case messages.TestStepFinished_TestStepResult_AMBIGUOUS:
+			summary.Ambiguous++
+		case messages.TestStepFinished_TestStepResult_FAILED:
+			summary.Failed++
+			if maxFailures == 0 || len(summary.Failures) < maxFailures {
+				summary.Failures = append(summary.Failures, self.failure(testCaseRun))
+			}
+		default:
+			summary.Unknown++
+		}
+	}
+	if !summary.Success && summary.Message == "" {
+		summary.Success = summary.Passed+summary.Skipped == summary.Total
+	}
+	return summary
+}
+
+func (self *run) failure(testCaseRun *query.TestCaseRun) *Failure {
+	failure := &Failure{Message: testCaseRun.Message}
+	if pickle := testCaseRun.Pickle; pickle != nil {
+		failure.Scenario = pickle.Name
+		failure.Uri = pickle.Uri
+		failure.Line = self.query.Line(pickle.AstNodeIds)
+	}
+	if testCaseRun.FailedStep != nil {
+		if step := self.query.PickleStep(testCaseRun.FailedStep.PickleStepId); step != nil {
+			failure.Step = step.Text
+		}
+	}
+	return failure
+}
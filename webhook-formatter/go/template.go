@@ -0,0 +1,31 @@
+package webhook
+
+import (
+	"encoding/json"
+	"text/template"
+)
+
+// SlackTemplate renders the payload of a Slack incoming webhook
+const SlackTemplate = `{"text": {{json .Text}}}`
+
+// TeamsTemplate renders the payload of a Microsoft Teams incoming webhook
+const TeamsTemplate = `{
+  "@type": "MessageCard",
+  "@context": "https://schema.org/extensions",
+  "themeColor": "{{if .Success}}2EB886{{else}}D00000{{end}}",
+  "summary": {{json .Title}},
+  "title": {{json .Title}},
+  "text": {{json .Text}}
+}`
+
+// NewTemplate parses the template of a payload. Besides the functions of
+// text/template, it can call json, which writes a value as JSON, so
+// strings are quoted and escaped.
+func NewTemplate(text string) (*template.Template, error) {
+	return template.New("payload").Funcs(template.FuncMap{"json": toJSON}).Parse(text)
+}
+
+func toJSON(value interface{}) (string, error) {
+	b, err := json.Marshal(value)
+	return string(b), err
+}
@@ -0,0 +1,100 @@
+/*
+Package webhook implements a Cucumber formatter that posts a summary of a
+test run to a webhook, such as a Slack or Microsoft Teams incoming webhook,
+when the run finishes.
+*/
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"text/template"
+
+	"github.com/cucumber/messages-go/v13"
+	gio "github.com/gogo/protobuf/io"
+)
+
+type Formatter struct {
+	// URL is the webhook the summary is posted to. When it is empty, the
+	// payload is written to STDOUT instead.
+	URL string
+	// Template renders the payload from a Summary. When it is nil, the
+	// payload is the Summary as JSON.
+	Template *template.Template
+	// ReportURL is a link to the full report, passed on to the Summary
+	ReportURL string
+	// MaxFailures is the number of failed scenarios listed in the Summary,
+	// all of them when it is 0
+	MaxFailures int
+	// Client posts the payload, http.DefaultClient when it is nil
+	Client *http.Client
+}
+
+// ProcessMessages posts a summary of the run when the TestRunFinished
+// message is read
+func (self *Formatter) ProcessMessages(reader gio.ReadCloser, stdout io.Writer) error {
+	run := newRun()
+	for {
+		envelope := &messages.Envelope{}
+		err := reader.ReadMsg(envelope)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		run.processMessage(envelope)
+
+		if m, ok := envelope.Message.(*messages.Envelope_TestRunFinished); ok {
+			summary := run.summary(m.TestRunFinished, self.MaxFailures)
+			summary.ReportURL = self.ReportURL
+			err = self.send(summary, stdout)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (self *Formatter) send(summary *Summary, stdout io.Writer) error {
+	payload, err := self.render(summary)
+	if err != nil {
+		return err
+	}
+	if self.URL == "" {
+		_, err = stdout.Write(append(payload, '\n'))
+		return err
+	}
+
+	client := self.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Post(self.URL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("webhook responded with %s: %s", response.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+func (self *Formatter) render(summary *Summary) ([]byte, error) {
+	if self.Template == nil {
+		return json.Marshal(summary)
+	}
+	var payload bytes.Buffer
+	err := self.Template.Execute(&payload, summary)
+	if err != nil {
+		return nil, err
+	}
+	return payload.Bytes(), nil
+}
@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cucumber/messages-go/v13"
+	"github.com/cucumber/query-go/querytest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostsTheSummaryAsJSON(t *testing.T) {
+	var contentType string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	formatter := &Formatter{URL: server.URL, ReportURL: "https://example.com/report"}
+	stdout := &bytes.Buffer{}
+	err := formatter.ProcessMessages(querytest.NewReader(t, querytest.FailingRun()), stdout)
+	require.NoError(t, err)
+
+	require.Equal(t, "application/json", contentType)
+	require.Empty(t, stdout.String())
+	summary := &Summary{}
+	require.NoError(t, json.Unmarshal(body, summary))
+	require.Equal(t, &Summary{
+		Success:   false,
+		Total:     3,
+		Passed:    1,
+		Failed:    1,
+		Undefined: 1,
+		Duration:  4000000000,
+		Failures: []*Failure{{
+			Scenario: "Eating 6",
+			Uri:      "features/eating.feature",
+			Line:     18,
+			Step:     "I eat 6",
+			Message:  "expected 0\n\tat eating.go:12",
+		}},
+		ReportURL: "https://example.com/report",
+	}, summary)
+}
+
+func TestCountsRetriedScenariosOnce(t *testing.T) {
+	envelopes := []*messages.Envelope{
+		querytest.TestCase("tc1", "p3", querytest.TestStep("ts1", "ps6", "")),
+		querytest.TestCaseStarted("tcs1", "tc1", 0),
+		querytest.TestStepFinished("tcs1", "ts1", messages.TestStepFinished_TestStepResult_FAILED, "flaky", true),
+		querytest.TestCaseFinished("tcs1"),
+		querytest.TestCaseStarted("tcs2", "tc1", 1),
+		querytest.TestStepFinished("tcs2", "ts1", messages.TestStepFinished_TestStepResult_PASSED, "", false),
+		querytest.TestCaseFinished("tcs2"),
+		querytest.TestRunFinished(true, ""),
+	}
+	stdout := &bytes.Buffer{}
+	err := (&Formatter{}).ProcessMessages(querytest.NewReader(t, envelopes), stdout)
+	require.NoError(t, err)
+
+	summary := &Summary{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), summary))
+	require.Equal(t, 1, summary.Total)
+	require.Equal(t, 1, summary.Passed)
+	require.Empty(t, summary.Failures)
+}
+
+func TestRendersTheSlackTemplate(t *testing.T) {
+	tmpl, err := NewTemplate(SlackTemplate)
+	require.NoError(t, err)
+	formatter := &Formatter{Template: tmpl, ReportURL: "https://example.com/report"}
+	stdout := &bytes.Buffer{}
+	err = formatter.ProcessMessages(querytest.NewReader(t, querytest.FailingRun()), stdout)
+	require.NoError(t, err)
+
+	payload := map[string]string{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &payload))
+	require.Equal(t,
+		"Cucumber run failed: 3 scenarios (1 passed, 1 failed, 1 undefined) in 4s\n"+
+			"- Eating 6 (features/eating.feature:18): I eat 6: expected 0\n"+
+			"Report: https://example.com/report",
+		payload["text"])
+}
+
+func TestRendersTheTeamsTemplate(t *testing.T) {
+	tmpl, err := NewTemplate(TeamsTemplate)
+	require.NoError(t, err)
+	stdout := &bytes.Buffer{}
+	err = (&Formatter{Template: tmpl}).ProcessMessages(querytest.NewReader(t, querytest.FailingRun()), stdout)
+	require.NoError(t, err)
+
+	payload := map[string]string{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &payload))
+	require.Equal(t, "MessageCard", payload["@type"])
+	require.Equal(t, "D00000", payload["themeColor"])
+	require.Equal(t, "Cucumber run failed: 3 scenarios (1 passed, 1 failed, 1 undefined) in 4s", payload["title"])
+}
+
+func TestLimitsTheFailures(t *testing.T) {
+	envelopes := []*messages.Envelope{
+		querytest.TestCase("tc1", "p3", querytest.TestStep("ts1", "ps6", "")),
+	}
+	for _, id := range []string{"tcs1", "tcs2", "tcs3"} {
+		envelopes = append(envelopes,
+			querytest.TestCaseStarted(id, "tc1", 0),
+			querytest.TestStepFinished(id, "ts1", messages.TestStepFinished_TestStepResult_FAILED, id, false),
+			querytest.TestCaseFinished(id),
+		)
+	}
+	envelopes = append(envelopes, querytest.TestRunFinished(false, ""))
+
+	stdout := &bytes.Buffer{}
+	err := (&Formatter{MaxFailures: 2}).ProcessMessages(querytest.NewReader(t, envelopes), stdout)
+	require.NoError(t, err)
+
+	summary := &Summary{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), summary))
+	require.Equal(t, 3, summary.Failed)
+	require.Len(t, summary.Failures, 2)
+	require.Equal(t, "tcs2", summary.Failures[1].Message)
+}
+
+func TestReturnsAnErrorWhenTheWebhookFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_payload", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := (&Formatter{URL: server.URL}).ProcessMessages(querytest.NewReader(t, querytest.FailingRun()), &bytes.Buffer{})
+	require.EqualError(t, err, "webhook responded with 400 Bad Request: invalid_payload")
+}